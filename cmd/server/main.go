@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"net/http"
 
@@ -24,11 +31,25 @@ import (
 	networkapi "github.com/rivernova/orcahub/internal/docker/networks/api"
 	networkdomain "github.com/rivernova/orcahub/internal/docker/networks/domain"
 
+	eventadapter "github.com/rivernova/orcahub/internal/docker/events/adapter"
+	eventapi "github.com/rivernova/orcahub/internal/docker/events/api"
+	eventdomain "github.com/rivernova/orcahub/internal/docker/events/domain"
+	eventmodel "github.com/rivernova/orcahub/internal/docker/events/model"
+
 	"github.com/rivernova/orcahub/internal/router"
 	systemapi "github.com/rivernova/orcahub/internal/system"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish
+// once a shutdown was signalled.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	// Background work stops on SIGINT or SIGTERM, and the server shuts down
+	// before the event history is closed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Containers
 	containerAdapt, err := containeradapter.NewContainerAdapterImpl()
 	if err != nil {
@@ -44,13 +65,57 @@ func main() {
 	}
 	imageService := imagedomain.NewImageServiceImpl(imageAdapt)
 	imageHandler := imageapi.NewHandler(imageService)
+	go imageService.WatchUpdates(ctx, getImageUpdateInterval())
+	if kind := os.Getenv("ORCAHUB_SCANNER"); kind != "" {
+		scanner, err := imageadapter.NewScanner(kind, os.Getenv("ORCAHUB_SCANNER_PATH"), os.Getenv("ORCAHUB_SCANNER_SERVER"))
+		if err != nil {
+			log.Fatalf("failed to create vulnerability scanner: %v", err)
+		}
+		if dir := os.Getenv("ORCAHUB_SCAN_REPORTS_DIR"); dir != "" {
+			if err := imageService.UseScanReportDir(dir); err != nil {
+				log.Fatalf("failed to load scan reports: %v", err)
+			}
+		}
+		imageService.StartScanner(ctx, scanner, getScanConcurrency())
+	}
+	if kind := os.Getenv("ORCAHUB_SIGNATURE_VERIFIER"); kind != "" {
+		verifier, err := imageadapter.NewVerifier(kind, os.Getenv("ORCAHUB_SIGNATURE_VERIFIER_PATH"), getVerifierOptions())
+		if err != nil {
+			log.Fatalf("failed to create signature verifier: %v", err)
+		}
+		imageService.UseVerifier(verifier)
+	}
+	if requireSignedImages() {
+		if os.Getenv("ORCAHUB_SIGNATURE_VERIFIER") == "" {
+			log.Fatalf("ORCAHUB_REQUIRE_SIGNED_IMAGES needs ORCAHUB_SIGNATURE_VERIFIER")
+		}
+		containerService.UseImagePolicy(imageService)
+	}
 
 	// Volumes
 	volumeAdapt, err := volumeadapter.NewVolumeAdapterImpl()
 	if err != nil {
 		log.Fatalf("failed to create volume adapter: %v", err)
 	}
+	if image := os.Getenv("ORCAHUB_VOLUME_HELPER_IMAGE"); image != "" {
+		volumeAdapt.UseHelperImage(image)
+	}
 	volumeService := volumedomain.NewVolumeServiceImpl(volumeAdapt)
+	if dir := os.Getenv("ORCAHUB_VOLUME_BACKUP_DIR"); dir != "" {
+		if err := volumeService.UseBackupDir(dir); err != nil {
+			log.Fatalf("failed to configure volume backups: %v", err)
+		}
+	}
+	if endpoint := os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_ENDPOINT"); endpoint != "" {
+		target, err := volumeadapter.NewS3Target(getS3Options(endpoint))
+		if err != nil {
+			log.Fatalf("failed to create s3 backup target: %v", err)
+		}
+		volumeService.UseBackupTarget("s3", target)
+	}
+	if err := volumeService.StartScheduler(ctx, os.Getenv("ORCAHUB_VOLUME_BACKUP_SCHEDULES_FILE")); err != nil {
+		log.Fatalf("failed to start volume backup scheduler: %v", err)
+	}
 	volumeHandler := volumeapi.NewHandler(volumeService)
 
 	// Networks
@@ -58,17 +123,47 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to create network adapter: %v", err)
 	}
+	if image := os.Getenv("ORCAHUB_NETWORK_HELPER_IMAGE"); image != "" {
+		networkAdapt.UseHelperImage(image)
+	}
+	if image := os.Getenv("ORCAHUB_NETWORK_CAPTURE_IMAGE"); image != "" {
+		networkAdapt.UseCaptureImage(image)
+	}
 	networkService := networkdomain.NewNetworkServiceImpl(networkAdapt)
+	networkService.UseHostRoutes(networkadapter.NewProcRoutes(getHostProc()))
 	networkHandler := networkapi.NewHandler(networkService)
 
+	// Events
+	eventAdapt, err := eventadapter.NewEventAdapterImpl()
+	if err != nil {
+		log.Fatalf("failed to create event adapter: %v", err)
+	}
+	eventService := eventdomain.NewEventServiceImpl(eventAdapt)
+	// Nothing may exit through log.Fatalf once the store is open, so it is
+	// always closed.
+	var eventStore *eventadapter.BoltStore
+	if path := os.Getenv("ORCAHUB_EVENTS_DB"); path != "" {
+		retention := getEventRetention()
+		eventStore, err = eventadapter.NewBoltStore(path)
+		if err != nil {
+			log.Fatalf("failed to open event history: %v", err)
+		}
+		eventService.UseStore(eventStore)
+		eventService.UseRetention(retention)
+		eventService.StartCollector(ctx)
+	}
+	eventHandler := eventapi.NewHandler(eventService)
+
 	// System
 	systemHandler := systemapi.NewHandler()
+	systemHandler.UseVolumePruner(volumeService)
 
 	r := router.SetupRouter(&router.Handlers{
 		Containers: containerHandler,
 		Images:     imageHandler,
 		Volumes:    volumeHandler,
 		Networks:   networkHandler,
+		Events:     eventHandler,
 		System:     systemHandler,
 	})
 
@@ -82,10 +177,31 @@ func main() {
 	})
 
 	port := getPort()
+	srv := &http.Server{Addr: ":" + port, Handler: r.Handler()}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down server: %v", err)
+		}
+	}()
+
 	log.Println("Starting OrcaHub server on :" + port)
-	if err := r.Run(":" + port); err != nil {
+	err = srv.ListenAndServe()
+	stop()
+	<-shutdown
+	if eventStore != nil {
+		if err := eventStore.Close(); err != nil {
+			log.Printf("failed to close event history: %v", err)
+		}
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("failed to start server: %v", err)
 	}
+	log.Println("OrcaHub server stopped")
 }
 
 func getPort() string {
@@ -94,3 +210,96 @@ func getPort() string {
 	}
 	return "3001"
 }
+
+func getHostProc() string {
+	if root := os.Getenv("ORCAHUB_HOST_PROC"); root != "" {
+		return root
+	}
+	return networkadapter.DefaultProcRoot
+}
+
+func getEventRetention() eventmodel.Retention {
+	retention := eventdomain.DefaultRetention
+	if raw := os.Getenv("ORCAHUB_EVENTS_RETENTION"); raw != "" {
+		age, err := time.ParseDuration(raw)
+		if err != nil || age < 0 {
+			log.Fatalf("invalid ORCAHUB_EVENTS_RETENTION %q", raw)
+		}
+		retention.MaxAge = age
+	}
+	if raw := os.Getenv("ORCAHUB_EVENTS_MAX"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			log.Fatalf("invalid ORCAHUB_EVENTS_MAX %q", raw)
+		}
+		retention.MaxEvents = n
+	}
+	return retention
+}
+
+func getImageUpdateInterval() time.Duration {
+	if raw := os.Getenv("ORCAHUB_IMAGE_UPDATE_INTERVAL"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid ORCAHUB_IMAGE_UPDATE_INTERVAL %q: %v", raw, err)
+		}
+		return interval
+	}
+	return 6 * time.Hour
+}
+
+func getScanConcurrency() int {
+	if raw := os.Getenv("ORCAHUB_SCAN_CONCURRENCY"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			log.Fatalf("invalid ORCAHUB_SCAN_CONCURRENCY %q", raw)
+		}
+		return n
+	}
+	return 2
+}
+
+func getVerifierOptions() imageadapter.VerifierOptions {
+	opts := imageadapter.VerifierOptions{
+		Key:        os.Getenv("ORCAHUB_COSIGN_KEY"),
+		Identity:   os.Getenv("ORCAHUB_COSIGN_IDENTITY"),
+		OIDCIssuer: os.Getenv("ORCAHUB_COSIGN_OIDC_ISSUER"),
+	}
+	for _, t := range strings.Split(os.Getenv("ORCAHUB_COSIGN_ATTESTATION_TYPES"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			opts.AttestationTypes = append(opts.AttestationTypes, t)
+		}
+	}
+	return opts
+}
+
+func getS3Options(endpoint string) volumeadapter.S3Options {
+	opts := volumeadapter.S3Options{
+		Endpoint:  endpoint,
+		Bucket:    os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_BUCKET"),
+		Prefix:    os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_PREFIX"),
+		Region:    os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_REGION"),
+		AccessKey: os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_ACCESS_KEY"),
+		SecretKey: os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_SECRET_KEY"),
+	}
+	if raw := os.Getenv("ORCAHUB_VOLUME_BACKUP_S3_INSECURE"); raw != "" {
+		insecure, err := strconv.ParseBool(raw)
+		if err != nil {
+			log.Fatalf("invalid ORCAHUB_VOLUME_BACKUP_S3_INSECURE %q", raw)
+		}
+		opts.Insecure = insecure
+	}
+	return opts
+}
+
+func requireSignedImages() bool {
+	raw := os.Getenv("ORCAHUB_REQUIRE_SIGNED_IMAGES")
+	if raw == "" {
+		return false
+	}
+	required, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Fatalf("invalid ORCAHUB_REQUIRE_SIGNED_IMAGES %q", raw)
+	}
+	return required
+}
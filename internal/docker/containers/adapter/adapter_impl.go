@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"archive/tar"
 	"bufio"
 	"bytes"
 	"context"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
@@ -401,3 +403,51 @@ func (a *ContainerAdapterImpl) Top(ctx context.Context, id string) (*model.TopRe
 		Processes: top.Processes,
 	}, nil
 }
+
+// DNS returns the container's DNS settings with its /etc/resolv.conf. The
+// file is copied out rather than exec'd, so it is read from stopped
+// containers and images without a shell too; a container that never started
+// may have none.
+func (a *ContainerAdapterImpl) DNS(ctx context.Context, id string) (*model.DNSConfig, error) {
+	c, err := a.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", id, err)
+	}
+	cfg := &model.DNSConfig{
+		Hostname:    c.Config.Hostname,
+		Domainname:  c.Config.Domainname,
+		NetworkMode: string(c.HostConfig.NetworkMode),
+		DNS:         c.HostConfig.DNS,
+		DNSSearch:   c.HostConfig.DNSSearch,
+		DNSOptions:  c.HostConfig.DNSOptions,
+		ExtraHosts:  c.HostConfig.ExtraHosts,
+		Networks:    make(map[string]model.NetworkDNS),
+	}
+	if c.NetworkSettings != nil {
+		for name, n := range c.NetworkSettings.Networks {
+			if n == nil {
+				continue
+			}
+			cfg.Networks[name] = model.NetworkDNS{Aliases: n.Aliases, DNSNames: n.DNSNames}
+		}
+	}
+
+	reader, _, err := a.client.CopyFromContainer(ctx, c.ID, "/etc/resolv.conf")
+	if cerrdefs.IsNotFound(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy resolv.conf from %s: %w", id, err)
+	}
+	defer reader.Close()
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return nil, fmt.Errorf("failed to read resolv.conf of %s: %w", id, err)
+	}
+	content, err := io.ReadAll(io.LimitReader(tr, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resolv.conf of %s: %w", id, err)
+	}
+	cfg.ResolvConf = string(content)
+	return cfg, nil
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	mappers "github.com/rivernova/orcahub/internal/docker/containers/api/mappers"
 	requests "github.com/rivernova/orcahub/internal/docker/containers/api/requests"
@@ -46,7 +48,14 @@ func (h *Handler) Create(c *gin.Context) {
 	}
 	result, err := h.service.Create(c.Request.Context(), mappers.ToDomainContainer(req))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrImageRejected):
+			status = http.StatusForbidden
+		case cerrdefs.IsNotFound(err):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, responses.CreateContainerResponse{ID: result.ID})
@@ -199,6 +208,20 @@ func (h *Handler) Kill(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "signal sent"})
 }
 
+func (h *Handler) DNS(c *gin.Context) {
+	id := c.Param("id")
+	cfg, err := h.service.DNS(c.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if cerrdefs.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToDNSConfigResponse(cfg))
+}
+
 func (h *Handler) Top(c *gin.Context) {
 	id := c.Param("id")
 	result, err := h.service.Top(c.Request.Context(), id)
@@ -5,12 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	containerapi "github.com/rivernova/orcahub/internal/docker/containers/api"
+	"github.com/rivernova/orcahub/internal/docker/containers/domain"
 	"github.com/rivernova/orcahub/internal/docker/containers/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -94,6 +97,14 @@ func (m *mockService) Top(ctx context.Context, id string) (*model.TopResult, err
 	return args.Get(0).(*model.TopResult), args.Error(1)
 }
 
+func (m *mockService) DNS(ctx context.Context, id string) (*model.DNSConfig, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DNSConfig), args.Error(1)
+}
+
 func setupRouter(svc *mockService) *gin.Engine {
 	r := gin.New()
 	h := containerapi.NewHandler(svc)
@@ -108,6 +119,7 @@ func setupRouter(svc *mockService) *gin.Engine {
 	r.GET("/containers/:id/stats", h.Stats)
 	r.POST("/containers/:id/exec", h.Exec)
 	r.POST("/containers/prune", h.Prune)
+	r.GET("/containers/:id/dns", h.DNS)
 	return r
 }
 
@@ -199,6 +211,38 @@ func TestHandler_Create_OK(t *testing.T) {
 	assert.Equal(t, "newid", resp["id"])
 }
 
+func TestHandler_Create_ImageRejected(t *testing.T) {
+	svc := &mockService{}
+	r := setupRouter(svc)
+
+	svc.On("Create", mock.Anything, mock.AnythingOfType("model.Container")).
+		Return(nil, fmt.Errorf("%w: unsigned", domain.ErrImageRejected))
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "my-app", "image": "nginx:latest"})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/containers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHandler_Create_ImageNotFound(t *testing.T) {
+	svc := &mockService{}
+	r := setupRouter(svc)
+
+	svc.On("Create", mock.Anything, mock.AnythingOfType("model.Container")).
+		Return(nil, fmt.Errorf("failed to inspect image: %w", cerrdefs.ErrNotFound))
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "my-app", "image": "nginx:latest"})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/containers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestHandler_Create_BadRequest(t *testing.T) {
 	svc := &mockService{}
 	r := setupRouter(svc)
@@ -332,3 +376,42 @@ func TestHandler_Prune_OK(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, float64(1024), resp["space_reclaimed"])
 }
+
+func TestHandler_DNS_OK(t *testing.T) {
+	svc := &mockService{}
+	r := setupRouter(svc)
+
+	svc.On("DNS", mock.Anything, "abc123").Return(&model.DNSConfig{
+		Hostname:        "abc123",
+		NetworkMode:     "app",
+		ExtraHosts:      []string{"host.docker.internal:host-gateway"},
+		Networks:        map[string]model.NetworkDNS{"app": {Aliases: []string{"api"}, DNSNames: []string{"web", "abc123", "api"}}},
+		Nameservers:     []string{"127.0.0.11"},
+		EmbeddedDNS:     true,
+		ExternalServers: []string{"1.1.1.1"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers/abc123/dns", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["embedded_dns"])
+	assert.Equal(t, []interface{}{}, resp["dns"])
+	assert.Equal(t, []interface{}{"1.1.1.1"}, resp["external_servers"])
+	network := resp["networks"].(map[string]interface{})["app"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"api"}, network["aliases"])
+}
+
+func TestHandler_DNS_NotFound(t *testing.T) {
+	svc := &mockService{}
+	r := setupRouter(svc)
+
+	svc.On("DNS", mock.Anything, "ghost").Return(nil, fmt.Errorf("failed to inspect container ghost: %w", cerrdefs.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers/ghost/dns", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
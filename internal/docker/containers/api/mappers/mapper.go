@@ -119,3 +119,37 @@ func ToStatsResponse(s *model.ContainerStats) *responses.StatsResponse {
 		PIDs:          s.PIDs,
 	}
 }
+
+func ToDNSConfigResponse(d *model.DNSConfig) responses.DNSConfigResponse {
+	networks := make(map[string]responses.NetworkDNSResponse, len(d.Networks))
+	for name, n := range d.Networks {
+		networks[name] = responses.NetworkDNSResponse{
+			Aliases:  nonNil(n.Aliases),
+			DNSNames: nonNil(n.DNSNames),
+		}
+	}
+	return responses.DNSConfigResponse{
+		Hostname:        d.Hostname,
+		Domainname:      d.Domainname,
+		NetworkMode:     d.NetworkMode,
+		DNS:             nonNil(d.DNS),
+		DNSSearch:       nonNil(d.DNSSearch),
+		DNSOptions:      nonNil(d.DNSOptions),
+		ExtraHosts:      nonNil(d.ExtraHosts),
+		Networks:        networks,
+		ResolvConf:      d.ResolvConf,
+		Nameservers:     nonNil(d.Nameservers),
+		Search:          nonNil(d.Search),
+		Options:         nonNil(d.Options),
+		EmbeddedDNS:     d.EmbeddedDNS,
+		ExternalServers: nonNil(d.ExternalServers),
+	}
+}
+
+// nonNil keeps empty lists as [] rather than null in responses.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
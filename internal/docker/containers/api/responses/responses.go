@@ -51,6 +51,28 @@ type NetworkEndpoint struct {
 	MacAddress string `json:"mac_address"`
 }
 
+type DNSConfigResponse struct {
+	Hostname        string                        `json:"hostname"`
+	Domainname      string                        `json:"domainname,omitempty"`
+	NetworkMode     string                        `json:"network_mode"`
+	DNS             []string                      `json:"dns"`
+	DNSSearch       []string                      `json:"dns_search"`
+	DNSOptions      []string                      `json:"dns_options"`
+	ExtraHosts      []string                      `json:"extra_hosts"`
+	Networks        map[string]NetworkDNSResponse `json:"networks"`
+	ResolvConf      string                        `json:"resolv_conf"`
+	Nameservers     []string                      `json:"nameservers"`
+	Search          []string                      `json:"search"`
+	Options         []string                      `json:"options"`
+	EmbeddedDNS     bool                          `json:"embedded_dns"`
+	ExternalServers []string                      `json:"external_servers"`
+}
+
+type NetworkDNSResponse struct {
+	Aliases  []string `json:"aliases"`
+	DNSNames []string `json:"dns_names"`
+}
+
 type StatsResponse struct {
 	CPUPercent    float64 `json:"cpu_percent"`
 	MemoryUsage   uint64  `json:"memory_usage"`
@@ -28,6 +28,7 @@ func Register(rg *gin.RouterGroup, handler *api.Handler) {
 		containers.GET("/:id/logs", handler.Logs)
 		containers.GET("/:id/stats", handler.Stats)
 		containers.GET("/:id/top", handler.Top)
+		containers.GET("/:id/dns", handler.DNS)
 		containers.POST("/:id/exec", handler.Exec)
 
 		// Maintenance
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/containers/model"
+	"github.com/rivernova/orcahub/internal/docker/dns"
+)
+
+func (s *ContainerServiceImpl) DNS(ctx context.Context, id string) (*model.DNSConfig, error) {
+	cfg, err := s.adapter.DNS(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parseResolvConf(cfg)
+	return cfg, nil
+}
+
+// parseResolvConf fills the effective settings from cfg.ResolvConf. Docker
+// records the upstream servers of the embedded resolver in a comment, either
+// "# ExtServers: [8.8.8.8]" or, since Engine 25, "# ExtServers: [host(8.8.8.8)]".
+func parseResolvConf(cfg *model.DNSConfig) {
+	for _, line := range strings.Split(cfg.ResolvConf, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "# ExtServers:"); ok {
+			rest = strings.Trim(strings.TrimSpace(rest), "[]")
+			for _, server := range strings.Fields(rest) {
+				server = strings.TrimSuffix(strings.TrimPrefix(server, "host("), ")")
+				cfg.ExternalServers = append(cfg.ExternalServers, server)
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			cfg.Nameservers = append(cfg.Nameservers, fields[1])
+			if fields[1] == dns.EmbeddedResolver {
+				cfg.EmbeddedDNS = true
+			}
+		case "search", "domain":
+			// The last search or domain line wins.
+			cfg.Search = fields[1:]
+		case "options":
+			cfg.Options = append(cfg.Options, fields[1:]...)
+		}
+	}
+}
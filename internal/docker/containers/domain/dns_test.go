@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+
+	model "github.com/rivernova/orcahub/internal/docker/containers/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResolvConf_EmbeddedResolver(t *testing.T) {
+	cfg := &model.DNSConfig{ResolvConf: `# Generated by Docker Engine.
+# This file can be edited; Docker Engine will not make further changes once it
+# has been modified.
+
+nameserver 127.0.0.11
+search corp.example.com example.com
+options ndots:0 timeout:2
+
+# Based on host file: '/etc/resolv.conf' (internal resolver)
+# ExtServers: [host(192.168.1.1) host(1.1.1.1)]
+# Overrides: []
+# Option ndots from: internal
+`}
+	parseResolvConf(cfg)
+
+	assert.True(t, cfg.EmbeddedDNS)
+	assert.Equal(t, []string{"127.0.0.11"}, cfg.Nameservers)
+	assert.Equal(t, []string{"corp.example.com", "example.com"}, cfg.Search)
+	assert.Equal(t, []string{"ndots:0", "timeout:2"}, cfg.Options)
+	assert.Equal(t, []string{"192.168.1.1", "1.1.1.1"}, cfg.ExternalServers)
+}
+
+func TestParseResolvConf_DefaultBridge(t *testing.T) {
+	cfg := &model.DNSConfig{ResolvConf: "domain lan\nnameserver 8.8.8.8\nnameserver 8.8.4.4\n; comment\nsearch example.com\n"}
+	parseResolvConf(cfg)
+
+	assert.False(t, cfg.EmbeddedDNS)
+	assert.Equal(t, []string{"8.8.8.8", "8.8.4.4"}, cfg.Nameservers)
+	assert.Equal(t, []string{"example.com"}, cfg.Search)
+	assert.Empty(t, cfg.ExternalServers)
+}
+
+func TestParseResolvConf_Empty(t *testing.T) {
+	cfg := &model.DNSConfig{}
+	parseResolvConf(cfg)
+
+	assert.Empty(t, cfg.Nameservers)
+	assert.False(t, cfg.EmbeddedDNS)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrImageRejected = errors.New("image rejected by policy")
+
+// imageTagLabel keeps the tag a pinned container was requested with. The
+// image update checker reads it, as Config.Image only holds the digest.
+const imageTagLabel = "io.orcahub.image-tag"
+
+// ImagePolicy decides whether containers may be created from an image. An
+// admitted image is returned as the immutable reference that was checked.
+// Rejected tells a refusal apart from a failure to evaluate the policy, such
+// as a missing image or an unreachable registry.
+type ImagePolicy interface {
+	Admit(ctx context.Context, image string) (string, error)
+	Rejected(err error) bool
+}
+
+// admit checks image against the policy and returns the reference the
+// container must be created from.
+func (s *ContainerServiceImpl) admit(ctx context.Context, image string) (string, error) {
+	if s.policy == nil {
+		return image, nil
+	}
+	pinned, err := s.policy.Admit(ctx, image)
+	if err != nil {
+		if s.policy.Rejected(err) {
+			return "", fmt.Errorf("%w: %w", ErrImageRejected, err)
+		}
+		return "", err
+	}
+	return pinned, nil
+}
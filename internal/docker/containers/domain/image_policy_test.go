@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/containers/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/containers/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnsigned = errors.New("unsigned")
+
+type fakePolicy struct {
+	pinned string
+	err    error
+}
+
+func (p fakePolicy) Admit(ctx context.Context, image string) (string, error) {
+	return p.pinned, p.err
+}
+
+func (p fakePolicy) Rejected(err error) bool {
+	return errors.Is(err, errUnsigned)
+}
+
+// createAdapter records the container passed to Create.
+type createAdapter struct {
+	adapter.ContainerAdapter
+	created model.Container
+}
+
+func (a *createAdapter) Create(ctx context.Context, c model.Container) (*model.Container, error) {
+	a.created = c
+	return &model.Container{ID: "newid"}, nil
+}
+
+func TestCreate_PinnedImageKeepsTag(t *testing.T) {
+	a := &createAdapter{}
+	s := NewContainerServiceImpl(a)
+	s.UseImagePolicy(fakePolicy{pinned: "nginx@sha256:abc"})
+
+	labels := map[string]string{"team": "web"}
+	_, err := s.Create(context.Background(), model.Container{Image: "nginx:latest", Labels: labels})
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx@sha256:abc", a.created.Image)
+	assert.Equal(t, map[string]string{"team": "web", imageTagLabel: "nginx:latest"}, a.created.Labels)
+	assert.Equal(t, map[string]string{"team": "web"}, labels)
+}
+
+func TestAdmit_Rejected(t *testing.T) {
+	s := &ContainerServiceImpl{policy: fakePolicy{err: errUnsigned}}
+
+	_, err := s.admit(context.Background(), "nginx:latest")
+	assert.ErrorIs(t, err, ErrImageRejected)
+	assert.ErrorIs(t, err, errUnsigned)
+}
+
+func TestAdmit_PolicyFailure(t *testing.T) {
+	lookup := errors.New("no such image")
+	s := &ContainerServiceImpl{policy: fakePolicy{err: lookup}}
+
+	_, err := s.admit(context.Background(), "nginx:latest")
+	assert.ErrorIs(t, err, lookup)
+	assert.NotErrorIs(t, err, ErrImageRejected)
+}
+
+func TestAdmit_NoPolicy(t *testing.T) {
+	s := &ContainerServiceImpl{}
+
+	image, err := s.admit(context.Background(), "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx:latest", image)
+}
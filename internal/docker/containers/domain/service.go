@@ -23,4 +23,5 @@ type ContainerService interface {
 	Rename(ctx context.Context, id string, name string) error
 	Kill(ctx context.Context, id string, signal string) error
 	Top(ctx context.Context, id string) (*model.TopResult, error)
+	DNS(ctx context.Context, id string) (*model.DNSConfig, error)
 }
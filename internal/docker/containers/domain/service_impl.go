@@ -9,6 +9,7 @@ import (
 
 type ContainerServiceImpl struct {
 	adapter adapter.ContainerAdapter
+	policy  ImagePolicy
 }
 
 func NewContainerServiceImpl(adapter adapter.ContainerAdapter) *ContainerServiceImpl {
@@ -24,9 +25,27 @@ func (s *ContainerServiceImpl) Inspect(ctx context.Context, id string) (*model.C
 }
 
 func (s *ContainerServiceImpl) Create(ctx context.Context, container model.Container) (*model.Container, error) {
+	image, err := s.admit(ctx, container.Image)
+	if err != nil {
+		return nil, err
+	}
+	if image != container.Image {
+		labels := make(map[string]string, len(container.Labels)+1)
+		for k, v := range container.Labels {
+			labels[k] = v
+		}
+		labels[imageTagLabel] = container.Image
+		container.Labels = labels
+		container.Image = image
+	}
 	return s.adapter.Create(ctx, container)
 }
 
+// UseImagePolicy makes Create check every image against policy first.
+func (s *ContainerServiceImpl) UseImagePolicy(policy ImagePolicy) {
+	s.policy = policy
+}
+
 func (s *ContainerServiceImpl) Delete(ctx context.Context, id string) error {
 	return s.adapter.Delete(ctx, id)
 }
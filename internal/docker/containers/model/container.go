@@ -85,3 +85,30 @@ type TopResult struct {
 	Titles    []string   `json:"titles"`
 	Processes [][]string `json:"processes"`
 }
+
+// DNSConfig is how a container resolves names: the settings it was created
+// with and the resolv.conf it ended up with. On user-defined networks the
+// nameserver is Docker's embedded resolver, which forwards anything it does
+// not know to ExternalServers.
+type DNSConfig struct {
+	Hostname        string
+	Domainname      string
+	NetworkMode     string
+	DNS             []string
+	DNSSearch       []string
+	DNSOptions      []string
+	ExtraHosts      []string
+	Networks        map[string]NetworkDNS
+	ResolvConf      string // empty when it could not be read
+	Nameservers     []string
+	Search          []string
+	Options         []string
+	EmbeddedDNS     bool
+	ExternalServers []string
+}
+
+// NetworkDNS lists the names a container answers to on one network.
+type NetworkDNS struct {
+	Aliases  []string
+	DNSNames []string
+}
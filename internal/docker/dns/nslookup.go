@@ -0,0 +1,51 @@
+// Package dns holds what the containers and networks resources share about
+// name resolution inside containers: Docker's embedded resolver and the
+// output of the busybox nslookup that probes run.
+package dns
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// EmbeddedResolver is the address of Docker's DNS server inside containers
+// on user-defined networks.
+const EmbeddedResolver = "127.0.0.11"
+
+// ParseNslookup returns the addresses in the answer section of busybox
+// nslookup output, skipping the server's own address above it.
+func ParseNslookup(out string) []string {
+	var addrs []string
+	answer := false
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Name:") {
+			answer = true
+			continue
+		}
+		if !answer || !strings.HasPrefix(line, "Address") {
+			continue
+		}
+		// "Address: 172.18.0.2" or the older "Address 1: 172.18.0.2 web"
+		_, rest, _ := strings.Cut(line, ":")
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if addr, err := netip.ParseAddr(fields[0]); err == nil && !slices.Contains(addrs, addr.String()) {
+			addrs = append(addrs, addr.String())
+		}
+	}
+	return addrs
+}
+
+// NslookupServer returns the resolver busybox nslookup reports having asked.
+func NslookupServer(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Server:"); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package dns_test
+
+import (
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNslookup(t *testing.T) {
+	out := `Server:		127.0.0.11
+Address:	127.0.0.11:53
+
+Non-authoritative answer:
+Name:	web
+Address: 172.18.0.2
+Name:	web
+Address: 172.18.0.2
+Address: fd00::2
+`
+	assert.Equal(t, []string{"172.18.0.2", "fd00::2"}, dns.ParseNslookup(out))
+	assert.Equal(t, "127.0.0.11", dns.NslookupServer(out))
+}
+
+func TestParseNslookup_OldBusybox(t *testing.T) {
+	out := `Server:    127.0.0.11
+Address 1: 127.0.0.11
+
+Name:      web
+Address 1: 172.18.0.2 web.app
+`
+	assert.Equal(t, []string{"172.18.0.2"}, dns.ParseNslookup(out))
+}
+
+func TestParseNslookup_NoAnswer(t *testing.T) {
+	out := "Server:\t\t127.0.0.11\nAddress:\t127.0.0.11:53\n\n** server can't find ghost: NXDOMAIN\n"
+	assert.Empty(t, dns.ParseNslookup(out))
+}
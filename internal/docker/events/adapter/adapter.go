@@ -0,0 +1,34 @@
+package adapter
+
+import (
+	"context"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+type EventAdapter interface {
+	// Subscribe streams daemon events, first replaying those since since
+	// that the daemon still buffers when since is set. The error channel
+	// receives once, when the stream ends.
+	Subscribe(ctx context.Context, since time.Time) (<-chan model.Event, <-chan error)
+}
+
+// EventStore persists events, keyed by time so ranges scan in order.
+type EventStore interface {
+	// Append stores events. Storing the same event twice keeps one copy,
+	// so overlapping replays are harmless.
+	Append(ctx context.Context, events []model.Event) error
+	// Scan returns up to limit events from since to until, inclusive and
+	// newest first, that match accepts. Zero times leave the range open.
+	// A non-nil before resumes below the event it names. next names the
+	// last event returned while older matching events remain, and is nil
+	// once they are exhausted.
+	Scan(ctx context.Context, since, until time.Time, before []byte, limit int, match func(model.Event) bool) (events []model.Event, next []byte, err error)
+	// Latest returns the time of the newest event, zero when empty.
+	Latest(ctx context.Context) (time.Time, error)
+	// Prune deletes events before before, then the oldest beyond keep when
+	// keep is positive, and returns how many it deleted.
+	Prune(ctx context.Context, before time.Time, keep int) (int, error)
+	Close() error
+}
@@ -0,0 +1,69 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+type EventAdapterImpl struct {
+	client *client.Client
+}
+
+func NewEventAdapterImpl() (*EventAdapterImpl, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	return &EventAdapterImpl{client: cli}, nil
+}
+
+var _ EventAdapter = (*EventAdapterImpl)(nil)
+
+func (a *EventAdapterImpl) Subscribe(ctx context.Context, since time.Time) (<-chan model.Event, <-chan error) {
+	opts := events.ListOptions{}
+	if !since.IsZero() {
+		opts.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+	msgs, errs := a.client.Events(ctx, opts)
+
+	out := make(chan model.Event)
+	outErr := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case m := <-msgs:
+				select {
+				case out <- toEvent(m):
+				case <-ctx.Done():
+					outErr <- ctx.Err()
+					return
+				}
+			case err := <-errs:
+				outErr <- fmt.Errorf("event stream ended: %w", err)
+				return
+			case <-ctx.Done():
+				outErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, outErr
+}
+
+func toEvent(m events.Message) model.Event {
+	return model.Event{
+		Type:       string(m.Type),
+		Action:     string(m.Action),
+		ActorID:    m.Actor.ID,
+		ActorName:  m.Actor.Attributes["name"],
+		Attributes: m.Actor.Attributes,
+		Scope:      m.Scope,
+		Time:       time.Unix(0, m.TimeNano),
+	}
+}
@@ -0,0 +1,181 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+var eventsBucket = []byte("events")
+
+// BoltStore keeps events in a bbolt file. Keys are the event time in
+// nanoseconds, big-endian so they sort by time, followed by a hash of the
+// event that tells apart events from the same nanosecond and makes
+// re-appending one a no-op.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+var _ EventStore = (*BoltStore)(nil)
+
+// NewBoltStore opens or creates the store at path. It fails rather than
+// waits when another process has the file open.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event store %s: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(eventsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise event store %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Append(ctx context.Context, events []model.Event) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(eventsBucket)
+		for _, e := range events {
+			value, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(eventKey(e.Time, value), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store events: %w", err)
+	}
+	return nil
+}
+
+// Scan names events by their key, so a page ends exactly after its last
+// event even when others share its nanosecond.
+func (s *BoltStore) Scan(ctx context.Context, since, until time.Time, before []byte, limit int, match func(model.Event) bool) ([]model.Event, []byte, error) {
+	// Everything below bound is in range.
+	var bound []byte
+	if !until.IsZero() {
+		bound = timeKey(until.UnixNano() + 1)
+	}
+	if before != nil && (bound == nil || bytes.Compare(before, bound) < 0) {
+		bound = before
+	}
+	var result []model.Event
+	var next []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(eventsBucket).Cursor()
+		var k, v []byte
+		if bound == nil {
+			k, v = c.Last()
+		} else if k, v = c.Seek(bound); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		var last []byte
+		for ; k != nil; k, v = c.Prev() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !since.IsZero() && keyTime(k) < since.UnixNano() {
+				break
+			}
+			var e model.Event
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("corrupt event %x: %w", k, err)
+			}
+			if match != nil && !match(e) {
+				continue
+			}
+			// One match past the limit only tells that there is more.
+			if limit > 0 && len(result) == limit {
+				next = last
+				break
+			}
+			result = append(result, e)
+			// Keys are only valid for the life of the transaction.
+			last = bytes.Clone(k)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan events: %w", err)
+	}
+	return result, next, nil
+}
+
+func (s *BoltStore) Latest(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if k, _ := tx.Bucket(eventsBucket).Cursor().Last(); k != nil {
+			latest = time.Unix(0, keyTime(k))
+		}
+		return nil
+	})
+	return latest, err
+}
+
+func (s *BoltStore) Prune(ctx context.Context, before time.Time, keep int) (int, error) {
+	deleted := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(eventsBucket)
+		excess := 0
+		if keep > 0 {
+			excess = b.Stats().KeyN - keep
+		}
+		// Deleting through a cursor skips the following key, so collect
+		// first.
+		var expired [][]byte
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			if (before.IsZero() || keyTime(k) >= before.UnixNano()) && len(expired) >= excess {
+				break
+			}
+			expired = append(expired, k)
+		}
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune events: %w", err)
+	}
+	return deleted, nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func timeKey(nanos int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(nanos))
+	return key
+}
+
+func eventKey(t time.Time, value []byte) []byte {
+	h := fnv.New64a()
+	h.Write(value)
+	return h.Sum(timeKey(t.UnixNano()))
+}
+
+func keyTime(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[:8]))
+}
@@ -0,0 +1,151 @@
+package adapter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *BoltStore {
+	t.Helper()
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "events.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func at(sec int) time.Time {
+	return time.Unix(1700000000+int64(sec), 0)
+}
+
+func actions(events []model.Event) []string {
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		result = append(result, e.Action)
+	}
+	return result
+}
+
+func TestBoltStore_AppendAndScan(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Append(ctx, []model.Event{
+		{Type: "container", Action: "create", ActorID: "c1", Time: at(0)},
+		{Type: "container", Action: "start", ActorID: "c1", Time: at(1)},
+		{Type: "container", Action: "die", ActorID: "c1", Time: at(2), Attributes: map[string]string{"exitCode": "137"}},
+		// Same nanosecond as the die, different event.
+		{Type: "network", Action: "disconnect", ActorID: "n1", Time: at(2)},
+		{Type: "container", Action: "restart", ActorID: "c1", Time: at(3)},
+	}))
+
+	all, _, err := store.Scan(ctx, time.Time{}, time.Time{}, nil, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+	assert.Equal(t, "restart", all[0].Action)
+	assert.Equal(t, "create", all[4].Action)
+
+	ranged, _, err := store.Scan(ctx, at(1), at(2), nil, 0, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"start", "die", "disconnect"}, actions(ranged))
+
+	limited, next, err := store.Scan(ctx, time.Time{}, time.Time{}, nil, 2, func(e model.Event) bool { return e.Type == "container" })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"restart", "die"}, actions(limited))
+	assert.Equal(t, "137", limited[1].Attributes["exitCode"])
+	assert.NotNil(t, next)
+
+	latest, err := store.Latest(ctx)
+	require.NoError(t, err)
+	assert.True(t, latest.Equal(at(3)))
+}
+
+func TestBoltStore_ScanPagesWithinNanosecond(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Paging by time alone would skip or repeat events sharing the
+	// nanosecond a page ends on.
+	require.NoError(t, store.Append(ctx, []model.Event{
+		{Type: "container", Action: "create", ActorID: "c1", Time: at(0)},
+		{Type: "container", Action: "start", ActorID: "c1", Time: at(1)},
+		{Type: "network", Action: "connect", ActorID: "n1", Time: at(1)},
+		{Type: "volume", Action: "mount", ActorID: "v1", Time: at(1)},
+	}))
+
+	var seen []string
+	var before []byte
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 4)
+		page, next, err := store.Scan(ctx, time.Time{}, time.Time{}, before, 1, nil)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		seen = append(seen, page[0].Action)
+		if next == nil {
+			break
+		}
+		before = next
+	}
+	assert.ElementsMatch(t, []string{"create", "start", "connect", "mount"}, seen)
+	assert.Equal(t, "create", seen[3])
+
+	// until still bounds a resumed scan.
+	page, next, err := store.Scan(ctx, time.Time{}, at(0), []byte{0xff}, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create"}, actions(page))
+	assert.Nil(t, next)
+}
+
+func TestBoltStore_AppendIsIdempotent(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	event := model.Event{Type: "container", Action: "start", ActorID: "c1", Time: at(0)}
+	require.NoError(t, store.Append(ctx, []model.Event{event}))
+	require.NoError(t, store.Append(ctx, []model.Event{event}))
+
+	all, _, err := store.Scan(ctx, time.Time{}, time.Time{}, nil, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestBoltStore_Prune(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	var events []model.Event
+	for i := 0; i < 10; i++ {
+		events = append(events, model.Event{Type: "container", Action: "start", ActorID: "c", Time: at(i)})
+	}
+	require.NoError(t, store.Append(ctx, events))
+
+	deleted, err := store.Prune(ctx, at(3), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	deleted, err = store.Prune(ctx, time.Time{}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	left, _, err := store.Scan(ctx, time.Time{}, time.Time{}, nil, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, left, 4)
+	assert.True(t, left[3].Time.Equal(at(6)))
+}
+
+func TestBoltStore_Empty(t *testing.T) {
+	store := newTestStore(t)
+
+	latest, err := store.Latest(context.Background())
+	require.NoError(t, err)
+	assert.True(t, latest.IsZero())
+
+	deleted, err := store.Prune(context.Background(), at(0), 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
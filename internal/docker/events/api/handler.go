@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/gin-gonic/gin"
+	mappers "github.com/rivernova/orcahub/internal/docker/events/api/mappers"
+	requests "github.com/rivernova/orcahub/internal/docker/events/api/requests"
+	domain "github.com/rivernova/orcahub/internal/docker/events/domain"
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+// eventTypes are the types the daemon reports events for.
+var eventTypes = []events.Type{
+	events.BuilderEventType, events.ConfigEventType, events.ContainerEventType,
+	events.DaemonEventType, events.ImageEventType, events.NetworkEventType,
+	events.NodeEventType, events.PluginEventType, events.SecretEventType,
+	events.ServiceEventType, events.VolumeEventType,
+}
+
+type Handler struct {
+	service domain.EventService
+}
+
+func NewHandler(service domain.EventService) *Handler {
+	return &Handler{service: service}
+}
+
+// List returns stored events newest first, e.g.
+// ?actor=web&action=restart&since=24h answers who restarted web in the last
+// day. Page back by passing next_cursor from the response as cursor, along
+// with the same filters.
+func (h *Handler) List(c *gin.Context) {
+	var req requests.EventQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, t := range req.Type {
+		if t != "" && !slices.Contains(eventTypes, events.Type(t)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown event type %q", t)})
+			return
+		}
+	}
+	now := time.Now()
+	since, err := parseTime("since", req.Since, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	until, err := parseTime("until", req.Until, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	page, err := h.service.Query(c.Request.Context(), model.Query{
+		Since:   since,
+		Until:   until,
+		Types:   req.Type,
+		Actions: req.Action,
+		Actors:  req.Actor,
+		Labels:  req.Label,
+		Cursor:  req.Cursor,
+		Limit:   req.Limit,
+	})
+	if err != nil {
+		c.JSON(eventErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToEventPageResponse(page))
+}
+
+func eventErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrHistoryDisabled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTime accepts what the daemon's own since and until do.
+func parseTime(name, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return time.Unix(sec, nsec), nil
+}
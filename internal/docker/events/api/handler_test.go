@@ -0,0 +1,125 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	eventapi "github.com/rivernova/orcahub/internal/docker/events/api"
+	"github.com/rivernova/orcahub/internal/docker/events/domain"
+	"github.com/rivernova/orcahub/internal/docker/events/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func init() { gin.SetMode(gin.TestMode) }
+
+type mockEventService struct{ mock.Mock }
+
+func (m *mockEventService) Query(ctx context.Context, q model.Query) (*model.EventPage, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EventPage), args.Error(1)
+}
+
+func setupEventRouter(svc *mockEventService) *gin.Engine {
+	r := gin.New()
+	h := eventapi.NewHandler(svc)
+	r.GET("/events", h.List)
+	return r
+}
+
+func TestEventHandler_List_OK(t *testing.T) {
+	svc := &mockEventService{}
+	r := setupEventRouter(svc)
+
+	svc.On("Query", mock.Anything, model.Query{
+		Since:   time.Unix(1700000000, 0),
+		Until:   time.Unix(1700086400, 500),
+		Types:   []string{"container"},
+		Actions: []string{"restart"},
+		Actors:  []string{"web"},
+		Labels:  []string{"env=prod"},
+		Cursor:  "F5eL",
+		Limit:   1,
+	}).Return(&model.EventPage{
+		Events: []model.Event{{
+			Type: "container", Action: "restart", ActorID: "c1", ActorName: "web",
+			Attributes: map[string]string{"name": "web", "env": "prod"},
+			Time:       time.Unix(1700050000, 42),
+		}},
+		More: true,
+		Next: "F5eM",
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/events?since=1700000000&until=1700086400.000000500&type=container&action=restart&actor=web&label=env%3Dprod&cursor=F5eL&limit=1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["more"])
+	assert.Equal(t, "F5eM", resp["next_cursor"])
+	event := resp["events"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "web", event["actor"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(1700050000), event["time"])
+}
+
+func TestEventHandler_List_RelativeSince(t *testing.T) {
+	svc := &mockEventService{}
+	r := setupEventRouter(svc)
+
+	svc.On("Query", mock.Anything, mock.MatchedBy(func(q model.Query) bool {
+		return time.Since(q.Since) > 11*time.Hour && time.Since(q.Since) < 13*time.Hour && q.Until.IsZero()
+	})).Return(&model.EventPage{}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events?since=12h", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"events":[],"more":false}`, w.Body.String())
+}
+
+func TestEventHandler_List_BadRequest(t *testing.T) {
+	for _, query := range []string{"since=yesterday", "until=soon", "limit=-1", "limit=5000", "type=contianer"} {
+		svc := &mockEventService{}
+		r := setupEventRouter(svc)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		svc.AssertNotCalled(t, "Query", mock.Anything, mock.Anything)
+	}
+}
+
+func TestEventHandler_List_Disabled(t *testing.T) {
+	svc := &mockEventService{}
+	r := setupEventRouter(svc)
+
+	svc.On("Query", mock.Anything, mock.Anything).Return(nil, domain.ErrHistoryDisabled)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestEventHandler_List_InvalidCursor(t *testing.T) {
+	svc := &mockEventService{}
+	r := setupEventRouter(svc)
+
+	svc.On("Query", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidCursor)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events?cursor=x", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,33 @@
+package mappers
+
+import (
+	responses "github.com/rivernova/orcahub/internal/docker/events/api/responses"
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+func ToEventPageResponse(p *model.EventPage) responses.EventPageResponse {
+	events := make([]responses.EventResponse, 0, len(p.Events))
+	for _, e := range p.Events {
+		events = append(events, ToEventResponse(e))
+	}
+	return responses.EventPageResponse{Events: events, More: p.More, NextCursor: p.Next}
+}
+
+func ToEventResponse(e model.Event) responses.EventResponse {
+	attributes := e.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return responses.EventResponse{
+		Type:   e.Type,
+		Action: e.Action,
+		Actor: responses.EventActorResponse{
+			ID:         e.ActorID,
+			Name:       e.ActorName,
+			Attributes: attributes,
+		},
+		Scope:    e.Scope,
+		Time:     e.Time.Unix(),
+		TimeNano: e.Time.UnixNano(),
+	}
+}
@@ -0,0 +1,30 @@
+package mappers_test
+
+import (
+	"testing"
+	"time"
+
+	mappers "github.com/rivernova/orcahub/internal/docker/events/api/mappers"
+	"github.com/rivernova/orcahub/internal/docker/events/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToEventResponse(t *testing.T) {
+	resp := mappers.ToEventResponse(model.Event{
+		Type: "volume", Action: "destroy", ActorID: "data", Scope: "local",
+		Time: time.Unix(1700000000, 123),
+	})
+
+	assert.Equal(t, "data", resp.Actor.ID)
+	assert.NotNil(t, resp.Actor.Attributes)
+	assert.Equal(t, int64(1700000000), resp.Time)
+	assert.Equal(t, int64(1700000000000000123), resp.TimeNano)
+}
+
+func TestToEventPageResponse_NextCursor(t *testing.T) {
+	page := &model.EventPage{Events: []model.Event{{Time: time.Unix(1700000001, 0)}}}
+	assert.Empty(t, mappers.ToEventPageResponse(page).NextCursor)
+
+	page.More, page.Next = true, "AAAAAQ"
+	assert.Equal(t, "AAAAAQ", mappers.ToEventPageResponse(page).NextCursor)
+}
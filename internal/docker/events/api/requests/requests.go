@@ -0,0 +1,12 @@
+package requests
+
+type EventQueryRequest struct {
+	Since  string   `form:"since"` // Unix timestamp, RFC 3339 date or duration ago, e.g. "12h"
+	Until  string   `form:"until"`
+	Type   []string `form:"type"`
+	Action []string `form:"action"`
+	Actor  []string `form:"actor"` // ID, ID prefix or name
+	Label  []string `form:"label"` // "key" or "key=value"
+	Cursor string   `form:"cursor"`
+	Limit  int      `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
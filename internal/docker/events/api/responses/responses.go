@@ -0,0 +1,23 @@
+package responses
+
+type EventPageResponse struct {
+	Events []EventResponse `json:"events"`
+	More   bool            `json:"more"`
+	// NextCursor is the cursor that fetches the next, older page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type EventResponse struct {
+	Type     string             `json:"type"`
+	Action   string             `json:"action"`
+	Actor    EventActorResponse `json:"actor"`
+	Scope    string             `json:"scope,omitempty"`
+	Time     int64              `json:"time"`
+	TimeNano int64              `json:"time_nano"`
+}
+
+type EventActorResponse struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes"`
+}
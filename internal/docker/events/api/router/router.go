@@ -0,0 +1,13 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rivernova/orcahub/internal/docker/events/api"
+)
+
+func Register(rg *gin.RouterGroup, handler *api.Handler) {
+	events := rg.Group("/events")
+	{
+		events.GET("", handler.List)
+	}
+}
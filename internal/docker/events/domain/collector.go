@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"log"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+const (
+	maxCollectorBackoff = 30 * time.Second
+	maxCollectorBatch   = 100
+	retentionInterval   = time.Hour
+)
+
+// StartCollector records daemon events in the store until ctx is done. It
+// resumes from the newest stored event, so the events the daemon still
+// buffers from while OrcaHub was down, or the stream was broken, are not
+// lost; on an empty store it takes whatever the daemon buffers within the
+// retention window. Retention runs at start and then hourly.
+func (s *EventServiceImpl) StartCollector(ctx context.Context) {
+	if s.store == nil {
+		return
+	}
+	go s.collect(ctx)
+	go s.retain(ctx)
+}
+
+func (s *EventServiceImpl) collect(ctx context.Context) {
+	backoff := time.Second
+	for {
+		received, err := s.subscribe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = time.Second
+		}
+		log.Printf("event collector: %v; reconnecting in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxCollectorBackoff)
+	}
+}
+
+// subscribe stores events until the stream or a write fails, and reports
+// whether it stored any.
+func (s *EventServiceImpl) subscribe(ctx context.Context) (bool, error) {
+	since, err := s.store.Latest(ctx)
+	if err != nil {
+		return false, err
+	}
+	if since.IsZero() && s.retention.MaxAge > 0 {
+		since = time.Now().Add(-s.retention.MaxAge)
+	}
+	msgs, errs := s.adapter.Subscribe(ctx, since)
+
+	received := false
+	for {
+		select {
+		case e, ok := <-msgs:
+			if !ok {
+				return received, <-errs
+			}
+			// Write whatever has queued up in one transaction.
+			batch := []model.Event{e}
+		drain:
+			for len(batch) < maxCollectorBatch {
+				select {
+				case e, ok := <-msgs:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, e)
+				default:
+					break drain
+				}
+			}
+			if err := s.store.Append(ctx, batch); err != nil {
+				return received, err
+			}
+			received = true
+		case err := <-errs:
+			return received, err
+		case <-ctx.Done():
+			return received, ctx.Err()
+		}
+	}
+}
+
+func (s *EventServiceImpl) retain(ctx context.Context) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		s.applyRetention(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *EventServiceImpl) applyRetention(ctx context.Context) {
+	if s.retention.MaxAge <= 0 && s.retention.MaxEvents <= 0 {
+		return
+	}
+	var before time.Time
+	if s.retention.MaxAge > 0 {
+		before = time.Now().Add(-s.retention.MaxAge)
+	}
+	if _, err := s.store.Prune(ctx, before, s.retention.MaxEvents); err != nil {
+		log.Printf("event collector: %v", err)
+	}
+}
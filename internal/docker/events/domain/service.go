@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+type EventService interface {
+	Query(ctx context.Context, q model.Query) (*model.EventPage, error)
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	adapter "github.com/rivernova/orcahub/internal/docker/events/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/events/model"
+)
+
+var (
+	ErrHistoryDisabled = errors.New("event history is not configured")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+)
+
+// Query limits.
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// DefaultRetention keeps a week of events, at most 100000 of them.
+var DefaultRetention = model.Retention{MaxAge: 7 * 24 * time.Hour, MaxEvents: 100000}
+
+type EventServiceImpl struct {
+	adapter   adapter.EventAdapter
+	store     adapter.EventStore
+	retention model.Retention
+}
+
+func NewEventServiceImpl(adapter adapter.EventAdapter) *EventServiceImpl {
+	return &EventServiceImpl{adapter: adapter, retention: DefaultRetention}
+}
+
+// UseStore enables the history. Without a store Query fails with
+// ErrHistoryDisabled and StartCollector does nothing.
+func (s *EventServiceImpl) UseStore(store adapter.EventStore) {
+	s.store = store
+}
+
+// UseRetention overrides DefaultRetention.
+func (s *EventServiceImpl) UseRetention(r model.Retention) {
+	s.retention = r
+}
+
+func (s *EventServiceImpl) Query(ctx context.Context, q model.Query) (*model.EventPage, error) {
+	if s.store == nil {
+		return nil, ErrHistoryDisabled
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+	limit = min(limit, MaxQueryLimit)
+
+	// Cursors are store keys, opaque to clients.
+	var before []byte
+	if q.Cursor != "" {
+		key, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("%w %q", ErrInvalidCursor, q.Cursor)
+		}
+		before = key
+	}
+	events, next, err := s.store.Scan(ctx, q.Since, q.Until, before, limit, matcher(q))
+	if err != nil {
+		return nil, err
+	}
+	page := &model.EventPage{Events: events}
+	if next != nil {
+		page.More, page.Next = true, base64.RawURLEncoding.EncodeToString(next)
+	}
+	return page, nil
+}
+
+// matcher returns the filter of q in the daemon's semantics: an action
+// matches with or without its detail, so "exec_start" matches
+// "exec_start: sh -c ls", an actor by ID, ID prefix or name, and an event
+// must carry every label given, while any of the other values will do.
+func matcher(q model.Query) func(model.Event) bool {
+	return func(e model.Event) bool {
+		if len(q.Types) > 0 && !anyOf(q.Types, func(t string) bool { return t == e.Type }) {
+			return false
+		}
+		if len(q.Actions) > 0 {
+			base, _, _ := strings.Cut(e.Action, ":")
+			if !anyOf(q.Actions, func(a string) bool { return a == e.Action || a == base }) {
+				return false
+			}
+		}
+		if len(q.Actors) > 0 && !anyOf(q.Actors, func(a string) bool {
+			return a == e.ActorName || strings.HasPrefix(e.ActorID, a)
+		}) {
+			return false
+		}
+		for _, l := range q.Labels {
+			if l == "" {
+				continue
+			}
+			key, value, hasValue := strings.Cut(l, "=")
+			if got, ok := e.Attributes[key]; !ok || (hasValue && got != value) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func anyOf(values []string, pred func(string) bool) bool {
+	for _, v := range values {
+		if v != "" && pred(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rivernova/orcahub/internal/docker/events/domain"
+	"github.com/rivernova/orcahub/internal/docker/events/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEventAdapter struct{ mock.Mock }
+
+func (m *mockEventAdapter) Subscribe(ctx context.Context, since time.Time) (<-chan model.Event, <-chan error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(<-chan model.Event), args.Get(1).(<-chan error)
+}
+
+// memStore is an in-memory EventStore.
+type memStore struct {
+	mu     sync.Mutex
+	events []model.Event
+	pruned []time.Time
+}
+
+func (s *memStore) Append(ctx context.Context, events []model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	sort.SliceStable(s.events, func(i, j int) bool { return s.events[i].Time.Before(s.events[j].Time) })
+	return nil
+}
+
+// Scan keys events by their position in the store.
+func (s *memStore) Scan(ctx context.Context, since, until time.Time, before []byte, limit int, match func(model.Event) bool) ([]model.Event, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := len(s.events) - 1
+	if before != nil {
+		start = int(before[0]) - 1
+	}
+	var result []model.Event
+	for i := start; i >= 0; i-- {
+		e := s.events[i]
+		if (!since.IsZero() && e.Time.Before(since)) || (!until.IsZero() && e.Time.After(until)) || !match(e) {
+			continue
+		}
+		if len(result) == limit {
+			return result, []byte{byte(i + 1)}, nil
+		}
+		result = append(result, e)
+	}
+	return result, nil, nil
+}
+
+func (s *memStore) Latest(ctx context.Context) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return time.Time{}, nil
+	}
+	return s.events[len(s.events)-1].Time, nil
+}
+
+func (s *memStore) Prune(ctx context.Context, before time.Time, keep int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruned = append(s.pruned, before)
+	return 0, nil
+}
+
+func (s *memStore) Close() error { return nil }
+
+func (s *memStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func at(sec int) time.Time {
+	return time.Unix(1700000000+int64(sec), 0)
+}
+
+func history() []model.Event {
+	return []model.Event{
+		{Type: "container", Action: "start", ActorID: "c1aaaa", ActorName: "web", Attributes: map[string]string{"env": "prod"}, Time: at(0)},
+		{Type: "container", Action: "exec_start: sh -c ls", ActorID: "c1aaaa", ActorName: "web", Attributes: map[string]string{"env": "prod"}, Time: at(1)},
+		{Type: "container", Action: "restart", ActorID: "c1aaaa", ActorName: "web", Attributes: map[string]string{"env": "prod", "tier": "frontend"}, Time: at(2)},
+		{Type: "container", Action: "restart", ActorID: "c2bbbb", ActorName: "db", Attributes: map[string]string{"env": "dev"}, Time: at(3)},
+		{Type: "network", Action: "connect", ActorID: "n1", ActorName: "app", Time: at(4)},
+	}
+}
+
+func TestEventService_Query_Filters(t *testing.T) {
+	store := &memStore{events: history()}
+	svc := domain.NewEventServiceImpl(&mockEventAdapter{})
+	svc.UseStore(store)
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		q    model.Query
+		want []time.Time
+	}{
+		"all":             {model.Query{}, []time.Time{at(4), at(3), at(2), at(1), at(0)}},
+		"type":            {model.Query{Types: []string{"network"}}, []time.Time{at(4)}},
+		"action":          {model.Query{Actions: []string{"restart"}}, []time.Time{at(3), at(2)}},
+		"action detail":   {model.Query{Actions: []string{"exec_start"}}, []time.Time{at(1)}},
+		"actor name":      {model.Query{Actors: []string{"web"}, Actions: []string{"restart"}}, []time.Time{at(2)}},
+		"actor id prefix": {model.Query{Actors: []string{"c2"}}, []time.Time{at(3)}},
+		"label key":       {model.Query{Labels: []string{"env"}, Since: at(2)}, []time.Time{at(3), at(2)}},
+		"label value":     {model.Query{Labels: []string{"env=dev"}}, []time.Time{at(3)}},
+		"two labels":      {model.Query{Labels: []string{"env=prod", "tier"}}, []time.Time{at(2)}},
+		"labels conflict": {model.Query{Labels: []string{"env=dev", "env=staging"}}, nil},
+		"range":           {model.Query{Since: at(1), Until: at(3)}, []time.Time{at(3), at(2), at(1)}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			page, err := svc.Query(ctx, tc.q)
+			require.NoError(t, err)
+			var got []time.Time
+			for _, e := range page.Events {
+				got = append(got, e.Time)
+			}
+			assert.Equal(t, tc.want, got)
+			assert.False(t, page.More)
+		})
+	}
+}
+
+func TestEventService_Query_Pages(t *testing.T) {
+	store := &memStore{events: history()}
+	svc := domain.NewEventServiceImpl(&mockEventAdapter{})
+	svc.UseStore(store)
+	ctx := context.Background()
+
+	page, err := svc.Query(ctx, model.Query{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Events, 2)
+	assert.True(t, page.More)
+
+	// The next page starts below the last event seen.
+	page, err = svc.Query(ctx, model.Query{Limit: 2, Cursor: page.Next})
+	require.NoError(t, err)
+	assert.Equal(t, at(2), page.Events[0].Time)
+	assert.True(t, page.More)
+
+	page, err = svc.Query(ctx, model.Query{Limit: 2, Cursor: page.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []model.Event{history()[0]}, page.Events)
+	assert.False(t, page.More)
+	assert.Empty(t, page.Next)
+}
+
+func TestEventService_Query_InvalidCursor(t *testing.T) {
+	svc := domain.NewEventServiceImpl(&mockEventAdapter{})
+	svc.UseStore(&memStore{events: history()})
+
+	_, err := svc.Query(context.Background(), model.Query{Cursor: "not base64!"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestEventService_Query_Disabled(t *testing.T) {
+	svc := domain.NewEventServiceImpl(&mockEventAdapter{})
+
+	_, err := svc.Query(context.Background(), model.Query{})
+	assert.ErrorIs(t, err, domain.ErrHistoryDisabled)
+}
+
+func TestEventService_Collector_ResumesFromLatest(t *testing.T) {
+	a := &mockEventAdapter{}
+	store := &memStore{events: history()}
+	svc := domain.NewEventServiceImpl(a)
+	svc.UseStore(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	msgs := make(chan model.Event)
+	errs := make(chan error, 1)
+	a.On("Subscribe", mock.Anything, at(4)).Return((<-chan model.Event)(msgs), (<-chan error)(errs)).Once()
+
+	svc.StartCollector(ctx)
+	msgs <- model.Event{Type: "container", Action: "die", ActorID: "c1aaaa", Time: at(5)}
+	msgs <- model.Event{Type: "container", Action: "start", ActorID: "c1aaaa", Time: at(6)}
+
+	assert.Eventually(t, func() bool { return store.count() == 7 }, time.Second, 10*time.Millisecond)
+	latest, _ := store.Latest(ctx)
+	assert.Equal(t, at(6), latest)
+	a.AssertExpectations(t)
+}
+
+func TestEventService_Collector_Reconnects(t *testing.T) {
+	a := &mockEventAdapter{}
+	store := &memStore{}
+	svc := domain.NewEventServiceImpl(a)
+	svc.UseStore(store)
+	svc.UseRetention(model.Retention{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := make(chan model.Event)
+	firstErr := make(chan error, 1)
+	firstErr <- errors.New("daemon restarted")
+	// An empty store with no MaxAge subscribes without since.
+	a.On("Subscribe", mock.Anything, time.Time{}).Return((<-chan model.Event)(first), (<-chan error)(firstErr)).Once()
+
+	second := make(chan model.Event, 1)
+	second <- model.Event{Type: "daemon", Action: "reload", Time: at(0)}
+	a.On("Subscribe", mock.Anything, time.Time{}).Return((<-chan model.Event)(second), (<-chan error)(make(chan error))).Once()
+
+	svc.StartCollector(ctx)
+
+	assert.Eventually(t, func() bool { return store.count() == 1 }, 3*time.Second, 10*time.Millisecond)
+	a.AssertExpectations(t)
+}
+
+func TestEventService_Collector_AppliesRetention(t *testing.T) {
+	a := &mockEventAdapter{}
+	store := &memStore{}
+	svc := domain.NewEventServiceImpl(a)
+	svc.UseStore(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a.On("Subscribe", mock.Anything, mock.Anything).Return((<-chan model.Event)(make(chan model.Event)), (<-chan error)(make(chan error)))
+
+	svc.StartCollector(ctx)
+
+	assert.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.pruned) == 1
+	}, time.Second, 10*time.Millisecond)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.WithinDuration(t, time.Now().Add(-domain.DefaultRetention.MaxAge), store.pruned[0], time.Minute)
+}
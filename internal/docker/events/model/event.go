@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Event is a Docker event as kept in the history store.
+type Event struct {
+	Type       string
+	Action     string
+	ActorID    string
+	ActorName  string
+	Attributes map[string]string
+	Scope      string
+	Time       time.Time
+}
+
+// Query selects events from the history. Zero times leave the range open.
+// Values within one filter are ORed and the filters ANDed, as with the
+// daemon's own event filters.
+type Query struct {
+	Since   time.Time
+	Until   time.Time
+	Types   []string
+	Actions []string
+	Actors  []string // ID, ID prefix or name
+	Labels  []string // "key" or "key=value"
+	Cursor  string   // Next of the previous page
+	Limit   int
+}
+
+// EventPage is a page of events, newest first. More reports older matching
+// events; ask for them with Cursor set to Next.
+type EventPage struct {
+	Events []Event
+	More   bool
+	Next   string
+}
+
+// Retention bounds the history. MaxAge drops older events and MaxEvents
+// the oldest beyond that count; zero disables either.
+type Retention struct {
+	MaxAge    time.Duration
+	MaxEvents int
+}
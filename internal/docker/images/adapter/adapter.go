@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"context"
+	"io"
 
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
 )
@@ -12,7 +13,23 @@ type ImageAdapter interface {
 	Delete(ctx context.Context, id string, opts model.RemoveOptions) (*model.RemoveResult, error)
 	Pull(ctx context.Context, opts model.PullOptions) error
 	Build(ctx context.Context, opts model.BuildOptions) (*model.BuildResult, error)
-	Prune(ctx context.Context) (model.PruneResult, error)
+	// ListUsage lists top-level images with Containers and SharedSize set.
+	ListUsage(ctx context.Context) ([]model.Image, error)
+	Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error)
 	Tag(ctx context.Context, opts model.TagOptions) error
 	History(ctx context.Context, id string) ([]model.HistoryEntry, error)
+	LayerContents(ctx context.Context, id string) ([]model.LayerContent, error)
+	// ReadFiles returns the contents of the files in the image's final
+	// filesystem whose absolute path satisfies match, and the module
+	// information of its Go executables.
+	ReadFiles(ctx context.Context, id string, match func(path string) bool) (*model.ImageFiles, error)
+	Save(ctx context.Context, refs []string) (io.ReadCloser, error)
+	Load(ctx context.Context, input io.Reader) (*model.LoadResult, error)
+	DistributionDigest(ctx context.Context, ref string) (string, error)
+	// RemoteManifests authenticates with auth when it is set.
+	RemoteManifests(ctx context.Context, ref string, auth *model.RegistryAuth) (*model.ManifestList, error)
+	ContainerImages(ctx context.Context) ([]model.ContainerImage, error)
+	// ImageGraph returns every image, intermediate ones included, with its
+	// parent and layer chain.
+	ImageGraph(ctx context.Context) ([]model.ImageNode, error)
 }
@@ -2,24 +2,32 @@ package adapter
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/distribution/reference"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"github.com/moby/go-archive"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
 )
 
 type ImageAdapterImpl struct {
-	client *client.Client
+	client   *client.Client
+	registry *registryClient
 }
 
 func NewImageAdapterImpl() (*ImageAdapterImpl, error) {
@@ -27,7 +35,7 @@ func NewImageAdapterImpl() (*ImageAdapterImpl, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
-	return &ImageAdapterImpl{client: cli}, nil
+	return &ImageAdapterImpl{client: cli, registry: newRegistryClient(http.DefaultClient)}, nil
 }
 
 var _ ImageAdapter = (*ImageAdapterImpl)(nil)
@@ -40,20 +48,46 @@ func (a *ImageAdapterImpl) List(ctx context.Context) ([]model.Image, error) {
 
 	result := make([]model.Image, 0, len(images))
 	for _, img := range images {
-		result = append(result, model.Image{
-			ID:         img.ID,
-			Tags:       img.RepoTags,
-			Size:       img.Size,
-			Created:    img.Created,
-			Labels:     img.Labels,
-			Containers: img.Containers,
-		})
+		result = append(result, toImage(img))
 	}
 	return result, nil
 }
 
+// ListUsage lists top-level images with their container count and shared
+// size, which the daemon only computes on request.
+func (a *ImageAdapterImpl) ListUsage(ctx context.Context) ([]model.Image, error) {
+	images, err := a.client.ImageList(ctx, image.ListOptions{SharedSize: true, ContainerCount: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	result := make([]model.Image, 0, len(images))
+	for _, img := range images {
+		result = append(result, toImage(img))
+	}
+	return result, nil
+}
+
+func toImage(img image.Summary) model.Image {
+	return model.Image{
+		ID:          img.ID,
+		Tags:        img.RepoTags,
+		RepoDigests: img.RepoDigests,
+		Size:        img.Size,
+		SharedSize:  img.SharedSize,
+		Created:     img.Created,
+		Labels:      img.Labels,
+		Containers:  img.Containers,
+	}
+}
+
 func (a *ImageAdapterImpl) Inspect(ctx context.Context, id string) (*model.Image, error) {
-	img, _, err := a.client.ImageInspectWithRaw(ctx, id)
+	img, err := a.client.ImageInspect(ctx, id, client.ImageInspectWithManifests(true))
+	if err != nil && versions.LessThan(a.client.ClientVersion(), "1.48") {
+		// Manifest summaries need API 1.48; older daemons only describe the
+		// platform the image was pulled for.
+		img, err = a.client.ImageInspect(ctx, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect image %s: %w", id, err)
 	}
@@ -68,11 +102,13 @@ func (a *ImageAdapterImpl) Inspect(ctx context.Context, id string) (*model.Image
 	return &model.Image{
 		ID:           img.ID,
 		Tags:         img.RepoTags,
+		RepoDigests:  img.RepoDigests,
 		Size:         img.Size,
 		Created:      created.Unix(),
 		Labels:       img.Config.Labels,
 		Os:           img.Os,
 		Architecture: img.Architecture,
+		Variant:      img.Variant,
 		Author:       img.Author,
 		Comment:      img.Comment,
 		Cmd:          img.Config.Cmd,
@@ -82,9 +118,44 @@ func (a *ImageAdapterImpl) Inspect(ctx context.Context, id string) (*model.Image
 		ExposedPorts: exposedPorts,
 		Layers:       len(img.RootFS.Layers),
 		VirtualSize:  img.VirtualSize,
+		Manifests:    toManifests(img.Manifests),
 	}, nil
 }
 
+func toManifests(summaries []image.ManifestSummary) []model.Manifest {
+	if len(summaries) == 0 {
+		return nil
+	}
+	result := make([]model.Manifest, 0, len(summaries))
+	for _, m := range summaries {
+		manifest := model.Manifest{
+			Digest:    m.Descriptor.Digest.String(),
+			MediaType: m.Descriptor.MediaType,
+			Kind:      string(m.Kind),
+			Size:      m.Size.Total,
+			Available: m.Available,
+		}
+		if m.ImageData != nil {
+			manifest.Platform = toPlatform(m.ImageData.Platform)
+			manifest.UnpackedSize = m.ImageData.Size.Unpacked
+			manifest.Containers = m.ImageData.Containers
+		} else if m.Descriptor.Platform != nil {
+			manifest.Platform = toPlatform(*m.Descriptor.Platform)
+		}
+		result = append(result, manifest)
+	}
+	return result
+}
+
+func toPlatform(p ocispec.Platform) model.Platform {
+	return model.Platform{
+		OS:           p.OS,
+		Architecture: p.Architecture,
+		Variant:      p.Variant,
+		OSVersion:    p.OSVersion,
+	}
+}
+
 func (a *ImageAdapterImpl) Delete(ctx context.Context, id string, opts model.RemoveOptions) (*model.RemoveResult, error) {
 	items, err := a.client.ImageRemove(ctx, id, image.RemoveOptions{
 		Force:         opts.Force,
@@ -107,19 +178,14 @@ func (a *ImageAdapterImpl) Delete(ctx context.Context, id string, opts model.Rem
 }
 
 func (a *ImageAdapterImpl) Pull(ctx context.Context, opts model.PullOptions) error {
-	pullOpts := image.PullOptions{}
+	pullOpts := image.PullOptions{Platform: opts.Platform}
 
 	if opts.Auth != nil {
-		authConfig := registry.AuthConfig{
-			Username:      opts.Auth.Username,
-			Password:      opts.Auth.Password,
-			ServerAddress: opts.Auth.ServerAddress,
-		}
-		authBytes, err := json.Marshal(authConfig)
+		encoded, err := encodeAuth(opts.Auth)
 		if err != nil {
-			return fmt.Errorf("failed to encode auth config: %w", err)
+			return err
 		}
-		pullOpts.RegistryAuth = base64.URLEncoding.EncodeToString(authBytes)
+		pullOpts.RegistryAuth = encoded
 	}
 
 	reader, err := a.client.ImagePull(ctx, opts.Image, pullOpts)
@@ -197,8 +263,18 @@ func (a *ImageAdapterImpl) Build(ctx context.Context, opts model.BuildOptions) (
 	}, nil
 }
 
-func (a *ImageAdapterImpl) Prune(ctx context.Context) (model.PruneResult, error) {
-	report, err := a.client.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", "false")))
+func (a *ImageAdapterImpl) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := filters.NewArgs(filters.Arg("dangling", strconv.FormatBool(opts.DanglingOnly)))
+	if opts.Until != "" {
+		args.Add("until", opts.Until)
+	}
+	for _, label := range opts.Labels {
+		args.Add("label", label)
+	}
+	for _, label := range opts.ExcludeLabels {
+		args.Add("label!", label)
+	}
+	report, err := a.client.ImagesPrune(ctx, args)
 	if err != nil {
 		return model.PruneResult{}, fmt.Errorf("failed to prune images: %w", err)
 	}
@@ -236,3 +312,217 @@ func (a *ImageAdapterImpl) History(ctx context.Context, id string) ([]model.Hist
 	}
 	return result, nil
 }
+
+func (a *ImageAdapterImpl) LayerContents(ctx context.Context, id string) ([]model.LayerContent, error) {
+	reader, err := a.client.ImageSave(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export image %s: %w", id, err)
+	}
+	defer reader.Close()
+
+	layers, err := readImageArchive(reader, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layers of image %s: %w", id, err)
+	}
+	return layers, nil
+}
+
+func (a *ImageAdapterImpl) ReadFiles(ctx context.Context, id string, match func(path string) bool) (*model.ImageFiles, error) {
+	reader, err := a.client.ImageSave(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export image %s: %w", id, err)
+	}
+	defer reader.Close()
+
+	layers, err := readImageArchive(reader, match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read files of image %s: %w", id, err)
+	}
+	return mergeLayerFiles(layers), nil
+}
+
+func (a *ImageAdapterImpl) Save(ctx context.Context, refs []string) (io.ReadCloser, error) {
+	reader, err := a.client.ImageSave(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save images %s: %w", strings.Join(refs, ", "), err)
+	}
+	return reader, nil
+}
+
+func (a *ImageAdapterImpl) Load(ctx context.Context, input io.Reader) (*model.LoadResult, error) {
+	resp, err := a.client.ImageLoad(ctx, input, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load images: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result := &model.LoadResult{}
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read load output: %w", err)
+		}
+		if msg.Error != "" {
+			return nil, fmt.Errorf("load error: %s", msg.Error)
+		}
+		// The daemon reports "Loaded image: <ref>" for tagged images and
+		// "Loaded image ID: <id>" for untagged ones.
+		line := strings.TrimSpace(msg.Stream)
+		if ref, ok := strings.CutPrefix(line, "Loaded image ID: "); ok {
+			result.Loaded = append(result.Loaded, ref)
+		} else if ref, ok := strings.CutPrefix(line, "Loaded image: "); ok {
+			result.Loaded = append(result.Loaded, ref)
+		}
+	}
+	return result, nil
+}
+
+func (a *ImageAdapterImpl) DistributionDigest(ctx context.Context, ref string) (string, error) {
+	info, err := a.client.DistributionInspect(ctx, ref, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to query registry for %s: %w", ref, err)
+	}
+	return info.Descriptor.Digest.String(), nil
+}
+
+// encodeAuth encodes credentials the way the daemon expects them in the
+// X-Registry-Auth header.
+func encodeAuth(auth *model.RegistryAuth) (string, error) {
+	encoded, err := registry.EncodeAuthConfig(registry.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		ServerAddress: auth.ServerAddress,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode auth config: %w", err)
+	}
+	return encoded, nil
+}
+
+// RemoteManifests asks the registry for the manifest of ref. The daemon only
+// relays the platforms of a manifest list, not the digests of its entries.
+// RemoteManifests resolves ref through the daemon and then reads the index
+// from the registry, so every platform comes with the digest it can be
+// pinned by and its total size (manifest, config and layers). When the
+// registry cannot be read directly, the platforms the daemon relayed are
+// returned with a warning instead.
+func (a *ImageAdapterImpl) RemoteManifests(ctx context.Context, ref string, auth *model.RegistryAuth) (*model.ManifestList, error) {
+	encodedAuth := ""
+	if auth != nil {
+		var err error
+		if encodedAuth, err = encodeAuth(auth); err != nil {
+			return nil, err
+		}
+	}
+	info, err := a.client.DistributionInspect(ctx, ref, encodedAuth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry for %s: %w", ref, err)
+	}
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	digest := info.Descriptor.Digest.String()
+	list := &model.ManifestList{
+		Ref:       ref,
+		Digest:    digest,
+		MediaType: info.Descriptor.MediaType,
+		Size:      info.Descriptor.Size,
+		Remote:    true,
+	}
+
+	ep := registryEndpoint{Auth: auth, Insecure: a.insecureRegistry(ctx, registryHost(named))}
+	manifests, err := a.registry.platformManifests(ctx, ep, named, digest, info.Platforms)
+	if err != nil {
+		list.Warning = fmt.Sprintf("platform digests and sizes unavailable: %v", err)
+		for _, p := range info.Platforms {
+			list.Manifests = append(list.Manifests, model.Manifest{Kind: model.ManifestKindImage, Platform: toPlatform(p)})
+		}
+		return list, nil
+	}
+	list.Manifests = manifests
+	return list, nil
+}
+
+// insecureRegistry reports whether the daemon is configured to reach host
+// as an insecure registry, by name or by the network its address is in.
+func (a *ImageAdapterImpl) insecureRegistry(ctx context.Context, host string) bool {
+	info, err := a.client.Info(ctx)
+	if err != nil || info.RegistryConfig == nil {
+		return false
+	}
+	if index, ok := info.RegistryConfig.IndexConfigs[host]; ok {
+		return !index.Secure
+	}
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+	if err != nil {
+		return false
+	}
+	for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+		for _, addr := range addrs {
+			if (*net.IPNet)(cidr).Contains(addr.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *ImageAdapterImpl) ContainerImages(ctx context.Context) ([]model.ContainerImage, error) {
+	containers, err := a.client.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	result := make([]model.ContainerImage, 0, len(containers))
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		result = append(result, model.ContainerImage{
+			ContainerID:   c.ID,
+			ContainerName: name,
+			Image:         c.Image,
+			Tag:           c.Labels[model.ImageTagLabel],
+			ImageID:       c.ImageID,
+			State:         c.State,
+		})
+	}
+	return result, nil
+}
+
+func (a *ImageAdapterImpl) ImageGraph(ctx context.Context) ([]model.ImageNode, error) {
+	images, err := a.client.ImageList(ctx, image.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	result := make([]model.ImageNode, 0, len(images))
+	for _, img := range images {
+		// The list does not carry layers, so each image is inspected.
+		info, err := a.client.ImageInspect(ctx, img.ID)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue // removed while listing
+			}
+			return nil, fmt.Errorf("failed to inspect image %s: %w", img.ID, err)
+		}
+		result = append(result, model.ImageNode{
+			ID:       img.ID,
+			ParentID: img.ParentID,
+			Tags:     img.RepoTags,
+			Created:  img.Created,
+			Layers:   info.RootFS.Layers,
+		})
+	}
+	return result, nil
+}
@@ -56,6 +56,6 @@ func TestImageAdapter_Inspect_NotFound(t *testing.T) {
 func TestImageAdapter_Prune(t *testing.T) {
 	a, err := adapter.NewImageAdapterImpl()
 	require.NoError(t, err)
-	_, err = a.Prune(context.Background())
+	_, err = a.Prune(context.Background(), model.PruneOptions{})
 	assert.NoError(t, err)
 }
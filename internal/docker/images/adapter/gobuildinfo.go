@@ -0,0 +1,72 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// goBuildInfoHeaderSize is the size of the header the Go linker writes
+	// in front of a binary's build information: the magic, the pointer
+	// size, flags and padding.
+	goBuildInfoHeaderSize = 32
+	goBuildInfoFlagInline = 0x2
+
+	// maxGoModInfoSize bounds the module information read from a binary.
+	maxGoModInfoSize = 1 << 20
+)
+
+var goBuildInfoMagic = []byte("\xff Go buildinf:")
+
+// readGoBuildInfo scans an executable for the build information the Go
+// linker embeds, as debug/buildinfo does but without needing the whole file,
+// and returns its module lines. ok is false when r is not a Go binary;
+// binaries built before Go 1.18 are reported with no module lines.
+func readGoBuildInfo(r io.Reader) (modinfo string, ok bool) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	for {
+		buf, err := br.Peek(br.Size())
+		if i := bytes.Index(buf, goBuildInfoMagic); i >= 0 && len(buf)-i >= goBuildInfoHeaderSize {
+			ptrSize, flags := buf[i+14], buf[i+15]
+			if (ptrSize != 4 && ptrSize != 8) || flags&^0x3 != 0 {
+				// A stray copy of the magic, as in programs that read
+				// build information themselves.
+				br.Discard(i + 1)
+				continue
+			}
+			br.Discard(i + goBuildInfoHeaderSize)
+			if flags&goBuildInfoFlagInline == 0 {
+				return "", true
+			}
+			if _, ok := readVarString(br); !ok {
+				return "", true
+			}
+			mod, _ := readVarString(br)
+			// The module lines are framed by 16 byte sentinels.
+			if len(mod) >= 33 && mod[len(mod)-17] == '\n' {
+				return mod[16 : len(mod)-16], true
+			}
+			return "", true
+		}
+		if err != nil {
+			return "", false
+		}
+		// Keep the tail in case the magic straddles the window.
+		br.Discard(len(buf) - goBuildInfoHeaderSize)
+	}
+}
+
+// readVarString reads a string prefixed with its uvarint length.
+func readVarString(br *bufio.Reader) (string, bool) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil || n > maxGoModInfoSize {
+		return "", false
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return "", false
+	}
+	return string(data), true
+}
@@ -0,0 +1,276 @@
+package adapter
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"strings"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+
+	// maxMetadataSize bounds how much of a non-layer blob (manifests, image
+	// configs) is kept in memory while reading a saved image.
+	maxMetadataSize = 16 << 20
+
+	// maxCapturedFileSize bounds how much of each captured layer file is kept.
+	maxCapturedFileSize = 32 << 20
+
+	// sniffSize is how much of each archive member is buffered to tell layer
+	// tarballs from metadata: a tar header plus the block that follows it.
+	sniffSize = 1024
+)
+
+type archiveManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+type archiveConfig struct {
+	History []struct {
+		Created    time.Time `json:"created"`
+		CreatedBy  string    `json:"created_by"`
+		Comment    string    `json:"comment"`
+		EmptyLayer bool      `json:"empty_layer"`
+	} `json:"history"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+type layerData struct {
+	entries    []model.LayerEntry
+	files      map[string][]byte
+	goBinaries map[string]string
+}
+
+// readImageArchive reads a `docker save` stream (legacy or OCI layout) and
+// returns the content of every layer in the order they are applied.
+// The archive is read in a single pass: layer tarballs are indexed as they are
+// encountered and matched against manifest.json once the stream is exhausted.
+// Regular files whose absolute path satisfies capture are read into
+// LayerContent.Files and the Go executables among the others into
+// LayerContent.GoBinaries; capture may be nil, which reads neither.
+func readImageArchive(r io.Reader, capture func(path string) bool) ([]model.LayerContent, error) {
+	tr := tar.NewReader(r)
+	metadata := make(map[string][]byte)
+	layers := make(map[string]layerData)
+	links := make(map[string]string)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean(hdr.Name)
+		switch hdr.Typeflag {
+		case tar.TypeSymlink:
+			links[name] = path.Join(path.Dir(name), hdr.Linkname)
+			continue
+		case tar.TypeReg:
+		default:
+			continue
+		}
+
+		br := bufio.NewReaderSize(tr, sniffSize)
+		head, _ := br.Peek(sniffSize)
+		switch {
+		case isTar(head):
+			layer, err := readLayer(br, capture)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %w", name, err)
+			}
+			layers[name] = layer
+		case isGzip(head):
+			gz, err := gzip.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decompress %s: %w", name, err)
+			}
+			layer, err := readLayer(gz, capture)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read layer %s: %w", name, err)
+			}
+			layers[name] = layer
+		default:
+			data, err := io.ReadAll(io.LimitReader(br, maxMetadataSize))
+			if err != nil {
+				return nil, err
+			}
+			metadata[name] = data
+		}
+	}
+
+	var manifests []archiveManifest
+	if err := json.Unmarshal(metadata["manifest.json"], &manifests); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest.json: %w", err)
+	}
+	if len(manifests) == 0 {
+		return nil, errors.New("archive contains no images")
+	}
+	manifest := manifests[0]
+
+	var config archiveConfig
+	if err := json.Unmarshal(metadata[resolveLink(links, manifest.Config)], &config); err != nil {
+		return nil, fmt.Errorf("failed to parse image config: %w", err)
+	}
+
+	history := config.History[:0:0]
+	for _, h := range config.History {
+		if !h.EmptyLayer {
+			history = append(history, h)
+		}
+	}
+
+	result := make([]model.LayerContent, 0, len(manifest.Layers))
+	for i, name := range manifest.Layers {
+		data, ok := layers[resolveLink(links, name)]
+		if !ok {
+			return nil, fmt.Errorf("layer %s is missing from the archive", name)
+		}
+		layer := model.LayerContent{Entries: data.entries, Files: data.files, GoBinaries: data.goBinaries}
+		if i < len(config.RootFS.DiffIDs) {
+			layer.Digest = config.RootFS.DiffIDs[i]
+		}
+		if i < len(history) {
+			layer.Created = history[i].Created.Unix()
+			layer.CreatedBy = history[i].CreatedBy
+			layer.Comment = history[i].Comment
+		}
+		result = append(result, layer)
+	}
+	return result, nil
+}
+
+func readLayer(r io.Reader, capture func(path string) bool) (layerData, error) {
+	tr := tar.NewReader(r)
+	var layer layerData
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return layer, nil
+		}
+		if err != nil {
+			return layerData{}, err
+		}
+		p := path.Join("/", hdr.Name)
+		if p == "/" {
+			continue
+		}
+		dir, base := path.Split(p)
+		switch {
+		case base == whiteoutOpaque:
+			layer.entries = append(layer.entries, model.LayerEntry{Path: path.Clean(dir), Opaque: true})
+		case strings.HasPrefix(base, whiteoutPrefix):
+			layer.entries = append(layer.entries, model.LayerEntry{
+				Path:     path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)),
+				Whiteout: true,
+			})
+		default:
+			layer.entries = append(layer.entries, model.LayerEntry{
+				Path: p,
+				Size: hdr.Size,
+				Mode: hdr.FileInfo().Mode(),
+			})
+			if capture == nil || hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			if capture(p) {
+				data, err := io.ReadAll(io.LimitReader(tr, maxCapturedFileSize))
+				if err != nil {
+					return layerData{}, err
+				}
+				if layer.files == nil {
+					layer.files = make(map[string][]byte)
+				}
+				layer.files[p] = data
+			} else if hdr.Mode&0o111 != 0 {
+				if modinfo, ok := readGoBuildInfo(tr); ok {
+					if layer.goBinaries == nil {
+						layer.goBinaries = make(map[string]string)
+					}
+					layer.goBinaries[p] = modinfo
+				}
+			}
+		}
+	}
+}
+
+func resolveLink(links map[string]string, name string) string {
+	name = path.Clean(name)
+	for i := 0; i < 8; i++ {
+		target, ok := links[name]
+		if !ok {
+			break
+		}
+		name = target
+	}
+	return name
+}
+
+func isGzip(head []byte) bool {
+	return len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b
+}
+
+// isTar reports whether head starts a tar stream by parsing its first header,
+// so pre-POSIX archives without the ustar magic and empty layers (nothing but
+// the end-of-archive blocks, or no bytes at all) are recognised too. A
+// truncated read only counts once a complete, checksummed header block has
+// been seen.
+func isTar(head []byte) bool {
+	_, err := tar.NewReader(bytes.NewReader(head)).Next()
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		return true
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return len(head) >= 512
+	default:
+		return false
+	}
+}
+
+// mergeLayerFiles applies the captured files and Go binaries of each layer
+// on top of the previous ones, honouring whiteouts, and returns the final
+// view.
+func mergeLayerFiles(layers []model.LayerContent) *model.ImageFiles {
+	merged := &model.ImageFiles{Files: make(map[string][]byte), GoBinaries: make(map[string]string)}
+	for _, layer := range layers {
+		for _, e := range layer.Entries {
+			switch {
+			case e.Opaque || e.Whiteout:
+				hidePath(merged.Files, e)
+				hidePath(merged.GoBinaries, e)
+			default:
+				// A file written again may no longer be a Go binary.
+				delete(merged.GoBinaries, e.Path)
+			}
+		}
+		maps.Copy(merged.Files, layer.Files)
+		maps.Copy(merged.GoBinaries, layer.GoBinaries)
+	}
+	return merged
+}
+
+// hidePath drops what whiteout entry e hides from lower layers.
+func hidePath[V any](files map[string]V, e model.LayerEntry) {
+	prefix := e.Path + "/"
+	for p := range files {
+		if strings.HasPrefix(p, prefix) || (e.Whiteout && p == e.Path) {
+			delete(files, p)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package adapter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tarFile struct {
+	name     string
+	body     []byte
+	linkname string
+}
+
+func buildTar(t *testing.T, files []tarFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.body)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(f.name, "/") {
+			hdr = &tar.Header{Name: f.name, Mode: 0o755, Typeflag: tar.TypeDir}
+		}
+		if f.linkname != "" {
+			hdr = &tar.Header{Name: f.name, Linkname: f.linkname, Typeflag: tar.TypeSymlink}
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write(f.body)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestReadImageArchive(t *testing.T) {
+	base := buildTar(t, []tarFile{
+		{name: "etc/"},
+		{name: "etc/config", body: []byte("hello")},
+	})
+	top := buildTar(t, []tarFile{
+		{name: "etc/.wh.config"},
+		{name: "app/.wh..wh..opq"},
+	})
+	config := []byte(`{
+		"history": [
+			{"created_by": "ADD rootfs /"},
+			{"created_by": "ENV A=b", "empty_layer": true},
+			{"created_by": "RUN rm /etc/config"}
+		],
+		"rootfs": {"diff_ids": ["sha256:base", "sha256:top"]}
+	}`)
+	// Manifest is written last to make sure layers seen earlier are matched
+	// up after the stream is exhausted.
+	archive := buildTar(t, []tarFile{
+		{name: "blobs/sha256/cfg", body: config},
+		{name: "blobs/sha256/base", body: base},
+		{name: "blobs/sha256/top", body: gzipBytes(t, top)},
+		{name: "legacy/layer.tar", linkname: "../blobs/sha256/top"},
+		{name: "manifest.json", body: []byte(`[{"Config":"blobs/sha256/cfg","Layers":["blobs/sha256/base","legacy/layer.tar"]}]`)},
+	})
+
+	layers, err := readImageArchive(bytes.NewReader(archive), nil)
+	require.NoError(t, err)
+	require.Len(t, layers, 2)
+
+	assert.Equal(t, "sha256:base", layers[0].Digest)
+	assert.Equal(t, "ADD rootfs /", layers[0].CreatedBy)
+	require.Len(t, layers[0].Entries, 2)
+	assert.Equal(t, "/etc/config", layers[0].Entries[1].Path)
+	assert.Equal(t, int64(5), layers[0].Entries[1].Size)
+
+	assert.Equal(t, "RUN rm /etc/config", layers[1].CreatedBy)
+	require.Len(t, layers[1].Entries, 2)
+	assert.Equal(t, "/etc/config", layers[1].Entries[0].Path)
+	assert.True(t, layers[1].Entries[0].Whiteout)
+	assert.Equal(t, "/app", layers[1].Entries[1].Path)
+	assert.True(t, layers[1].Entries[1].Opaque)
+}
+
+func TestReadImageArchive_LayersWithoutUstarMagic(t *testing.T) {
+	// A pre-POSIX (v7) header carries no magic, and an empty layer is
+	// nothing but end-of-archive blocks; both must still be read as layers.
+	v7 := buildTar(t, []tarFile{{name: "bin/sh", body: []byte("#!")}})
+	copy(v7[257:265], make([]byte, 8))
+	copy(v7[148:156], "        ")
+	var sum int64
+	for _, b := range v7[:512] {
+		sum += int64(b)
+	}
+	copy(v7[148:156], fmt.Sprintf("%06o\x00 ", sum))
+
+	archive := buildTar(t, []tarFile{
+		{name: "blobs/sha256/cfg", body: []byte(`{"rootfs": {"diff_ids": ["sha256:v7", "sha256:empty"]}}`)},
+		{name: "blobs/sha256/v7", body: v7},
+		{name: "blobs/sha256/empty", body: make([]byte, 1024)},
+		{name: "manifest.json", body: []byte(`[{"Config":"blobs/sha256/cfg","Layers":["blobs/sha256/v7","blobs/sha256/empty"]}]`)},
+	})
+
+	layers, err := readImageArchive(bytes.NewReader(archive), nil)
+	require.NoError(t, err)
+	require.Len(t, layers, 2)
+	require.Len(t, layers[0].Entries, 1)
+	assert.Equal(t, "/bin/sh", layers[0].Entries[0].Path)
+	assert.Empty(t, layers[1].Entries)
+}
+
+func TestReadImageArchive_MissingManifest(t *testing.T) {
+	archive := buildTar(t, []tarFile{{name: "oci-layout", body: []byte(`{}`)}})
+
+	_, err := readImageArchive(bytes.NewReader(archive), nil)
+	assert.Error(t, err)
+}
+
+func TestMergeLayerFiles(t *testing.T) {
+	merged := mergeLayerFiles([]model.LayerContent{
+		{Files: map[string][]byte{
+			"/etc/os-release":      []byte("ID=debian"),
+			"/app/package.json":    []byte("v1"),
+			"/var/lib/dpkg/status": []byte("old"),
+		}},
+		{
+			Entries: []model.LayerEntry{
+				{Path: "/etc/os-release", Whiteout: true},
+				{Path: "/app", Opaque: true},
+			},
+			Files: map[string][]byte{"/var/lib/dpkg/status": []byte("new")},
+		},
+	})
+
+	assert.Equal(t, map[string][]byte{"/var/lib/dpkg/status": []byte("new")}, merged.Files)
+}
+
+func TestMergeLayerFiles_GoBinaries(t *testing.T) {
+	merged := mergeLayerFiles([]model.LayerContent{
+		{GoBinaries: map[string]string{"/bin/app": "dep\ta\tv1\t\n", "/bin/tool": "dep\tb\tv1\t\n"}},
+		// /bin/app is replaced by a shell script and /bin/tool deleted.
+		{Entries: []model.LayerEntry{{Path: "/bin/app"}, {Path: "/bin/tool", Whiteout: true}}},
+	})
+
+	assert.Empty(t, merged.GoBinaries)
+}
+
+// goBinary returns a fake executable carrying Go build information the way
+// the linker lays it out, preceded by enough padding to straddle the
+// scanning window and a stray copy of the magic.
+func goBinary(modinfo string) []byte {
+	var b bytes.Buffer
+	b.Write(bytes.Repeat([]byte{0x90}, 70000))
+	b.WriteString("\xff Go buildinf:not a header at all")
+	header := make([]byte, goBuildInfoHeaderSize)
+	copy(header, goBuildInfoMagic)
+	header[14], header[15] = 8, goBuildInfoFlagInline
+	b.Write(header)
+	for _, s := range []string{"go1.22.0", strings.Repeat("s", 16) + modinfo + strings.Repeat("s", 16)} {
+		b.Write(binary.AppendUvarint(nil, uint64(len(s))))
+		b.WriteString(s)
+	}
+	b.Write(bytes.Repeat([]byte{0}, 100))
+	return b.Bytes()
+}
+
+func TestReadGoBuildInfo(t *testing.T) {
+	modinfo := "path\texample.com/app\nmod\texample.com/app\t(devel)\t\ndep\tgithub.com/x/y\tv1.2.3\th1:abc=\n"
+
+	got, ok := readGoBuildInfo(bytes.NewReader(goBinary(modinfo)))
+	assert.True(t, ok)
+	assert.Equal(t, modinfo, got)
+
+	// Before Go 1.18 the information was referenced by pointers instead.
+	legacy := make([]byte, goBuildInfoHeaderSize)
+	copy(legacy, goBuildInfoMagic)
+	legacy[14] = 8
+	got, ok = readGoBuildInfo(bytes.NewReader(legacy))
+	assert.True(t, ok)
+	assert.Empty(t, got)
+
+	_, ok = readGoBuildInfo(strings.NewReader("#!/bin/sh\necho hi\n"))
+	assert.False(t, ok)
+}
+
+func TestReadImageArchive_GoBinaries(t *testing.T) {
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	for _, f := range []struct {
+		name string
+		mode int64
+		body []byte
+	}{
+		{"usr/local/bin/app", 0o755, goBinary("dep\tgithub.com/x/y\tv1.2.3\t\n")},
+		{"usr/local/bin/run.sh", 0o755, []byte("#!/bin/sh\n")},
+		// Not executable, so not scanned.
+		{"data/blob", 0o644, goBinary("dep\tignored\tv1\t\n")},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: f.mode, Size: int64(len(f.body)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(f.body)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	archive := buildTar(t, []tarFile{
+		{name: "blobs/sha256/cfg", body: []byte(`{"rootfs": {"diff_ids": ["sha256:l"]}}`)},
+		{name: "blobs/sha256/l", body: layer.Bytes()},
+		{name: "manifest.json", body: []byte(`[{"Config":"blobs/sha256/cfg","Layers":["blobs/sha256/l"]}]`)},
+	})
+
+	layers, err := readImageArchive(bytes.NewReader(archive), func(string) bool { return false })
+	require.NoError(t, err)
+	require.Len(t, layers, 1)
+	assert.Equal(t, map[string]string{"/usr/local/bin/app": "dep\tgithub.com/x/y\tv1.2.3\t\n"}, layers[0].GoBinaries)
+
+	layers, err = readImageArchive(bytes.NewReader(archive), nil)
+	require.NoError(t, err)
+	assert.Nil(t, layers[0].GoBinaries)
+}
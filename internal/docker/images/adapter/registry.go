@@ -0,0 +1,327 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// maxManifestSize bounds the manifests read from a registry.
+	maxManifestSize = 4 << 20
+
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+
+	attestationTypeAnnotation = "vnd.docker.reference.type"
+	attestationManifestType   = "attestation-manifest"
+)
+
+var manifestAccept = strings.Join([]string{
+	ocispec.MediaTypeImageIndex,
+	ocispec.MediaTypeImageManifest,
+	mediaTypeDockerManifestList,
+	mediaTypeDockerManifest,
+}, ", ")
+
+// registryClient fetches manifests straight from a registry over the
+// distribution API, which the daemon does not expose beyond the top-level
+// descriptor. It answers Basic and Bearer challenges with the caller's
+// credentials, or anonymously without them; identity tokens from a
+// credential helper are not supported.
+type registryClient struct {
+	http *http.Client
+	// insecure skips certificate verification, for registries the daemon
+	// is configured to trust as insecure.
+	insecure *http.Client
+}
+
+func newRegistryClient(httpClient *http.Client) *registryClient {
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = true
+	insecure := *httpClient
+	insecure.Transport = transport
+	return &registryClient{http: httpClient, insecure: &insecure}
+}
+
+// registryEndpoint is how a registry is reached: with the caller's
+// credentials, if any, and, for an insecure registry, over HTTPS without
+// verification or else plain HTTP, as the daemon does.
+type registryEndpoint struct {
+	Auth     *model.RegistryAuth
+	Insecure bool
+}
+
+type registryManifest struct {
+	MediaType string
+	Body      []byte
+}
+
+func isIndexMediaType(mediaType string) bool {
+	return mediaType == ocispec.MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList
+}
+
+// platformManifests lists the manifests behind digest: the entries of an
+// index, or the image manifest itself (on the given platform) when digest
+// names a single-platform image. Image entries are sized by fetching their
+// manifest and adding up the config and layers.
+func (r *registryClient) platformManifests(ctx context.Context, ep registryEndpoint, named reference.Named, digest string, platforms []ocispec.Platform) ([]model.Manifest, error) {
+	top, err := r.manifest(ctx, ep, named, digest)
+	if err != nil {
+		return nil, err
+	}
+	if !isIndexMediaType(top.MediaType) {
+		manifest := model.Manifest{Digest: digest, MediaType: top.MediaType, Kind: model.ManifestKindImage}
+		if len(platforms) > 0 {
+			manifest.Platform = toPlatform(platforms[0])
+		}
+		if manifest.Size, err = manifestContentSize(top.Body); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", digest, err)
+		}
+		return []model.Manifest{manifest}, nil
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(top.Body, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", digest, err)
+	}
+	result := make([]model.Manifest, 0, len(index.Manifests))
+	for _, d := range index.Manifests {
+		manifest := model.Manifest{
+			Digest:    d.Digest.String(),
+			MediaType: d.MediaType,
+			Kind:      model.ManifestKindImage,
+			Size:      d.Size,
+		}
+		if d.Platform != nil {
+			manifest.Platform = toPlatform(*d.Platform)
+		}
+		// BuildKit attaches provenance and SBOM attestations as extra
+		// index entries with an unknown/unknown platform; only the
+		// attestation manifest itself is counted for those.
+		if d.Annotations[attestationTypeAnnotation] == attestationManifestType {
+			manifest.Kind = model.ManifestKindAttestation
+		} else {
+			m, err := r.manifest(ctx, ep, named, manifest.Digest)
+			if err != nil {
+				return nil, err
+			}
+			if manifest.Size, err = manifestContentSize(m.Body); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest %s: %w", manifest.Digest, err)
+			}
+		}
+		result = append(result, manifest)
+	}
+	return result, nil
+}
+
+// manifestContentSize adds the sizes of the config and layers an image
+// manifest references to the size of the manifest itself.
+func manifestContentSize(body []byte) (int64, error) {
+	var m ocispec.Manifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return 0, err
+	}
+	size := int64(len(body)) + m.Config.Size
+	for _, l := range m.Layers {
+		size += l.Size
+	}
+	return size, nil
+}
+
+// manifest fetches the manifest with the given digest from the repository
+// of named and checks that its content matches the digest.
+func (r *registryClient) manifest(ctx context.Context, ep registryEndpoint, named reference.Named, digest string) (*registryManifest, error) {
+	host := registryHost(named)
+	repo := reference.Path(named)
+	p := "/v2/" + repo + "/manifests/" + digest
+
+	resp, err := r.get(ctx, ep, host, p, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := r.authorize(ctx, ep, challenge, repo)
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = r.get(ctx, ep, host, p, authorization); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s for manifest %s", resp.Status, digest)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, err
+	}
+	if algo, hexSum, ok := strings.Cut(digest, ":"); ok && algo == "sha256" {
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != hexSum {
+			return nil, fmt.Errorf("manifest %s does not match its digest", digest)
+		}
+	}
+	// The mediaType field is optional in OCI manifests, so fall back to
+	// what the registry says it served.
+	var probe struct {
+		MediaType string `json:"mediaType"`
+	}
+	json.Unmarshal(body, &probe)
+	mediaType := probe.MediaType
+	if mediaType == "" {
+		mediaType, _, _ = strings.Cut(resp.Header.Get("Content-Type"), ";")
+	}
+	return &registryManifest{MediaType: strings.TrimSpace(mediaType), Body: body}, nil
+}
+
+// registryHost returns the host serving the repository of named.
+func registryHost(named reference.Named) string {
+	host := reference.Domain(named)
+	if host == dockerHubDomain {
+		return dockerHubRegistry
+	}
+	return host
+}
+
+// get fetches path from host over HTTPS, falling back to plain HTTP for an
+// insecure registry that does not speak TLS.
+func (r *registryClient) get(ctx context.Context, ep registryEndpoint, host, path, authorization string) (*http.Response, error) {
+	u := url.URL{Scheme: "https", Host: host, Path: path}
+	resp, err := r.do(ctx, ep, u.String(), manifestAccept, authorization)
+	if err != nil && ep.Insecure {
+		u.Scheme = "http"
+		resp, err = r.do(ctx, ep, u.String(), manifestAccept, authorization)
+	}
+	return resp, err
+}
+
+func (r *registryClient) do(ctx context.Context, ep registryEndpoint, u, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	client := r.http
+	if ep.Insecure {
+		client = r.insecure
+	}
+	return client.Do(req)
+}
+
+// authorize answers a WWW-Authenticate challenge with the value of the
+// Authorization header to retry with.
+func (r *registryClient) authorize(ctx context.Context, ep registryEndpoint, challenge, repo string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic"):
+		if ep.Auth == nil || ep.Auth.Username == "" {
+			return "", errors.New("registry requires credentials")
+		}
+		return "Basic " + basicCredentials(ep.Auth), nil
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := r.token(ctx, ep, params, repo)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("registry requires unsupported authentication %q", scheme)
+	}
+}
+
+func basicCredentials(auth *model.RegistryAuth) string {
+	return base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+}
+
+// token requests a pull token as described by the parameters of a Bearer
+// challenge, authenticating with the caller's credentials when there are
+// any.
+func (r *registryClient) token(ctx context.Context, ep registryEndpoint, params, repo string) (string, error) {
+	values := parseChallenge(params)
+	if values["realm"] == "" {
+		return "", errors.New("registry authentication challenge has no realm")
+	}
+	u, err := url.Parse(values["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid registry authentication realm: %w", err)
+	}
+	q := u.Query()
+	if values["service"] != "" {
+		q.Set("service", values["service"])
+	}
+	q.Set("scope", "repository:"+repo+":pull")
+	u.RawQuery = q.Encode()
+
+	authorization := ""
+	if ep.Auth != nil && ep.Auth.Username != "" {
+		authorization = "Basic " + basicCredentials(ep.Auth)
+	}
+	resp, err := r.do(ctx, ep, u.String(), "", authorization)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token endpoint returned %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseChallenge splits the comma separated key="value" parameters of a
+// WWW-Authenticate challenge.
+func parseChallenge(s string) map[string]string {
+	values := make(map[string]string)
+	for s != "" {
+		var key, value string
+		key, s, _ = strings.Cut(strings.TrimLeft(s, ", "), "=")
+		if strings.HasPrefix(s, `"`) {
+			value, s, _ = strings.Cut(s[1:], `"`)
+		} else {
+			value, s, _ = strings.Cut(s, ",")
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return values
+}
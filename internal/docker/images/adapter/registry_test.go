@@ -0,0 +1,189 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Digest(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fakeRegistry serves manifests by digest behind an anonymous bearer token.
+func fakeRegistry(t *testing.T, manifests map[string]string) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:library/app:pull", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token": "anon"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anon" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		serveManifest(w, r, manifests)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func serveManifest(w http.ResponseWriter, r *http.Request, manifests map[string]string) {
+	digest := strings.TrimPrefix(r.URL.Path, "/v2/library/app/manifests/")
+	body, ok := manifests[digest]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprint(w, body)
+}
+
+func registryRef(t *testing.T, srv *httptest.Server) reference.Named {
+	t.Helper()
+	host := strings.TrimPrefix(strings.TrimPrefix(srv.URL, "https://"), "http://")
+	named, err := reference.ParseNormalizedNamed(host + "/library/app:latest")
+	require.NoError(t, err)
+	return named
+}
+
+func TestRegistryClient_PlatformManifests(t *testing.T) {
+	amd64 := `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 100}, "layers": [{"size": 1000}, {"size": 2000}]}`
+	arm64 := `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 50}, "layers": [{"size": 500}]}`
+	index := fmt.Sprintf(`{"mediaType": "application/vnd.oci.image.index.v1+json", "manifests": [
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "%s", "size": %d, "platform": {"os": "linux", "architecture": "amd64"}},
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "%s", "size": %d, "platform": {"os": "linux", "architecture": "arm64", "variant": "v8"}},
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:att", "size": 840, "platform": {"os": "unknown", "architecture": "unknown"},
+		 "annotations": {"vnd.docker.reference.type": "attestation-manifest"}}
+	]}`, sha256Digest(amd64), len(amd64), sha256Digest(arm64), len(arm64))
+	srv := fakeRegistry(t, map[string]string{
+		sha256Digest(index): index,
+		sha256Digest(amd64): amd64,
+		sha256Digest(arm64): arm64,
+	})
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "https://") + "/library/app:latest")
+	require.NoError(t, err)
+
+	manifests, err := newRegistryClient(srv.Client()).platformManifests(context.Background(), registryEndpoint{}, named, sha256Digest(index), nil)
+	require.NoError(t, err)
+	require.Len(t, manifests, 3)
+
+	assert.Equal(t, sha256Digest(amd64), manifests[0].Digest)
+	assert.Equal(t, model.Platform{OS: "linux", Architecture: "amd64"}, manifests[0].Platform)
+	assert.Equal(t, int64(len(amd64)+3100), manifests[0].Size)
+	assert.Equal(t, sha256Digest(arm64), manifests[1].Digest)
+	assert.Equal(t, "v8", manifests[1].Platform.Variant)
+	assert.Equal(t, int64(len(arm64)+550), manifests[1].Size)
+	assert.Equal(t, model.ManifestKindAttestation, manifests[2].Kind)
+	assert.Equal(t, int64(840), manifests[2].Size)
+}
+
+func TestRegistryClient_SinglePlatformImage(t *testing.T) {
+	manifest := `{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "config": {"size": 10}, "layers": [{"size": 90}]}`
+	srv := fakeRegistry(t, map[string]string{sha256Digest(manifest): manifest})
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "https://") + "/library/app:1.0")
+	require.NoError(t, err)
+
+	manifests, err := newRegistryClient(srv.Client()).platformManifests(context.Background(), registryEndpoint{}, named, sha256Digest(manifest),
+		[]ocispec.Platform{{OS: "linux", Architecture: "amd64"}})
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	assert.Equal(t, sha256Digest(manifest), manifests[0].Digest)
+	assert.Equal(t, "linux/amd64", manifests[0].Platform.String())
+	assert.Equal(t, int64(len(manifest)+100), manifests[0].Size)
+}
+
+func TestRegistryClient_DigestMismatch(t *testing.T) {
+	srv := fakeRegistry(t, map[string]string{sha256Digest("a"): "tampered"})
+	named, err := reference.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "https://") + "/library/app:latest")
+	require.NoError(t, err)
+
+	_, err = newRegistryClient(srv.Client()).manifest(context.Background(), registryEndpoint{}, named, sha256Digest("a"))
+	assert.ErrorContains(t, err, "does not match its digest")
+}
+
+func TestRegistryClient_BasicAuth(t *testing.T) {
+	manifest := `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 1}, "layers": []}`
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "s3cret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		serveManifest(w, r, map[string]string{sha256Digest(manifest): manifest})
+	}))
+	t.Cleanup(srv.Close)
+	client := newRegistryClient(srv.Client())
+
+	_, err := client.manifest(context.Background(), registryEndpoint{}, registryRef(t, srv), sha256Digest(manifest))
+	assert.ErrorContains(t, err, "requires credentials")
+
+	ep := registryEndpoint{Auth: &model.RegistryAuth{Username: "bot", Password: "s3cret"}}
+	m, err := client.manifest(context.Background(), ep, registryRef(t, srv), sha256Digest(manifest))
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", m.MediaType)
+}
+
+func TestRegistryClient_BearerWithCredentials(t *testing.T) {
+	manifest := `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 1}, "layers": []}`
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bot" || pass != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"access_token": "private"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer private" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		serveManifest(w, r, map[string]string{sha256Digest(manifest): manifest})
+	}))
+	t.Cleanup(srv.Close)
+
+	ep := registryEndpoint{Auth: &model.RegistryAuth{Username: "bot", Password: "s3cret"}}
+	_, err := newRegistryClient(srv.Client()).manifest(context.Background(), ep, registryRef(t, srv), sha256Digest(manifest))
+	assert.NoError(t, err)
+}
+
+func TestRegistryClient_InsecureRegistry(t *testing.T) {
+	manifest := `{"mediaType": "application/vnd.oci.image.manifest.v1+json", "config": {"size": 1}, "layers": []}`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveManifest(w, r, map[string]string{sha256Digest(manifest): manifest})
+	})
+	// The default client trusts neither a self-signed certificate nor
+	// plain HTTP.
+	client := newRegistryClient(&http.Client{})
+
+	selfSigned := httptest.NewTLSServer(handler)
+	t.Cleanup(selfSigned.Close)
+	_, err := client.manifest(context.Background(), registryEndpoint{}, registryRef(t, selfSigned), sha256Digest(manifest))
+	assert.Error(t, err)
+	_, err = client.manifest(context.Background(), registryEndpoint{Insecure: true}, registryRef(t, selfSigned), sha256Digest(manifest))
+	assert.NoError(t, err)
+
+	plain := httptest.NewServer(handler)
+	t.Cleanup(plain.Close)
+	_, err = client.manifest(context.Background(), registryEndpoint{}, registryRef(t, plain), sha256Digest(manifest))
+	assert.Error(t, err)
+	_, err = client.manifest(context.Background(), registryEndpoint{Insecure: true}, registryRef(t, plain), sha256Digest(manifest))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,215 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// Scanner runs a vulnerability scanner against an image archive produced by
+// `docker save`.
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, archive io.Reader) ([]model.Vulnerability, error)
+}
+
+// NewScanner returns the scanner for kind ("trivy" or "grype"). binary
+// overrides the executable looked up in PATH; server points Trivy at a
+// remote Trivy server instead of its local vulnerability database.
+func NewScanner(kind, binary, server string) (Scanner, error) {
+	switch strings.ToLower(kind) {
+	case "trivy":
+		if binary == "" {
+			binary = "trivy"
+		}
+		return &TrivyScanner{binary: binary, server: server}, nil
+	case "grype":
+		if server != "" {
+			return nil, fmt.Errorf("grype does not support a scanner server")
+		}
+		if binary == "" {
+			binary = "grype"
+		}
+		return &GrypeScanner{binary: binary}, nil
+	default:
+		return nil, fmt.Errorf("unsupported scanner %q", kind)
+	}
+}
+
+type TrivyScanner struct {
+	binary string
+	server string
+}
+
+var _ Scanner = (*TrivyScanner)(nil)
+
+func (s *TrivyScanner) Name() string {
+	return "trivy"
+}
+
+func (s *TrivyScanner) Scan(ctx context.Context, archive io.Reader) ([]model.Vulnerability, error) {
+	path, cleanup, err := spoolArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	args := []string{"image", "--quiet", "--format", "json", "--input", path}
+	if s.server != "" {
+		args = append(args, "--server", s.server)
+	}
+	out, err := runTool(ctx, s.binary, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseTrivyReport(out)
+}
+
+type GrypeScanner struct {
+	binary string
+}
+
+var _ Scanner = (*GrypeScanner)(nil)
+
+func (s *GrypeScanner) Name() string {
+	return "grype"
+}
+
+func (s *GrypeScanner) Scan(ctx context.Context, archive io.Reader) ([]model.Vulnerability, error) {
+	path, cleanup, err := spoolArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	out, err := runTool(ctx, s.binary, "docker-archive:"+path, "--output", "json", "--quiet")
+	if err != nil {
+		return nil, err
+	}
+	return parseGrypeReport(out)
+}
+
+// spoolArchive writes the archive to a temporary file, since both scanners
+// need a seekable path rather than a stream.
+func spoolArchive(archive io.Reader) (string, func(), error) {
+	f, err := os.CreateTemp("", "orcahub-scan-*.tar")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create scan archive: %w", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := io.Copy(f, archive); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write scan archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write scan archive: %w", err)
+	}
+	return f.Name(), cleanup, nil
+}
+
+func runTool(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("%s failed: %w", binary, err)
+		}
+		return nil, fmt.Errorf("%s failed: %w: %s", binary, err, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+func parseTrivyReport(data []byte) ([]model.Vulnerability, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string `json:"VulnerabilityID"`
+				PkgName          string `json:"PkgName"`
+				InstalledVersion string `json:"InstalledVersion"`
+				FixedVersion     string `json:"FixedVersion"`
+				Severity         string `json:"Severity"`
+				Title            string `json:"Title"`
+				PrimaryURL       string `json:"PrimaryURL"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	result := []model.Vulnerability{}
+	for _, r := range report.Results {
+		for _, v := range r.Vulnerabilities {
+			result = append(result, model.Vulnerability{
+				ID:               v.VulnerabilityID,
+				PkgName:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Severity:         normalizeSeverity(v.Severity),
+				Title:            v.Title,
+				URL:              v.PrimaryURL,
+			})
+		}
+	}
+	return result, nil
+}
+
+func parseGrypeReport(data []byte) ([]model.Vulnerability, error) {
+	var report struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID          string `json:"id"`
+				Severity    string `json:"severity"`
+				DataSource  string `json:"dataSource"`
+				Description string `json:"description"`
+				Fix         struct {
+					Versions []string `json:"versions"`
+				} `json:"fix"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse grype report: %w", err)
+	}
+
+	result := []model.Vulnerability{}
+	for _, m := range report.Matches {
+		result = append(result, model.Vulnerability{
+			ID:               m.Vulnerability.ID,
+			PkgName:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			Severity:         normalizeSeverity(m.Vulnerability.Severity),
+			Title:            m.Vulnerability.Description,
+			URL:              m.Vulnerability.DataSource,
+		})
+	}
+	return result, nil
+}
+
+func normalizeSeverity(severity string) string {
+	switch s := strings.ToUpper(severity); s {
+	case model.SeverityCritical, model.SeverityHigh, model.SeverityMedium, model.SeverityLow:
+		return s
+	case "NEGLIGIBLE":
+		return model.SeverityLow
+	default:
+		return model.SeverityUnknown
+	}
+}
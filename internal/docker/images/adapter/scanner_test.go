@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"testing"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScanner(t *testing.T) {
+	s, err := NewScanner("Trivy", "", "http://trivy:4954")
+	require.NoError(t, err)
+	assert.Equal(t, "trivy", s.Name())
+
+	s, err = NewScanner("grype", "/opt/grype", "")
+	require.NoError(t, err)
+	assert.Equal(t, "grype", s.Name())
+
+	_, err = NewScanner("grype", "", "http://trivy:4954")
+	assert.Error(t, err)
+
+	_, err = NewScanner("clair", "", "")
+	assert.Error(t, err)
+}
+
+func TestParseTrivyReport(t *testing.T) {
+	data := []byte(`{
+		"Results": [
+			{"Target": "alpine", "Vulnerabilities": [
+				{"VulnerabilityID": "CVE-2024-0001", "PkgName": "openssl", "InstalledVersion": "3.1.0",
+				 "FixedVersion": "3.1.4", "Severity": "HIGH", "Title": "bad", "PrimaryURL": "https://avd.example/CVE-2024-0001"}
+			]},
+			{"Target": "app", "Vulnerabilities": null}
+		]
+	}`)
+
+	vulns, err := parseTrivyReport(data)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Vulnerability{{
+		ID:               "CVE-2024-0001",
+		PkgName:          "openssl",
+		InstalledVersion: "3.1.0",
+		FixedVersion:     "3.1.4",
+		Severity:         model.SeverityHigh,
+		Title:            "bad",
+		URL:              "https://avd.example/CVE-2024-0001",
+	}}, vulns)
+}
+
+func TestParseGrypeReport(t *testing.T) {
+	data := []byte(`{
+		"matches": [
+			{"vulnerability": {"id": "CVE-2024-0002", "severity": "Negligible", "dataSource": "https://nvd.example",
+			  "fix": {"versions": ["1.2.3", "2.0.0"]}},
+			 "artifact": {"name": "zlib", "version": "1.2.0"}}
+		]
+	}`)
+
+	vulns, err := parseGrypeReport(data)
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "zlib", vulns[0].PkgName)
+	assert.Equal(t, "1.2.3, 2.0.0", vulns[0].FixedVersion)
+	assert.Equal(t, model.SeverityLow, vulns[0].Severity)
+}
+
+func TestParseTrivyReport_Invalid(t *testing.T) {
+	_, err := parseTrivyReport([]byte("not json"))
+	assert.Error(t, err)
+}
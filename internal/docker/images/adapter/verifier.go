@@ -0,0 +1,241 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// Verifier checks the signatures of an image in its registry.
+type Verifier interface {
+	Name() string
+	// Verify checks ref, a repo@digest reference. Missing or invalid
+	// signatures yield an unverified report; an error means the verifier
+	// itself could not be run.
+	Verify(ctx context.Context, ref string) (*model.SignatureReport, error)
+}
+
+type VerifierOptions struct {
+	Key              string   // public key file or KMS URI
+	Identity         string   // certificate identity for keyless verification
+	OIDCIssuer       string   // certificate OIDC issuer for keyless verification
+	AttestationTypes []string // cosign predicate types to verify, e.g. "slsaprovenance"
+}
+
+// NewVerifier returns the verifier for kind ("cosign" or "notation"). binary
+// overrides the executable looked up in PATH. Notation takes its keys from
+// its own trust store and trust policy, so opts only apply to cosign.
+func NewVerifier(kind, binary string, opts VerifierOptions) (Verifier, error) {
+	switch strings.ToLower(kind) {
+	case "cosign":
+		if opts.Key == "" && (opts.Identity == "" || opts.OIDCIssuer == "") {
+			return nil, errors.New("cosign needs a public key or a keyless identity and OIDC issuer")
+		}
+		if binary == "" {
+			binary = "cosign"
+		}
+		return &CosignVerifier{binary: binary, opts: opts}, nil
+	case "notation":
+		if opts.Key != "" || opts.Identity != "" || opts.OIDCIssuer != "" || len(opts.AttestationTypes) > 0 {
+			return nil, errors.New("notation is configured through its trust policy, not verifier options")
+		}
+		if binary == "" {
+			binary = "notation"
+		}
+		return &NotationVerifier{binary: binary}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature verifier %q", kind)
+	}
+}
+
+type CosignVerifier struct {
+	binary string
+	opts   VerifierOptions
+}
+
+var _ Verifier = (*CosignVerifier)(nil)
+
+func (v *CosignVerifier) Name() string {
+	return "cosign"
+}
+
+func (v *CosignVerifier) Verify(ctx context.Context, ref string) (*model.SignatureReport, error) {
+	report := &model.SignatureReport{Ref: ref, Verifier: v.Name()}
+	out, err := runTool(ctx, v.binary, append(append([]string{"verify", "--output", "json"}, v.identityArgs()...), ref)...)
+	if err != nil {
+		if !isExitError(err) {
+			return nil, err
+		}
+		report.Error = err.Error()
+		return report, nil
+	}
+	if report.Signatures, err = parseCosignSignatures(out); err != nil {
+		return nil, err
+	}
+	report.Verified = len(report.Signatures) > 0
+
+	for _, predicateType := range v.opts.AttestationTypes {
+		args := append(append([]string{"verify-attestation", "--type", predicateType}, v.identityArgs()...), ref)
+		out, err := runTool(ctx, v.binary, args...)
+		if err != nil {
+			if isExitError(err) {
+				continue // no attestation of this type
+			}
+			return nil, err
+		}
+		attestations, err := parseCosignAttestations(out)
+		if err != nil {
+			return nil, err
+		}
+		report.Attestations = append(report.Attestations, attestations...)
+	}
+	return report, nil
+}
+
+func (v *CosignVerifier) identityArgs() []string {
+	if v.opts.Key != "" {
+		return []string{"--key", v.opts.Key}
+	}
+	return []string{"--certificate-identity", v.opts.Identity, "--certificate-oidc-issuer", v.opts.OIDCIssuer}
+}
+
+type NotationVerifier struct {
+	binary string
+}
+
+var _ Verifier = (*NotationVerifier)(nil)
+
+func (v *NotationVerifier) Name() string {
+	return "notation"
+}
+
+func (v *NotationVerifier) Verify(ctx context.Context, ref string) (*model.SignatureReport, error) {
+	report := &model.SignatureReport{Ref: ref, Verifier: v.Name()}
+	if _, err := runTool(ctx, v.binary, "verify", ref); err != nil {
+		if !isExitError(err) {
+			return nil, err
+		}
+		report.Error = err.Error()
+		return report, nil
+	}
+	report.Verified = true
+
+	// verify has no machine readable output; inspect lists the signatures
+	// and their certificate chains.
+	out, err := runTool(ctx, v.binary, "inspect", "--output", "json", ref)
+	if err != nil {
+		return nil, err
+	}
+	if report.Signatures, err = parseNotationSignatures(out); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func isExitError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
+
+func parseCosignSignatures(data []byte) ([]model.Signature, error) {
+	var payloads []struct {
+		Critical struct {
+			Identity struct {
+				DockerReference string `json:"docker-reference"`
+			} `json:"identity"`
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+		Optional map[string]any `json:"optional"`
+	}
+	if err := json.Unmarshal(data, &payloads); err != nil {
+		return nil, fmt.Errorf("failed to parse cosign output: %w", err)
+	}
+	sigs := make([]model.Signature, 0, len(payloads))
+	for _, p := range payloads {
+		sig := model.Signature{
+			DockerReference: p.Critical.Identity.DockerReference,
+			Digest:          p.Critical.Image.DockerManifestDigest,
+		}
+		sig.Issuer, _ = p.Optional["Issuer"].(string)
+		sig.Subject, _ = p.Optional["Subject"].(string)
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// parseCosignAttestations decodes the DSSE envelopes cosign prints, one per
+// line, and extracts the in-toto statement of each.
+func parseCosignAttestations(data []byte) ([]model.Attestation, error) {
+	var attestations []model.Attestation
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var envelope struct {
+			Payload string `json:"payload"`
+		}
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			return nil, fmt.Errorf("failed to parse cosign attestation: %w", err)
+		}
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode cosign attestation: %w", err)
+		}
+		var statement struct {
+			PredicateType string `json:"predicateType"`
+			Subject       []struct {
+				Digest map[string]string `json:"digest"`
+			} `json:"subject"`
+		}
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			return nil, fmt.Errorf("failed to parse in-toto statement: %w", err)
+		}
+		attestation := model.Attestation{PredicateType: statement.PredicateType}
+		for _, s := range statement.Subject {
+			if d, ok := s.Digest["sha256"]; ok {
+				attestation.Subjects = append(attestation.Subjects, "sha256:"+d)
+			}
+		}
+		attestations = append(attestations, attestation)
+	}
+	return attestations, scanner.Err()
+}
+
+func parseNotationSignatures(data []byte) ([]model.Signature, error) {
+	var inspect struct {
+		Signatures []struct {
+			Digest       string `json:"digest"`
+			Certificates []struct {
+				Subject string `json:"subject"`
+				Issuer  string `json:"issuer"`
+			} `json:"certificates"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal(data, &inspect); err != nil {
+		return nil, fmt.Errorf("failed to parse notation output: %w", err)
+	}
+	sigs := make([]model.Signature, 0, len(inspect.Signatures))
+	for _, s := range inspect.Signatures {
+		sig := model.Signature{Digest: s.Digest}
+		if len(s.Certificates) > 0 {
+			// The leaf certificate comes first in the chain.
+			sig.Subject = s.Certificates[0].Subject
+			sig.Issuer = s.Certificates[0].Issuer
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
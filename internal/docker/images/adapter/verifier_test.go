@@ -0,0 +1,99 @@
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVerifier(t *testing.T) {
+	v, err := NewVerifier("Cosign", "", VerifierOptions{Key: "/keys/cosign.pub"})
+	require.NoError(t, err)
+	assert.Equal(t, "cosign", v.Name())
+
+	_, err = NewVerifier("cosign", "", VerifierOptions{Identity: "ci@example.com"})
+	assert.Error(t, err)
+
+	v, err = NewVerifier("notation", "", VerifierOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "notation", v.Name())
+
+	_, err = NewVerifier("notation", "", VerifierOptions{Key: "/keys/cosign.pub"})
+	assert.Error(t, err)
+
+	_, err = NewVerifier("gpg", "", VerifierOptions{})
+	assert.Error(t, err)
+}
+
+func TestParseCosignSignatures(t *testing.T) {
+	data := []byte(`[{
+		"critical": {
+			"identity": {"docker-reference": "ghcr.io/acme/app"},
+			"image": {"docker-manifest-digest": "sha256:abc"},
+			"type": "cosign container image signature"
+		},
+		"optional": {"Issuer": "https://token.actions.githubusercontent.com", "Subject": "https://github.com/acme/app/.github/workflows/release.yml@refs/heads/main"}
+	}]`)
+
+	sigs, err := parseCosignSignatures(data)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Signature{{
+		DockerReference: "ghcr.io/acme/app",
+		Digest:          "sha256:abc",
+		Issuer:          "https://token.actions.githubusercontent.com",
+		Subject:         "https://github.com/acme/app/.github/workflows/release.yml@refs/heads/main",
+	}}, sigs)
+}
+
+func TestParseCosignAttestations(t *testing.T) {
+	statement := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","subject":[{"name":"ghcr.io/acme/app","digest":{"sha256":"abc"}}]}`
+	data := []byte(`{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[]}` + "\n")
+
+	attestations, err := parseCosignAttestations(data)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Attestation{{
+		PredicateType: "https://slsa.dev/provenance/v0.2",
+		Subjects:      []string{"sha256:abc"},
+	}}, attestations)
+}
+
+func TestParseNotationSignatures(t *testing.T) {
+	data := []byte(`{"mediaType": "application/vnd.oci.image.manifest.v1+json", "signatures": [
+		{"digest": "sha256:sig", "certificates": [
+			{"subject": "CN=acme.io,O=Acme", "issuer": "CN=Acme CA"},
+			{"subject": "CN=Acme CA", "issuer": "CN=Acme CA"}
+		]}
+	]}`)
+
+	sigs, err := parseNotationSignatures(data)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Signature{{Digest: "sha256:sig", Subject: "CN=acme.io,O=Acme", Issuer: "CN=Acme CA"}}, sigs)
+}
+
+func TestCosignVerifier_Unsigned(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "cosign")
+	script := "#!/bin/sh\necho 'Error: no signatures found' >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0o755))
+
+	v, err := NewVerifier("cosign", binary, VerifierOptions{Key: "cosign.pub"})
+	require.NoError(t, err)
+
+	report, err := v.Verify(context.Background(), "ghcr.io/acme/app@sha256:abc")
+	require.NoError(t, err)
+	assert.False(t, report.Verified)
+	assert.Contains(t, report.Error, "no signatures found")
+}
+
+func TestCosignVerifier_MissingBinary(t *testing.T) {
+	v, err := NewVerifier("cosign", filepath.Join(t.TempDir(), "missing"), VerifierOptions{Key: "cosign.pub"})
+	require.NoError(t, err)
+
+	_, err = v.Verify(context.Background(), "ghcr.io/acme/app@sha256:abc")
+	assert.Error(t, err)
+}
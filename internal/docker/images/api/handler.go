@@ -1,14 +1,19 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/docker/docker/api/types/registry"
 	"github.com/gin-gonic/gin"
 	mappers "github.com/rivernova/orcahub/internal/docker/images/api/mappers"
 	requests "github.com/rivernova/orcahub/internal/docker/images/api/requests"
 	responses "github.com/rivernova/orcahub/internal/docker/images/api/responses"
 	domain "github.com/rivernova/orcahub/internal/docker/images/domain"
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
+	"github.com/rivernova/orcahub/internal/httputil"
 )
 
 type Handler struct {
@@ -62,7 +67,7 @@ func (h *Handler) Pull(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	opts := model.PullOptions{Image: req.Image}
+	opts := model.PullOptions{Image: req.Image, Platform: req.Platform}
 	if req.Auth != nil {
 		opts.Auth = &model.RegistryAuth{
 			Username:      req.Auth.Username,
@@ -71,7 +76,11 @@ func (h *Handler) Pull(c *gin.Context) {
 		}
 	}
 	if err := h.service.Pull(c.Request.Context(), opts); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidPlatform) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "image pulled"})
@@ -103,9 +112,25 @@ func (h *Handler) Build(c *gin.Context) {
 }
 
 func (h *Handler) Prune(c *gin.Context) {
-	result, err := h.service.Prune(c.Request.Context())
+	var query requests.PruneImagesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Prune(c.Request.Context(), model.PruneOptions{
+		DanglingOnly:  query.Dangling,
+		Until:         query.Until,
+		Labels:        query.Labels,
+		ExcludeLabels: query.ExcludeLabels,
+		KeepLast:      query.KeepLast,
+		DryRun:        query.DryRun,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidPruneOptions) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, result)
@@ -136,3 +161,213 @@ func (h *Handler) History(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"history": history})
 }
+
+func (h *Handler) Dependents(c *gin.Context) {
+	id := c.Param("id")
+	deps, err := h.service.Dependents(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToImageDependentsResponse(deps))
+}
+
+func (h *Handler) Layers(c *gin.Context) {
+	id := c.Param("id")
+	var query requests.ImageLayersRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	analysis, err := h.service.Layers(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToLayerAnalysisResponse(analysis, query.Files))
+}
+
+func (h *Handler) Save(c *gin.Context) {
+	var query requests.SaveImageRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	refs := append([]string{c.Param("id")}, query.Refs...)
+	reader, err := h.service.Save(c.Request.Context(), refs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+	c.DataFromReader(http.StatusOK, -1, "application/x-tar", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, archiveName(refs[0])),
+	})
+}
+
+// Load accepts the tarball either as the raw request body or as the "file"
+// field of a multipart form. Multipart uploads are streamed, not buffered.
+func (h *Handler) Load(c *gin.Context) {
+	input, err := httputil.UploadReader(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Load(c.Request.Context(), input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToLoadImageResponse(result))
+}
+
+func (h *Handler) Scan(c *gin.Context) {
+	id := c.Param("id")
+	report, err := h.service.Scan(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, mappers.ToScanReportResponse(report))
+}
+
+func (h *Handler) Vulnerabilities(c *gin.Context) {
+	id := c.Param("id")
+	var query requests.ScanReportRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.service.ScanReport(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(scanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if len(query.Severity) > 0 {
+		wanted := make(map[string]bool, len(query.Severity))
+		for _, s := range query.Severity {
+			wanted[strings.ToUpper(strings.TrimSpace(s))] = true
+		}
+		filtered := make([]model.Vulnerability, 0, len(report.Vulnerabilities))
+		for _, v := range report.Vulnerabilities {
+			if wanted[v.Severity] {
+				filtered = append(filtered, v)
+			}
+		}
+		report.Vulnerabilities = filtered
+	}
+	c.JSON(http.StatusOK, mappers.ToScanReportResponse(report))
+}
+
+// registryAuthHeader carries registry credentials encoded as the Docker API
+// expects them: base64url encoded JSON with username, password and
+// serveraddress.
+const registryAuthHeader = "X-Registry-Auth"
+
+// Manifests lists the platforms of an image. With remote=true they are read
+// from the registry, authenticating with the X-Registry-Auth header when it
+// is sent.
+func (h *Handler) Manifests(c *gin.Context) {
+	id := c.Param("id")
+	var query requests.ImageManifestsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts := model.ManifestOptions{Remote: query.Remote}
+	if header := c.GetHeader(registryAuthHeader); header != "" && query.Remote {
+		auth, err := registry.DecodeAuthConfig(header)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.Auth = &model.RegistryAuth{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			ServerAddress: auth.ServerAddress,
+		}
+	}
+	list, err := h.service.Manifests(c.Request.Context(), id, opts)
+	if err != nil {
+		status := http.StatusNotFound
+		if query.Remote {
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToManifestListResponse(list))
+}
+
+func (h *Handler) Signatures(c *gin.Context) {
+	id := c.Param("id")
+	var query requests.SignatureRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	report, err := h.service.VerifySignature(c.Request.Context(), id, query.Refresh)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrVerifierNotConfigured) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToSignatureReportResponse(report))
+}
+
+func (h *Handler) SBOM(c *gin.Context) {
+	id := c.Param("id")
+	var query requests.SBOMRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.Format != model.SBOMFormatSPDX && query.Format != model.SBOMFormatCycloneDX {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported sbom format %q", query.Format)})
+		return
+	}
+	sbom, err := h.service.SBOM(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := strings.TrimSuffix(archiveName(sbom.Name), ".tar") + "." + strings.TrimSuffix(query.Format, "-json") + ".json"
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if query.Format == model.SBOMFormatCycloneDX {
+		c.JSON(http.StatusOK, mappers.ToCycloneDXDocument(sbom))
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToSPDXDocument(sbom))
+}
+
+func (h *Handler) Updates(c *gin.Context) {
+	var query requests.ImageUpdatesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updates, err := h.service.Updates(c.Request.Context(), query.Refresh)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToImageUpdateResponseList(updates))
+}
+
+func archiveName(ref string) string {
+	return strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(ref) + ".tar"
+}
+
+func scanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrScannerNotConfigured), errors.Is(err, domain.ErrScanQueueFull):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrScanNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -5,15 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/docker/docker/api/types/registry"
 	"github.com/gin-gonic/gin"
 	imageapi "github.com/rivernova/orcahub/internal/docker/images/api"
+	"github.com/rivernova/orcahub/internal/docker/images/api/responses"
+	"github.com/rivernova/orcahub/internal/docker/images/domain"
 	"github.com/rivernova/orcahub/internal/docker/images/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func init() { gin.SetMode(gin.TestMode) }
@@ -49,8 +56,8 @@ func (m *mockImageService) Build(ctx context.Context, opts model.BuildOptions) (
 	return args.Get(0).(*model.BuildResult), args.Error(1)
 }
 
-func (m *mockImageService) Prune(ctx context.Context) (model.PruneResult, error) {
-	args := m.Called(ctx)
+func (m *mockImageService) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := m.Called(ctx, opts)
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
 func (m *mockImageService) Tag(ctx context.Context, opts model.TagOptions) error {
@@ -62,16 +69,103 @@ func (m *mockImageService) History(ctx context.Context, id string) ([]model.Hist
 	return args.Get(0).([]model.HistoryEntry), args.Error(1)
 }
 
+func (m *mockImageService) Layers(ctx context.Context, id string) (*model.LayerAnalysis, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LayerAnalysis), args.Error(1)
+}
+
+func (m *mockImageService) Save(ctx context.Context, refs []string) (io.ReadCloser, error) {
+	args := m.Called(ctx, refs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockImageService) Load(ctx context.Context, input io.Reader) (*model.LoadResult, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoadResult), args.Error(1)
+}
+
+func (m *mockImageService) Scan(ctx context.Context, id string) (*model.ScanReport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScanReport), args.Error(1)
+}
+
+func (m *mockImageService) ScanReport(ctx context.Context, id string) (*model.ScanReport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScanReport), args.Error(1)
+}
+
+func (m *mockImageService) Updates(ctx context.Context, refresh bool) ([]model.ImageUpdate, error) {
+	args := m.Called(ctx, refresh)
+	return args.Get(0).([]model.ImageUpdate), args.Error(1)
+}
+
+func (m *mockImageService) SBOM(ctx context.Context, id string) (*model.SBOM, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SBOM), args.Error(1)
+}
+
+func (m *mockImageService) Manifests(ctx context.Context, ref string, opts model.ManifestOptions) (*model.ManifestList, error) {
+	args := m.Called(ctx, ref, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ManifestList), args.Error(1)
+}
+
+func (m *mockImageService) VerifySignature(ctx context.Context, id string, refresh bool) (*model.SignatureReport, error) {
+	args := m.Called(ctx, id, refresh)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SignatureReport), args.Error(1)
+}
+
+func (m *mockImageService) Dependents(ctx context.Context, id string) (*model.ImageDependents, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImageDependents), args.Error(1)
+}
+
 func setupImageRouter(svc *mockImageService) *gin.Engine {
 	r := gin.New()
 	h := imageapi.NewHandler(svc)
 	r.GET("/images", h.List)
+	r.GET("/images/updates", h.Updates)
 	r.GET("/images/:id", h.Inspect)
 	r.GET("/images/:id/history", h.History)
+	r.GET("/images/:id/dependents", h.Dependents)
+	r.GET("/images/:id/layers", h.Layers)
+	r.GET("/images/:id/manifests", h.Manifests)
+	r.GET("/images/:id/save", h.Save)
+	r.GET("/images/:id/sbom", h.SBOM)
+	r.GET("/images/:id/signatures", h.Signatures)
+	r.GET("/images/:id/vulnerabilities", h.Vulnerabilities)
+	r.POST("/images/:id/scan", h.Scan)
 	r.DELETE("/images/:id", h.Delete)
 	r.POST("/images/pull", h.Pull)
 	r.POST("/images/build", h.Build)
 	r.POST("/images/tag", h.Tag)
+	r.POST("/images/load", h.Load)
 	r.POST("/images/prune", h.Prune)
 	return r
 }
@@ -174,6 +268,22 @@ func TestImageHandler_Pull_BadRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestImageHandler_Pull_InvalidPlatform(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Pull", mock.Anything, model.PullOptions{Image: "nginx:latest", Platform: "arm64"}).
+		Return(fmt.Errorf("%w %q", domain.ErrInvalidPlatform, "arm64"))
+
+	body, _ := json.Marshal(map[string]string{"image": "nginx:latest", "platform": "arm64"})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/images/pull", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestImageHandler_Build_OK(t *testing.T) {
 	svc := &mockImageService{}
 	r := setupImageRouter(svc)
@@ -201,7 +311,7 @@ func TestImageHandler_Prune_OK(t *testing.T) {
 	r := setupImageRouter(svc)
 
 	expected := model.PruneResult{Deleted: []string{"img1"}, SpaceReclaimed: 4096}
-	svc.On("Prune", mock.Anything).Return(expected, nil)
+	svc.On("Prune", mock.Anything, model.PruneOptions{}).Return(expected, nil)
 
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/images/prune", nil))
@@ -211,3 +321,448 @@ func TestImageHandler_Prune_OK(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, float64(4096), resp["space_reclaimed"])
 }
+
+func TestImageHandler_Prune_Options(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Prune", mock.Anything, model.PruneOptions{
+		Until:         "24h",
+		Labels:        []string{"env=dev", "team"},
+		ExcludeLabels: []string{"keep"},
+		KeepLast:      3,
+		DryRun:        true,
+	}).Return(model.PruneResult{Deleted: []string{"sha256:old"}, SpaceReclaimed: 2048, DryRun: true}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost,
+		"/images/prune?until=24h&label=env=dev&label=team&exclude_label=keep&keep_last=3&dry_run=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["dry_run"])
+	assert.Equal(t, float64(2048), resp["space_reclaimed"])
+}
+
+func TestImageHandler_Prune_InvalidOptions(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Prune", mock.Anything, model.PruneOptions{Until: "soon"}).
+		Return(model.PruneResult{}, fmt.Errorf("%w: cannot parse until", domain.ErrInvalidPruneOptions))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/images/prune?until=soon", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImageHandler_Updates_OK(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Updates", mock.Anything, true).Return([]model.ImageUpdate{
+		{ContainerID: "c1", Image: "nginx:latest", UpdateAvailable: true},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/updates?refresh=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, true, resp[0]["update_available"])
+}
+
+func TestImageHandler_Updates_Error(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Updates", mock.Anything, false).Return([]model.ImageUpdate{}, errors.New("daemon error"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/updates", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestImageHandler_Save_OK(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Save", mock.Anything, []string{"nginx:latest", "redis:7"}).
+		Return(io.NopCloser(bytes.NewBufferString("tarball")), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/save?refs=redis:7", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-tar", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "nginx_latest.tar")
+	assert.Equal(t, "tarball", w.Body.String())
+}
+
+func TestImageHandler_Save_Error(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Save", mock.Anything, []string{"nope"}).Return(nil, errors.New("no such image"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nope/save", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestImageHandler_Load_Multipart(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	var uploaded string
+	svc.On("Load", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			data, _ := io.ReadAll(args.Get(1).(io.Reader))
+			uploaded = string(data)
+		}).
+		Return(&model.LoadResult{Loaded: []string{"nginx:latest"}}, nil)
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	fw, _ := mw.CreateFormFile("file", "nginx.tar")
+	fw.Write([]byte("tarball"))
+	mw.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/images/load", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "tarball", uploaded)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, []interface{}{"nginx:latest"}, resp["loaded"])
+}
+
+func TestImageHandler_Load_MissingFile(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	mw.WriteField("other", "value")
+	mw.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/images/load", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImageHandler_Layers_OK(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Layers", mock.Anything, "sha256:abc").Return(&model.LayerAnalysis{
+		Layers: []model.Layer{{Index: 0, Size: 10, Files: []model.LayerFile{
+			{Path: "/etc/config", Size: 10, Change: model.FileAdded},
+		}}},
+		TotalSize:  10,
+		Efficiency: 1,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/sha256:abc/layers", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Layers []struct {
+			FileCount int                      `json:"file_count"`
+			Files     []map[string]interface{} `json:"files"`
+		} `json:"layers"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 1, resp.Layers[0].FileCount)
+	assert.Equal(t, "added", resp.Layers[0].Files[0]["change"])
+}
+
+func TestImageHandler_Layers_WithoutFiles(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Layers", mock.Anything, "sha256:abc").Return(&model.LayerAnalysis{
+		Layers: []model.Layer{{Files: []model.LayerFile{{Path: "/a", Change: model.FileAdded}}}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/sha256:abc/layers?files=false", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"files"`)
+	assert.Contains(t, w.Body.String(), `"file_count":1`)
+}
+
+func TestImageHandler_Scan_Accepted(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Scan", mock.Anything, "nginx:latest").
+		Return(&model.ScanReport{ImageID: "sha256:abc", Status: model.ScanQueued}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/images/nginx:latest/scan", nil))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "queued", resp["status"])
+}
+
+func TestImageHandler_Scan_NotConfigured(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Scan", mock.Anything, "nginx:latest").Return(nil, domain.ErrScannerNotConfigured)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/images/nginx:latest/scan", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestImageHandler_Vulnerabilities_FilterSeverity(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("ScanReport", mock.Anything, "sha256:abc").Return(&model.ScanReport{
+		Status: model.ScanCompleted,
+		Counts: model.SeverityCounts{Critical: 1, Low: 1},
+		Vulnerabilities: []model.Vulnerability{
+			{ID: "CVE-1", Severity: model.SeverityCritical},
+			{ID: "CVE-2", Severity: model.SeverityLow},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/sha256:abc/vulnerabilities?severity=critical,high", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Counts          map[string]int           `json:"counts"`
+		Vulnerabilities []map[string]interface{} `json:"vulnerabilities"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Vulnerabilities, 1)
+	assert.Equal(t, "CVE-1", resp.Vulnerabilities[0]["id"])
+	assert.Equal(t, 1, resp.Counts["low"])
+}
+
+func TestImageHandler_Vulnerabilities_NotScanned(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("ScanReport", mock.Anything, "sha256:abc").Return(nil, domain.ErrScanNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/sha256:abc/vulnerabilities", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func testSBOM() *model.SBOM {
+	return &model.SBOM{
+		ImageID: "sha256:abc",
+		Name:    "nginx:latest",
+		Created: 1700000000,
+		OS:      model.OSRelease{ID: "debian", VersionID: "12"},
+		Packages: []model.Package{
+			{Name: "libc6", Version: "2.36-9", Type: model.PackageTypeDeb, PURL: "pkg:deb/debian/libc6@2.36-9"},
+		},
+	}
+}
+
+func TestImageHandler_SBOM_SPDX(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("SBOM", mock.Anything, "nginx:latest").Return(testSBOM(), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/sbom", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "nginx_latest.spdx.json")
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "SPDX-2.3", doc["spdxVersion"])
+	assert.Len(t, doc["packages"], 2)
+}
+
+func TestImageHandler_SBOM_CycloneDX(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("SBOM", mock.Anything, "nginx:latest").Return(testSBOM(), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/sbom?format=cyclonedx-json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "CycloneDX", doc["bomFormat"])
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc["serialNumber"])
+	assert.Len(t, doc["components"], 2)
+}
+
+func TestImageHandler_SBOM_InvalidFormat(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/sbom?format=xml", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "SBOM", mock.Anything, mock.Anything)
+}
+
+func TestImageHandler_Manifests_Remote(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Manifests", mock.Anything, "nginx:latest", model.ManifestOptions{Remote: true}).Return(&model.ManifestList{
+		Ref:       "nginx:latest",
+		Digest:    "sha256:index",
+		MediaType: "application/vnd.oci.image.index.v1+json",
+		Remote:    true,
+		Manifests: []model.Manifest{
+			{Kind: model.ManifestKindImage, Platform: model.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/manifests?remote=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp responses.ManifestListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Remote)
+	assert.Len(t, resp.Manifests, 1)
+	assert.Equal(t, "linux/arm64/v8", resp.Manifests[0].Platform.Platform)
+}
+
+func TestImageHandler_Manifests_RegistryAuth(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	opts := model.ManifestOptions{Remote: true, Auth: &model.RegistryAuth{Username: "bot", Password: "s3cret", ServerAddress: "registry.example.com"}}
+	svc.On("Manifests", mock.Anything, "nginx:latest", opts).Return(&model.ManifestList{Ref: "nginx:latest", Remote: true}, nil)
+
+	header, err := registry.EncodeAuthConfig(registry.AuthConfig{Username: "bot", Password: "s3cret", ServerAddress: "registry.example.com"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/images/nginx:latest/manifests?remote=true", nil)
+	req.Header.Set("X-Registry-Auth", header)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestImageHandler_Manifests_InvalidRegistryAuth(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/images/nginx:latest/manifests?remote=true", nil)
+	req.Header.Set("X-Registry-Auth", "not base64!")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "Manifests", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImageHandler_Manifests_NotFound(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Manifests", mock.Anything, "nope", model.ManifestOptions{}).Return(nil, errors.New("no such image"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nope/manifests", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestImageHandler_Signatures_OK(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("VerifySignature", mock.Anything, "nginx:latest", true).Return(&model.SignatureReport{
+		ImageID:      "sha256:abc",
+		Ref:          "nginx@sha256:def",
+		Verifier:     "cosign",
+		Verified:     true,
+		Signatures:   []model.Signature{{Digest: "sha256:def", Issuer: "https://accounts.example", Subject: "ci@example.com"}},
+		Attestations: []model.Attestation{{PredicateType: "https://slsa.dev/provenance/v0.2"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/signatures?refresh=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp responses.SignatureReportResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Verified)
+	assert.Equal(t, "ci@example.com", resp.Signatures[0].Subject)
+	assert.Len(t, resp.Attestations, 1)
+}
+
+func TestImageHandler_Signatures_NotConfigured(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("VerifySignature", mock.Anything, "nginx:latest", false).Return(nil, domain.ErrVerifierNotConfigured)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nginx:latest/signatures", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestImageHandler_Dependents_OK(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Dependents", mock.Anything, "debian:12").Return(&model.ImageDependents{
+		ImageID:    "sha256:base",
+		Tags:       []string{"debian:12"},
+		Containers: []model.ContainerImage{{ContainerID: "c1", ContainerName: "shell", State: "exited"}},
+		Children:   []model.ChildImage{},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/debian:12/dependents", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp responses.ImageDependentsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "shell", resp.Containers[0].Name)
+	assert.False(t, resp.SafeToDelete)
+}
+
+func TestImageHandler_Dependents_Error(t *testing.T) {
+	svc := &mockImageService{}
+	r := setupImageRouter(svc)
+
+	svc.On("Dependents", mock.Anything, "nope").Return(nil, errors.New("no such image"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/images/nope/dependents", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -1,6 +1,11 @@
 package mappers
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
 	responses "github.com/rivernova/orcahub/internal/docker/images/api/responses"
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
 )
@@ -14,7 +19,7 @@ func ToImageResponseList(imgs []model.Image) []responses.ImageResponse {
 }
 
 func ToImageResponse(img model.Image) responses.ImageResponse {
-	return responses.ImageResponse{
+	resp := responses.ImageResponse{
 		ID:         img.ID,
 		Tags:       img.Tags,
 		Size:       img.Size,
@@ -22,13 +27,25 @@ func ToImageResponse(img model.Image) responses.ImageResponse {
 		Labels:     img.Labels,
 		Containers: img.Containers,
 	}
+	if img.Scan != nil {
+		resp.Scan = &responses.ScanSummary{
+			Status:     img.Scan.Status,
+			Pending:    img.Scan.Pending,
+			Scanner:    img.Scan.Scanner,
+			FinishedAt: img.Scan.FinishedAt,
+			Counts:     toSeverityCountsResponse(img.Scan.Counts),
+		}
+	}
+	return resp
 }
 
 func ToImageInspectResponse(img *model.Image) *responses.ImageInspectResponse {
 	return &responses.ImageInspectResponse{
 		ImageResponse: ToImageResponse(*img),
+		RepoDigests:   img.RepoDigests,
 		Os:            img.Os,
 		Architecture:  img.Architecture,
+		Variant:       img.Variant,
 		Author:        img.Author,
 		Comment:       img.Comment,
 		Cmd:           img.Cmd,
@@ -38,5 +55,357 @@ func ToImageInspectResponse(img *model.Image) *responses.ImageInspectResponse {
 		ExposedPorts:  img.ExposedPorts,
 		Layers:        img.Layers,
 		VirtualSize:   img.VirtualSize,
+		Manifests:     toManifestResponses(img.Manifests),
+		Signature:     ToSignatureReportResponse(img.Signature),
+	}
+}
+
+func ToManifestListResponse(l *model.ManifestList) *responses.ManifestListResponse {
+	manifests := toManifestResponses(l.Manifests)
+	if manifests == nil {
+		manifests = []responses.ManifestResponse{}
+	}
+	return &responses.ManifestListResponse{
+		Ref:       l.Ref,
+		Digest:    l.Digest,
+		MediaType: l.MediaType,
+		Size:      l.Size,
+		Remote:    l.Remote,
+		Manifests: manifests,
+		Warning:   l.Warning,
+	}
+}
+
+func toManifestResponses(manifests []model.Manifest) []responses.ManifestResponse {
+	if len(manifests) == 0 {
+		return nil
+	}
+	result := make([]responses.ManifestResponse, 0, len(manifests))
+	for _, m := range manifests {
+		result = append(result, responses.ManifestResponse{
+			Digest:    m.Digest,
+			MediaType: m.MediaType,
+			Kind:      m.Kind,
+			Platform: responses.PlatformResponse{
+				Platform:     m.Platform.String(),
+				OS:           m.Platform.OS,
+				Architecture: m.Platform.Architecture,
+				Variant:      m.Platform.Variant,
+				OSVersion:    m.Platform.OSVersion,
+			},
+			Size:         m.Size,
+			UnpackedSize: m.UnpackedSize,
+			Available:    m.Available,
+			Containers:   m.Containers,
+		})
+	}
+	return result
+}
+
+func ToImageUpdateResponseList(updates []model.ImageUpdate) []responses.ImageUpdateResponse {
+	result := make([]responses.ImageUpdateResponse, 0, len(updates))
+	for _, u := range updates {
+		result = append(result, responses.ImageUpdateResponse{
+			ContainerID:     u.ContainerID,
+			ContainerName:   u.ContainerName,
+			Image:           u.Image,
+			ImageID:         u.ImageID,
+			LocalDigest:     u.LocalDigest,
+			RemoteDigest:    u.RemoteDigest,
+			UpdateAvailable: u.UpdateAvailable,
+			CheckedAt:       u.CheckedAt,
+			Error:           u.Error,
+		})
+	}
+	return result
+}
+
+func ToLoadImageResponse(r *model.LoadResult) responses.LoadImageResponse {
+	loaded := r.Loaded
+	if loaded == nil {
+		loaded = []string{}
+	}
+	return responses.LoadImageResponse{Loaded: loaded}
+}
+
+func ToLayerAnalysisResponse(a *model.LayerAnalysis, includeFiles bool) *responses.LayerAnalysisResponse {
+	layers := make([]responses.LayerResponse, 0, len(a.Layers))
+	for _, l := range a.Layers {
+		layer := responses.LayerResponse{
+			Index:     l.Index,
+			Digest:    l.Digest,
+			Size:      l.Size,
+			Created:   l.Created,
+			CreatedBy: l.CreatedBy,
+			Comment:   l.Comment,
+			FileCount: len(l.Files),
+		}
+		if includeFiles {
+			layer.Files = make([]responses.LayerFileResponse, 0, len(l.Files))
+			for _, f := range l.Files {
+				layer.Files = append(layer.Files, responses.LayerFileResponse{
+					Path:   f.Path,
+					Size:   f.Size,
+					Mode:   f.Mode.String(),
+					Change: f.Change,
+				})
+			}
+		}
+		layers = append(layers, layer)
+	}
+
+	wasted := make([]responses.WastedFileResponse, 0, len(a.Inefficiencies))
+	for _, w := range a.Inefficiencies {
+		wasted = append(wasted, responses.WastedFileResponse{
+			Path:        w.Path,
+			Occurrences: w.Occurrences,
+			WastedSize:  w.WastedSize,
+		})
+	}
+
+	return &responses.LayerAnalysisResponse{
+		Layers:         layers,
+		TotalSize:      a.TotalSize,
+		WastedSize:     a.WastedSize,
+		Efficiency:     a.Efficiency,
+		Inefficiencies: wasted,
+	}
+}
+
+func ToScanReportResponse(r *model.ScanReport) *responses.ScanReportResponse {
+	vulns := make([]responses.VulnerabilityResponse, 0, len(r.Vulnerabilities))
+	for _, v := range r.Vulnerabilities {
+		vulns = append(vulns, responses.VulnerabilityResponse{
+			ID:               v.ID,
+			PkgName:          v.PkgName,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+			Severity:         v.Severity,
+			Title:            v.Title,
+			URL:              v.URL,
+		})
+	}
+	return &responses.ScanReportResponse{
+		ImageID:         r.ImageID,
+		Scanner:         r.Scanner,
+		Status:          r.Status,
+		Pending:         r.Pending,
+		Error:           r.Error,
+		QueuedAt:        r.QueuedAt,
+		StartedAt:       r.StartedAt,
+		FinishedAt:      r.FinishedAt,
+		Counts:          toSeverityCountsResponse(r.Counts),
+		Vulnerabilities: vulns,
+	}
+}
+
+func ToImageDependentsResponse(d *model.ImageDependents) *responses.ImageDependentsResponse {
+	containers := make([]responses.DependentContainerResponse, 0, len(d.Containers))
+	for _, c := range d.Containers {
+		containers = append(containers, responses.DependentContainerResponse{
+			ID:    c.ContainerID,
+			Name:  c.ContainerName,
+			Image: c.Image,
+			State: c.State,
+		})
+	}
+	children := make([]responses.ChildImageResponse, 0, len(d.Children))
+	for _, child := range d.Children {
+		children = append(children, responses.ChildImageResponse{
+			ID:      child.ID,
+			Tags:    child.Tags,
+			Created: child.Created,
+			Direct:  child.Direct,
+		})
+	}
+	return &responses.ImageDependentsResponse{
+		ImageID:      d.ImageID,
+		Tags:         d.Tags,
+		RepoDigests:  d.RepoDigests,
+		Containers:   containers,
+		Children:     children,
+		SafeToDelete: len(containers) == 0 && len(children) == 0,
+	}
+}
+
+func ToSignatureReportResponse(r *model.SignatureReport) *responses.SignatureReportResponse {
+	if r == nil {
+		return nil
+	}
+	sigs := make([]responses.SignatureResponse, 0, len(r.Signatures))
+	for _, sig := range r.Signatures {
+		sigs = append(sigs, responses.SignatureResponse{
+			DockerReference: sig.DockerReference,
+			Digest:          sig.Digest,
+			Issuer:          sig.Issuer,
+			Subject:         sig.Subject,
+		})
+	}
+	attestations := make([]responses.AttestationResponse, 0, len(r.Attestations))
+	for _, a := range r.Attestations {
+		attestations = append(attestations, responses.AttestationResponse{
+			PredicateType: a.PredicateType,
+			Subjects:      a.Subjects,
+		})
+	}
+	return &responses.SignatureReportResponse{
+		ImageID:      r.ImageID,
+		Ref:          r.Ref,
+		Verifier:     r.Verifier,
+		Verified:     r.Verified,
+		Error:        r.Error,
+		CheckedAt:    r.CheckedAt,
+		Signatures:   sigs,
+		Attestations: attestations,
+	}
+}
+
+func toSeverityCountsResponse(c model.SeverityCounts) responses.SeverityCountsResponse {
+	return responses.SeverityCountsResponse{
+		Critical: c.Critical,
+		High:     c.High,
+		Medium:   c.Medium,
+		Low:      c.Low,
+		Unknown:  c.Unknown,
+	}
+}
+
+const sbomTool = "orcahub"
+
+func ToSPDXDocument(s *model.SBOM) *responses.SPDXDocument {
+	const imageRef = "SPDXRef-Image"
+	packages := []responses.SPDXPackage{{
+		Name:             s.Name,
+		SPDXID:           imageRef,
+		VersionInfo:      s.ImageID,
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		PrimaryPurpose:   "CONTAINER",
+	}}
+	relationships := []responses.SPDXRelationship{{
+		SPDXElementID:      "SPDXRef-DOCUMENT",
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: imageRef,
+	}}
+	for i, p := range s.Packages {
+		id := fmt.Sprintf("SPDXRef-Package-%s-%d", p.Type, i)
+		license := p.License
+		if license == "" {
+			license = "NOASSERTION"
+		}
+		pkg := responses.SPDXPackage{
+			Name:             p.Name,
+			SPDXID:           id,
+			VersionInfo:      p.Version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  license,
+		}
+		if p.PURL != "" {
+			pkg.ExternalRefs = []responses.SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  p.PURL,
+			}}
+		}
+		packages = append(packages, pkg)
+		relationships = append(relationships, responses.SPDXRelationship{
+			SPDXElementID:      imageRef,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: id,
+		})
 	}
+	return &responses.SPDXDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.Name,
+		DocumentNamespace: fmt.Sprintf("https://%s/spdx/%s", sbomTool, strings.TrimPrefix(s.ImageID, "sha256:")),
+		CreationInfo: responses.SPDXCreationInfo{
+			Created:  sbomTimestamp(s.Created),
+			Creators: []string{"Tool: " + sbomTool},
+			Comment:  sbomIncompleteComment(s),
+		},
+		Packages:      packages,
+		Relationships: relationships,
+	}
+}
+
+func ToCycloneDXDocument(s *model.SBOM) *responses.CycloneDXDocument {
+	components := make([]responses.CycloneDXComponent, 0, len(s.Packages)+1)
+	if s.OS.ID != "" {
+		components = append(components, responses.CycloneDXComponent{
+			BOMRef:  "os:" + s.OS.ID,
+			Type:    "operating-system",
+			Name:    s.OS.ID,
+			Version: s.OS.VersionID,
+		})
+	}
+	for _, p := range s.Packages {
+		c := responses.CycloneDXComponent{
+			BOMRef:  p.PURL,
+			Type:    "library",
+			Name:    p.Name,
+			Version: p.Version,
+			PURL:    p.PURL,
+		}
+		if p.License != "" {
+			c.Licenses = []responses.CycloneDXLicense{{License: responses.CycloneDXLicenseName{Name: p.License}}}
+		}
+		if p.Location != "" {
+			c.Properties = []responses.CycloneDXProperty{{Name: sbomTool + ":location", Value: p.Location}}
+		}
+		components = append(components, c)
+	}
+	var properties []responses.CycloneDXProperty
+	if s.Incomplete {
+		properties = append(properties, responses.CycloneDXProperty{Name: sbomTool + ":incomplete", Value: "true"})
+	}
+	for _, w := range s.Warnings {
+		properties = append(properties, responses.CycloneDXProperty{Name: sbomTool + ":warning", Value: w})
+	}
+	return &responses.CycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + sbomSerial(s.ImageID),
+		Version:      1,
+		Metadata: responses.CycloneDXMetadata{
+			Timestamp: sbomTimestamp(s.Created),
+			Tools: responses.CycloneDXTools{Components: []responses.CycloneDXComponent{{
+				Type: "application",
+				Name: sbomTool,
+			}}},
+			Component: responses.CycloneDXComponent{
+				BOMRef:  s.ImageID,
+				Type:    "container",
+				Name:    s.Name,
+				Version: s.ImageID,
+			},
+			Properties: properties,
+		},
+		Components: components,
+	}
+}
+
+// sbomIncompleteComment explains in the SPDX creation info why packages may
+// be missing from the document.
+func sbomIncompleteComment(s *model.SBOM) string {
+	if !s.Incomplete {
+		return ""
+	}
+	return "Incomplete: " + strings.Join(s.Warnings, "; ")
+}
+
+func sbomTimestamp(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
+
+// sbomSerial derives a stable RFC 4122 UUID from the image ID, so the same
+// image always yields the same CycloneDX serial number.
+func sbomSerial(imageID string) string {
+	sum := sha256.Sum256([]byte(imageID))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
 }
@@ -45,6 +45,12 @@ func TestToImageResponseList_Empty(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+func TestToLoadImageResponse_Untagged(t *testing.T) {
+	result := mappers.ToLoadImageResponse(&model.LoadResult{})
+	assert.NotNil(t, result.Loaded)
+	assert.Empty(t, result.Loaded)
+}
+
 func TestToImageInspectResponse(t *testing.T) {
 	img := &model.Image{
 		ID:           "sha256:abc",
@@ -73,3 +79,46 @@ func TestToImageInspectResponse(t *testing.T) {
 	assert.Equal(t, 7, resp.Layers)
 	assert.Equal(t, int64(142000000), resp.VirtualSize)
 }
+
+func TestToImageUpdateResponseList(t *testing.T) {
+	updates := []model.ImageUpdate{
+		{
+			ContainerID:     "c1",
+			ContainerName:   "web",
+			Image:           "nginx:latest",
+			LocalDigest:     "sha256:old",
+			RemoteDigest:    "sha256:new",
+			UpdateAvailable: true,
+			CheckedAt:       1700000000,
+		},
+	}
+
+	result := mappers.ToImageUpdateResponseList(updates)
+
+	assert.Len(t, result, 1)
+	assert.Equal(t, "web", result[0].ContainerName)
+	assert.Equal(t, "sha256:new", result[0].RemoteDigest)
+	assert.True(t, result[0].UpdateAvailable)
+	assert.Equal(t, int64(1700000000), result[0].CheckedAt)
+}
+
+func TestSBOMDocuments_Incomplete(t *testing.T) {
+	sbom := &model.SBOM{
+		ImageID:    "sha256:abc",
+		Name:       "ubi9",
+		Incomplete: true,
+		Warnings:   []string{"rpm packages in /var/lib/rpm/Packages are not catalogued"},
+	}
+
+	spdx := mappers.ToSPDXDocument(sbom)
+	assert.Equal(t, "Incomplete: rpm packages in /var/lib/rpm/Packages are not catalogued", spdx.CreationInfo.Comment)
+
+	cdx := mappers.ToCycloneDXDocument(sbom)
+	assert.Len(t, cdx.Metadata.Properties, 2)
+	assert.Equal(t, "orcahub:incomplete", cdx.Metadata.Properties[0].Name)
+	assert.Equal(t, "orcahub:warning", cdx.Metadata.Properties[1].Name)
+
+	sbom.Incomplete, sbom.Warnings = false, nil
+	assert.Empty(t, mappers.ToSPDXDocument(sbom).CreationInfo.Comment)
+	assert.Empty(t, mappers.ToCycloneDXDocument(sbom).Metadata.Properties)
+}
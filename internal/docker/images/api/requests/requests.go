@@ -1,8 +1,9 @@
 package requests
 
 type PullImageRequest struct {
-	Image string        `json:"image" binding:"required"` // e.g. "nginx:latest"
-	Auth  *RegistryAuth `json:"auth"`                     // opcional para registries privados
+	Image    string        `json:"image" binding:"required"` // e.g. "nginx:latest"
+	Platform string        `json:"platform"`                 // e.g. "linux/arm64/v8"; vacío usa la plataforma del daemon
+	Auth     *RegistryAuth `json:"auth"`                     // opcional para registries privados
 }
 
 type RegistryAuth struct {
@@ -25,7 +26,44 @@ type RemoveImageRequest struct {
 	PruneChildren bool `form:"prune_children"`
 }
 
+type ImageLayersRequest struct {
+	Files bool `form:"files,default=true"` // include per-layer file changes
+}
+
+type ScanReportRequest struct {
+	Severity []string `form:"severity" collection_format:"csv"` // e.g. "CRITICAL,HIGH"; empty returns all
+}
+
+type ImageManifestsRequest struct {
+	Remote bool `form:"remote"` // query the registry instead of the local store
+}
+
+type SignatureRequest struct {
+	Refresh bool `form:"refresh"` // verify again even if a recent result is cached
+}
+
+type SBOMRequest struct {
+	Format string `form:"format,default=spdx-json"` // spdx-json or cyclonedx-json
+}
+
+type SaveImageRequest struct {
+	Refs []string `form:"refs"` // additional images to include in the same archive
+}
+
+type PruneImagesRequest struct {
+	Dangling      bool     `form:"dangling"`      // only remove untagged images
+	Until         string   `form:"until"`         // e.g. "24h", "2024-01-31" or a Unix timestamp
+	Labels        []string `form:"label"`         // key or key=value, repeatable
+	ExcludeLabels []string `form:"exclude_label"` // key or key=value, repeatable
+	KeepLast      int      `form:"keep_last"`     // newest tags to keep per repository
+	DryRun        bool     `form:"dry_run"`
+}
+
 type TagImageRequest struct {
 	Source string `json:"source" binding:"required"`
 	Target string `json:"target" binding:"required"`
 }
+
+type ImageUpdatesRequest struct {
+	Refresh bool `form:"refresh"` // bypass the cached report and query the registries now
+}
@@ -7,21 +7,55 @@ type ImageResponse struct {
 	Created    int64             `json:"created"`
 	Labels     map[string]string `json:"labels"`
 	Containers int64             `json:"containers"`
+	Scan       *ScanSummary      `json:"scan,omitempty"`
 }
 
 type ImageInspectResponse struct {
 	ImageResponse
-	Os           string   `json:"os"`
-	Architecture string   `json:"architecture"`
-	Author       string   `json:"author"`
-	Comment      string   `json:"comment"`
-	Cmd          []string `json:"cmd"`
-	Entrypoint   []string `json:"entrypoint"`
-	Env          []string `json:"env"`
-	WorkingDir   string   `json:"working_dir"`
-	ExposedPorts []string `json:"exposed_ports"`
-	Layers       int      `json:"layers"`
-	VirtualSize  int64    `json:"virtual_size"`
+	RepoDigests  []string                 `json:"repo_digests"`
+	Os           string                   `json:"os"`
+	Architecture string                   `json:"architecture"`
+	Variant      string                   `json:"variant,omitempty"`
+	Author       string                   `json:"author"`
+	Comment      string                   `json:"comment"`
+	Cmd          []string                 `json:"cmd"`
+	Entrypoint   []string                 `json:"entrypoint"`
+	Env          []string                 `json:"env"`
+	WorkingDir   string                   `json:"working_dir"`
+	ExposedPorts []string                 `json:"exposed_ports"`
+	Layers       int                      `json:"layers"`
+	VirtualSize  int64                    `json:"virtual_size"`
+	Manifests    []ManifestResponse       `json:"manifests,omitempty"`
+	Signature    *SignatureReportResponse `json:"signature,omitempty"`
+}
+
+type PlatformResponse struct {
+	Platform     string `json:"platform"` // os/arch[/variant], as accepted by pull
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+	OSVersion    string `json:"os_version,omitempty"`
+}
+
+type ManifestResponse struct {
+	Digest       string           `json:"digest,omitempty"`
+	MediaType    string           `json:"media_type,omitempty"`
+	Kind         string           `json:"kind"`
+	Platform     PlatformResponse `json:"platform"`
+	Size         int64            `json:"size"`
+	UnpackedSize int64            `json:"unpacked_size"`
+	Available    bool             `json:"available"`
+	Containers   []string         `json:"containers,omitempty"`
+}
+
+type ManifestListResponse struct {
+	Ref       string             `json:"ref"`
+	Digest    string             `json:"digest"`
+	MediaType string             `json:"media_type,omitempty"`
+	Size      int64              `json:"size"`
+	Remote    bool               `json:"remote"`
+	Manifests []ManifestResponse `json:"manifests"`
+	Warning   string             `json:"warning,omitempty"`
 }
 
 type PullImageResponse struct {
@@ -39,3 +73,221 @@ type RemoveImageResponse struct {
 	Deleted  []string `json:"deleted"`
 	Untagged []string `json:"untagged"`
 }
+
+type ImageUpdateResponse struct {
+	ContainerID     string `json:"container_id"`
+	ContainerName   string `json:"container_name"`
+	Image           string `json:"image"`
+	ImageID         string `json:"image_id"`
+	LocalDigest     string `json:"local_digest"`
+	RemoteDigest    string `json:"remote_digest"`
+	UpdateAvailable bool   `json:"update_available"`
+	CheckedAt       int64  `json:"checked_at"`
+	Error           string `json:"error,omitempty"`
+}
+
+type LoadImageResponse struct {
+	Loaded []string `json:"loaded"`
+}
+
+type LayerAnalysisResponse struct {
+	Layers         []LayerResponse      `json:"layers"`
+	TotalSize      int64                `json:"total_size"`
+	WastedSize     int64                `json:"wasted_size"`
+	Efficiency     float64              `json:"efficiency"`
+	Inefficiencies []WastedFileResponse `json:"inefficiencies"`
+}
+
+type LayerResponse struct {
+	Index     int                 `json:"index"`
+	Digest    string              `json:"digest"`
+	Size      int64               `json:"size"`
+	Created   int64               `json:"created"`
+	CreatedBy string              `json:"created_by"`
+	Comment   string              `json:"comment"`
+	FileCount int                 `json:"file_count"`
+	Files     []LayerFileResponse `json:"files,omitempty"`
+}
+
+type LayerFileResponse struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mode   string `json:"mode"`
+	Change string `json:"change"` // added, modified, removed
+}
+
+type WastedFileResponse struct {
+	Path        string `json:"path"`
+	Occurrences int    `json:"occurrences"`
+	WastedSize  int64  `json:"wasted_size"`
+}
+
+type ScanSummary struct {
+	Status     string                 `json:"status"`            // queued, running, completed, failed
+	Pending    string                 `json:"pending,omitempty"` // state of a rescan while the previous report is shown
+	Scanner    string                 `json:"scanner"`
+	FinishedAt int64                  `json:"finished_at"`
+	Counts     SeverityCountsResponse `json:"counts"`
+}
+
+type SeverityCountsResponse struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+}
+
+type ScanReportResponse struct {
+	ImageID         string                  `json:"image_id"`
+	Scanner         string                  `json:"scanner"`
+	Status          string                  `json:"status"`
+	Pending         string                  `json:"pending,omitempty"`
+	Error           string                  `json:"error,omitempty"`
+	QueuedAt        int64                   `json:"queued_at"`
+	StartedAt       int64                   `json:"started_at"`
+	FinishedAt      int64                   `json:"finished_at"`
+	Counts          SeverityCountsResponse  `json:"counts"`
+	Vulnerabilities []VulnerabilityResponse `json:"vulnerabilities"`
+}
+
+type VulnerabilityResponse struct {
+	ID               string `json:"id"`
+	PkgName          string `json:"pkg_name"`
+	InstalledVersion string `json:"installed_version"`
+	FixedVersion     string `json:"fixed_version"`
+	Severity         string `json:"severity"`
+	Title            string `json:"title"`
+	URL              string `json:"url"`
+}
+
+// SPDXDocument is an SPDX 2.3 JSON document.
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// CycloneDXDocument is a CycloneDX 1.5 JSON BOM.
+type CycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp"`
+	Tools      CycloneDXTools      `json:"tools"`
+	Component  CycloneDXComponent  `json:"component"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Type       string              `json:"type"` // container, operating-system, library, application
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Licenses   []CycloneDXLicense  `json:"licenses,omitempty"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXLicense struct {
+	License CycloneDXLicenseName `json:"license"`
+}
+
+type CycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type SignatureReportResponse struct {
+	ImageID      string                `json:"image_id"`
+	Ref          string                `json:"ref"`
+	Verifier     string                `json:"verifier"`
+	Verified     bool                  `json:"verified"`
+	Error        string                `json:"error,omitempty"`
+	CheckedAt    int64                 `json:"checked_at"`
+	Signatures   []SignatureResponse   `json:"signatures"`
+	Attestations []AttestationResponse `json:"attestations"`
+}
+
+type SignatureResponse struct {
+	DockerReference string `json:"docker_reference,omitempty"`
+	Digest          string `json:"digest"`
+	Issuer          string `json:"issuer,omitempty"`
+	Subject         string `json:"subject,omitempty"`
+}
+
+type AttestationResponse struct {
+	PredicateType string   `json:"predicate_type"`
+	Subjects      []string `json:"subjects"`
+}
+
+type ImageDependentsResponse struct {
+	ImageID      string                       `json:"image_id"`
+	Tags         []string                     `json:"tags"`
+	RepoDigests  []string                     `json:"repo_digests"`
+	Containers   []DependentContainerResponse `json:"containers"`
+	Children     []ChildImageResponse         `json:"children"`
+	SafeToDelete bool                         `json:"safe_to_delete"` // no containers or child images depend on it
+}
+
+type DependentContainerResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	State string `json:"state"`
+}
+
+type ChildImageResponse struct {
+	ID      string   `json:"id"`
+	Tags    []string `json:"tags"`
+	Created int64    `json:"created"`
+	Direct  bool     `json:"direct"`
+}
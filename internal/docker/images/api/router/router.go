@@ -9,12 +9,22 @@ func Register(rg *gin.RouterGroup, handler *api.Handler) {
 	images := rg.Group("/images")
 	{
 		images.GET("", handler.List)
+		images.GET("/updates", handler.Updates)
 		images.GET("/:id", handler.Inspect)
 		images.GET("/:id/history", handler.History)
+		images.GET("/:id/dependents", handler.Dependents)
+		images.GET("/:id/layers", handler.Layers)
+		images.GET("/:id/manifests", handler.Manifests)
+		images.GET("/:id/save", handler.Save)
+		images.GET("/:id/sbom", handler.SBOM)
+		images.GET("/:id/signatures", handler.Signatures)
+		images.GET("/:id/vulnerabilities", handler.Vulnerabilities)
+		images.POST("/:id/scan", handler.Scan)
 		images.DELETE("/:id", handler.Delete)
 		images.POST("/pull", handler.Pull)
 		images.POST("/build", handler.Build)
 		images.POST("/tag", handler.Tag)
+		images.POST("/load", handler.Load)
 		images.POST("/prune", handler.Prune)
 	}
 }
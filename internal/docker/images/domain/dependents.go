@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"sort"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+func (s *ImageServiceImpl) Dependents(ctx context.Context, id string) (*model.ImageDependents, error) {
+	img, err := s.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := s.adapter.ContainerImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := s.adapter.ImageGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := &model.ImageDependents{
+		ImageID:     img.ID,
+		Tags:        img.Tags,
+		RepoDigests: img.RepoDigests,
+		Containers:  []model.ContainerImage{},
+		Children:    []model.ChildImage{},
+	}
+	for _, c := range containers {
+		if c.ImageID == img.ID {
+			deps.Containers = append(deps.Containers, c)
+		}
+	}
+	for _, n := range nodes {
+		if n.ID == img.ID {
+			deps.Children = findChildren(n, nodes)
+			break
+		}
+	}
+	return deps, nil
+}
+
+// findChildren returns the images built on top of target. Images built
+// locally by the classic builder link to their parent; pulled images do not,
+// so they are related by sharing target's layer chain as a strict prefix.
+func findChildren(target model.ImageNode, nodes []model.ImageNode) []model.ChildImage {
+	byID := make(map[string]model.ImageNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	var descendants []model.ImageNode
+	for _, n := range nodes {
+		if n.ID != target.ID && (hasAncestor(n, target.ID, byID) || extendsLayers(n.Layers, target.Layers)) {
+			descendants = append(descendants, n)
+		}
+	}
+
+	children := make([]model.ChildImage, 0, len(descendants))
+	for _, n := range descendants {
+		direct := n.ParentID == target.ID
+		if n.ParentID == "" {
+			direct = true
+			for _, m := range descendants {
+				if m.ID != n.ID && extendsLayers(m.Layers, target.Layers) && extendsLayers(n.Layers, m.Layers) {
+					direct = false
+					break
+				}
+			}
+		}
+		children = append(children, model.ChildImage{ID: n.ID, Tags: n.Tags, Created: n.Created, Direct: direct})
+	}
+	sort.SliceStable(children, func(a, b int) bool {
+		if children[a].Created != children[b].Created {
+			return children[a].Created < children[b].Created
+		}
+		return children[a].ID < children[b].ID
+	})
+	return children
+}
+
+func hasAncestor(n model.ImageNode, ancestorID string, byID map[string]model.ImageNode) bool {
+	seen := make(map[string]bool)
+	for parent := n.ParentID; parent != "" && !seen[parent]; parent = byID[parent].ParentID {
+		if parent == ancestorID {
+			return true
+		}
+		seen[parent] = true
+	}
+	return false
+}
+
+// extendsLayers reports whether layers starts with base and adds to it.
+func extendsLayers(layers, base []string) bool {
+	if len(base) == 0 || len(layers) <= len(base) {
+		return false
+	}
+	for i := range base {
+		if layers[i] != base[i] {
+			return false
+		}
+	}
+	return true
+}
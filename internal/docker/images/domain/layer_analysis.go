@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"path"
+	"sort"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// maxInefficiencies caps how many wasted paths are reported.
+const maxInefficiencies = 100
+
+// analyzeLayers replays the layers in order, classifying every entry as an
+// addition, modification or removal relative to the layers below it.
+// Bytes of a file that is later overwritten or removed still ship with the
+// image, so they are counted as wasted space.
+func analyzeLayers(contents []model.LayerContent) *model.LayerAnalysis {
+	tree := newPathTree()
+	wasted := make(map[string]*model.WastedFile)
+	analysis := &model.LayerAnalysis{Layers: make([]model.Layer, 0, len(contents))}
+
+	waste := func(p string, size int64) {
+		w, ok := wasted[p]
+		if !ok {
+			w = &model.WastedFile{Path: p}
+			wasted[p] = w
+		}
+		w.Occurrences++
+		w.WastedSize += size
+		analysis.WastedSize += size
+	}
+
+	for i, content := range contents {
+		layer := model.Layer{
+			Index:     i,
+			Digest:    content.Digest,
+			Created:   content.Created,
+			CreatedBy: content.CreatedBy,
+			Comment:   content.Comment,
+			Files:     make([]model.LayerFile, 0, len(content.Entries)),
+		}
+
+		// Opaque directories hide everything the lower layers put in them,
+		// regardless of where the marker appears in this layer's tar stream.
+		for _, e := range content.Entries {
+			if !e.Opaque {
+				continue
+			}
+			tree.removeBelow(e.Path, func(p string, size int64) {
+				waste(p, size)
+				layer.Files = append(layer.Files, model.LayerFile{Path: p, Size: size, Change: model.FileRemoved})
+			})
+		}
+
+		for _, e := range content.Entries {
+			switch {
+			case e.Opaque:
+				continue
+			case e.Whiteout:
+				size, _ := tree.remove(e.Path, waste)
+				layer.Files = append(layer.Files, model.LayerFile{Path: e.Path, Size: size, Change: model.FileRemoved})
+			default:
+				change := model.FileAdded
+				if prev, ok := tree.sizes[e.Path]; ok {
+					change = model.FileModified
+					waste(e.Path, prev)
+				}
+				tree.set(e.Path, e.Size)
+				layer.Size += e.Size
+				layer.Files = append(layer.Files, model.LayerFile{Path: e.Path, Size: e.Size, Mode: e.Mode, Change: change})
+			}
+		}
+
+		sort.Slice(layer.Files, func(a, b int) bool { return layer.Files[a].Path < layer.Files[b].Path })
+		analysis.TotalSize += layer.Size
+		analysis.Layers = append(analysis.Layers, layer)
+	}
+
+	for _, w := range wasted {
+		if w.WastedSize > 0 {
+			analysis.Inefficiencies = append(analysis.Inefficiencies, *w)
+		}
+	}
+	sort.Slice(analysis.Inefficiencies, func(a, b int) bool {
+		if analysis.Inefficiencies[a].WastedSize != analysis.Inefficiencies[b].WastedSize {
+			return analysis.Inefficiencies[a].WastedSize > analysis.Inefficiencies[b].WastedSize
+		}
+		return analysis.Inefficiencies[a].Path < analysis.Inefficiencies[b].Path
+	})
+	if len(analysis.Inefficiencies) > maxInefficiencies {
+		analysis.Inefficiencies = analysis.Inefficiencies[:maxInefficiencies]
+	}
+
+	analysis.Efficiency = 1
+	if analysis.TotalSize > 0 {
+		analysis.Efficiency = float64(analysis.TotalSize-analysis.WastedSize) / float64(analysis.TotalSize)
+	}
+	return analysis
+}
+
+// pathTree holds the size of every path in the merged filesystem together with
+// a parent-to-children index, so removing a directory only visits what lies
+// below it instead of scanning every path.
+type pathTree struct {
+	sizes    map[string]int64
+	children map[string]map[string]struct{}
+}
+
+func newPathTree() *pathTree {
+	return &pathTree{
+		sizes:    make(map[string]int64),
+		children: make(map[string]map[string]struct{}),
+	}
+}
+
+// set records p and links it, and any ancestors not yet known, to its parent.
+func (t *pathTree) set(p string, size int64) {
+	t.sizes[p] = size
+	for p != "/" {
+		dir := path.Dir(p)
+		kids, ok := t.children[dir]
+		if !ok {
+			kids = make(map[string]struct{})
+			t.children[dir] = kids
+		}
+		if _, ok := kids[p]; ok {
+			return
+		}
+		kids[p] = struct{}{}
+		p = dir
+	}
+}
+
+// remove deletes p and everything below it, calling fn for each removed path
+// that held content. It returns the size recorded for p itself, if any.
+func (t *pathTree) remove(p string, fn func(p string, size int64)) (int64, bool) {
+	size, ok := t.sizes[p]
+	if ok {
+		fn(p, size)
+		delete(t.sizes, p)
+	}
+	t.removeBelow(p, fn)
+	delete(t.children[path.Dir(p)], p)
+	return size, true
+}
+
+// removeBelow deletes everything below dir, leaving dir itself in place.
+func (t *pathTree) removeBelow(dir string, fn func(p string, size int64)) {
+	for child := range t.children[dir] {
+		if size, ok := t.sizes[child]; ok {
+			fn(child, size)
+			delete(t.sizes, child)
+		}
+		t.removeBelow(child, fn)
+	}
+	delete(t.children, dir)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidPlatform = errors.New("invalid platform")
+
+// validatePlatform checks that p has the os/arch[/variant] form the daemon
+// expects for pulls. An empty platform selects the daemon's own.
+func validatePlatform(p string) error {
+	if p == "" {
+		return nil
+	}
+	parts := strings.Split(p, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("%w %q: expected os/arch[/variant]", ErrInvalidPlatform, p)
+	}
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, " \t") {
+			return fmt.Errorf("%w %q: expected os/arch[/variant]", ErrInvalidPlatform, p)
+		}
+	}
+	return nil
+}
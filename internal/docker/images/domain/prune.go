@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+var ErrInvalidPruneOptions = errors.New("invalid prune options")
+
+// untilLayouts are the absolute time formats accepted by the until filter,
+// besides Unix timestamps and durations.
+var untilLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// pruneAction is what pruning does to a single image: untag some of its tags
+// and, when nothing worth keeping is left, remove the image itself.
+type pruneAction struct {
+	imageID string
+	tags    []string
+	remove  bool
+	size    int64 // bytes freed by removing the image
+}
+
+// parseUntil resolves the until filter relative to now. Durations such as
+// "24h" select images created more than that long ago.
+func parseUntil(until string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(until); err == nil {
+		return now.Add(-d), nil
+	}
+	if secs, err := strconv.ParseInt(until, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	for _, layout := range untilLayouts {
+		if t, err := time.Parse(layout, until); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: cannot parse until %q", ErrInvalidPruneOptions, until)
+}
+
+// planPrune selects the images and tags that pruning with opts would remove,
+// mirroring the daemon's own prune filters so a dry run matches a real one.
+func planPrune(images []model.Image, opts model.PruneOptions, cutoff time.Time) []pruneAction {
+	sorted := append([]model.Image(nil), images...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Created != sorted[b].Created {
+			return sorted[a].Created < sorted[b].Created
+		}
+		return sorted[a].ID < sorted[b].ID
+	})
+
+	protected := keepNewestTags(sorted, opts.KeepLast)
+	var actions []pruneAction
+	for _, img := range sorted {
+		tags := repoTags(img)
+		switch {
+		case img.Containers > 0:
+			continue
+		case opts.DanglingOnly && (len(tags) > 0 || len(img.RepoDigests) > 0):
+			continue
+		case !cutoff.IsZero() && !time.Unix(img.Created, 0).Before(cutoff):
+			continue
+		case !matchLabels(img.Labels, opts.Labels, opts.ExcludeLabels):
+			continue
+		}
+
+		action := pruneAction{imageID: img.ID}
+		kept := 0
+		for _, tag := range tags {
+			if protected[tag] {
+				kept++
+				continue
+			}
+			action.tags = append(action.tags, tag)
+		}
+		if kept == 0 {
+			action.remove = true
+			action.size = img.Size
+			if img.SharedSize > 0 {
+				action.size -= img.SharedSize
+			}
+		}
+		if action.remove || len(action.tags) > 0 {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// keepNewestTags returns the keep most recent tags of every repository,
+// ranked by the creation time of the image they point to.
+func keepNewestTags(images []model.Image, keep int) map[string]bool {
+	protected := make(map[string]bool)
+	if keep <= 0 {
+		return protected
+	}
+	type taggedImage struct {
+		tag     string
+		created int64
+	}
+	byRepo := make(map[string][]taggedImage)
+	for _, img := range images {
+		for _, tag := range repoTags(img) {
+			repo := repositoryOf(tag)
+			byRepo[repo] = append(byRepo[repo], taggedImage{tag: tag, created: img.Created})
+		}
+	}
+	for _, tags := range byRepo {
+		sort.SliceStable(tags, func(a, b int) bool {
+			if tags[a].created != tags[b].created {
+				return tags[a].created > tags[b].created
+			}
+			return tags[a].tag < tags[b].tag
+		})
+		for i := 0; i < len(tags) && i < keep; i++ {
+			protected[tags[i].tag] = true
+		}
+	}
+	return protected
+}
+
+func repoTags(img model.Image) []string {
+	tags := make([]string, 0, len(img.Tags))
+	for _, tag := range img.Tags {
+		if tag != "<none>:<none>" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func repositoryOf(tag string) string {
+	if named, err := reference.ParseNormalizedNamed(tag); err == nil {
+		return reference.FamiliarName(named)
+	}
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		return tag[:i]
+	}
+	return tag
+}
+
+// matchLabels applies the daemon's label and label! filters: every include
+// must match and no exclude may. Filters are either "key" or "key=value".
+func matchLabels(labels map[string]string, include, exclude []string) bool {
+	for _, f := range include {
+		if !hasLabel(labels, f) {
+			return false
+		}
+	}
+	for _, f := range exclude {
+		if hasLabel(labels, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLabel(labels map[string]string, filter string) bool {
+	key, value, withValue := strings.Cut(filter, "=")
+	v, ok := labels[key]
+	return ok && (!withValue || v == value)
+}
+
+// executePrune carries out the planned actions without ever forcing a
+// removal: tags are removed one by one, which deletes the image along with
+// its last tag, and only an image left without references is then removed
+// by ID. Containers may have been created since planning, so each image is
+// checked again right before it is touched, and the daemon still refuses to
+// remove an image a container started using in between.
+func (s *ImageServiceImpl) executePrune(ctx context.Context, actions []pruneAction) model.PruneResult {
+	result := model.PruneResult{Deleted: []string{}}
+	for _, action := range actions {
+		user, err := s.containerUsing(ctx, action.imageID)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		if user != "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("skipped image %s: now used by container %s", action.imageID, user))
+			continue
+		}
+
+		removed := false
+		untagged := 0
+		for _, tag := range action.tags {
+			res, err := s.adapter.Delete(ctx, tag, model.RemoveOptions{})
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			untagged++
+			result.Untagged = append(result.Untagged, tag)
+			if res != nil && slices.Contains(res.Deleted, action.imageID) {
+				removed = true
+			}
+		}
+		if !action.remove || untagged < len(action.tags) {
+			continue
+		}
+		if !removed {
+			if _, err := s.adapter.Delete(ctx, action.imageID, model.RemoveOptions{PruneChildren: true}); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, action.imageID)
+		result.SpaceReclaimed += action.size
+	}
+	return result
+}
+
+// containerUsing returns the name of a container, running or not, that uses
+// the image, or "" if there is none.
+func (s *ImageServiceImpl) containerUsing(ctx context.Context, imageID string) (string, error) {
+	containers, err := s.adapter.ContainerImages(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range containers {
+		if c.ImageID == imageID {
+			return c.ContainerName, nil
+		}
+	}
+	return "", nil
+}
+
+func previewPrune(actions []pruneAction) model.PruneResult {
+	result := model.PruneResult{Deleted: []string{}, DryRun: true}
+	for _, action := range actions {
+		if action.remove {
+			result.Deleted = append(result.Deleted, action.imageID)
+			result.SpaceReclaimed += action.size
+		}
+		result.Untagged = append(result.Untagged, action.tags...)
+	}
+	return result
+}
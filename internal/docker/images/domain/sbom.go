@@ -0,0 +1,386 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivernova/orcahub/internal/docker/images/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+const (
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	dpkgStatusDir    = "/var/lib/dpkg/status.d/"
+	apkInstalledPath = "/lib/apk/db/installed"
+	osReleasePath    = "/etc/os-release"
+	osReleaseLibPath = "/usr/lib/os-release"
+)
+
+// rpmDatabases are the rpm package databases of RHEL, Fedora, CentOS, Alma,
+// Rocky, UBI, SUSE and Amazon Linux images: Berkeley DB, NDB and SQLite,
+// under the legacy and the sysimage location. They are not catalogued yet,
+// so finding one marks the SBOM incomplete instead of silently leaving the
+// system packages out.
+var rpmDatabases = map[string]bool{
+	"/var/lib/rpm/Packages":              true,
+	"/var/lib/rpm/Packages.db":           true,
+	"/var/lib/rpm/rpmdb.sqlite":          true,
+	"/usr/lib/sysimage/rpm/Packages":     true,
+	"/usr/lib/sysimage/rpm/Packages.db":  true,
+	"/usr/lib/sysimage/rpm/rpmdb.sqlite": true,
+}
+
+// SBOMGenerator catalogues the packages installed in an image from its
+// package manager databases and lockfiles, and the modules linked into its
+// Go binaries. Images are immutable, so results
+// are cached by image ID for the lifetime of the process.
+type SBOMGenerator struct {
+	adapter adapter.ImageAdapter
+
+	mu    sync.Mutex
+	cache map[string]*model.SBOM
+}
+
+func NewSBOMGenerator(adapter adapter.ImageAdapter) *SBOMGenerator {
+	return &SBOMGenerator{adapter: adapter, cache: make(map[string]*model.SBOM)}
+}
+
+func (g *SBOMGenerator) Generate(ctx context.Context, id string) (*model.SBOM, error) {
+	img, err := g.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	cached, ok := g.cache[img.ID]
+	g.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	// Java archives are only noted: reading the libraries bundled in them
+	// would mean holding every archive in memory. match sees the files of
+	// every layer, so one deleted again later is still noted.
+	var archives []string
+	files, err := g.adapter.ReadFiles(ctx, img.ID, func(p string) bool {
+		if isJavaArchive(p) {
+			archives = append(archives, p)
+			return false
+		}
+		return isCatalogPath(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sbom := catalog(files, archives)
+	sbom.ImageID = img.ID
+	sbom.Name = img.ID
+	if len(img.Tags) > 0 {
+		sbom.Name = img.Tags[0]
+	}
+	sbom.Created = time.Now().Unix()
+
+	g.mu.Lock()
+	g.cache[img.ID] = sbom
+	g.mu.Unlock()
+	return sbom, nil
+}
+
+func isCatalogPath(p string) bool {
+	switch p {
+	case dpkgStatusPath, apkInstalledPath, osReleasePath, osReleaseLibPath:
+		return true
+	}
+	if rpmDatabases[p] {
+		return true
+	}
+	if strings.HasPrefix(p, dpkgStatusDir) {
+		return !strings.HasSuffix(p, ".md5sums")
+	}
+	base := path.Base(p)
+	if base == "package-lock.json" || base == ".package-lock.json" || lockfiles[base] != nil {
+		return true
+	}
+	return base == "METADATA" && strings.HasSuffix(path.Dir(p), ".dist-info")
+}
+
+func isJavaArchive(p string) bool {
+	switch path.Ext(p) {
+	case ".jar", ".war", ".ear":
+		return true
+	}
+	return false
+}
+
+func catalog(files *model.ImageFiles, archives []string) *model.SBOM {
+	sbom := &model.SBOM{}
+	if data, ok := files.Files[osReleasePath]; ok {
+		sbom.OS = parseOSRelease(data)
+	} else if data, ok := files.Files[osReleaseLibPath]; ok {
+		sbom.OS = parseOSRelease(data)
+	}
+
+	seen := make(map[string]bool)
+	add := func(location string, pkgs []model.Package) {
+		for _, pkg := range pkgs {
+			key := pkg.Type + "/" + pkg.Name + "@" + pkg.Version
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			pkg.Location = location
+			sbom.Packages = append(sbom.Packages, pkg)
+		}
+	}
+	warn := func(warning string) {
+		sbom.Incomplete = true
+		sbom.Warnings = append(sbom.Warnings, warning)
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(files.Files)) {
+		data := files.Files[p]
+		base := path.Base(p)
+		switch {
+		case rpmDatabases[p]:
+			warn("rpm packages in " + p + " are not catalogued")
+		case p == dpkgStatusPath || strings.HasPrefix(p, dpkgStatusDir):
+			add(p, parseDpkgStatus(data, sbom.OS))
+		case p == apkInstalledPath:
+			add(p, parseAPKInstalled(data))
+		case base == "package-lock.json" || base == ".package-lock.json":
+			add(p, parseNPMLock(data))
+		case base == "METADATA":
+			add(p, parsePythonMetadata(data))
+		case lockfiles[base] != nil:
+			add(p, lockfiles[base](data))
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(files.GoBinaries)) {
+		if modinfo := files.GoBinaries[p]; modinfo != "" {
+			add(p, parseGoModInfo(modinfo))
+		} else {
+			warn("modules of Go binary " + p + " are not catalogued: it was built before Go 1.18")
+		}
+	}
+	if len(archives) > 0 {
+		slices.Sort(archives)
+		archives = slices.Compact(archives)
+		warning := "Java archives are not catalogued: " + archives[0]
+		if len(archives) > 1 {
+			warning += fmt.Sprintf(" and %d more", len(archives)-1)
+		}
+		warn(warning)
+	}
+
+	sort.SliceStable(sbom.Packages, func(a, b int) bool {
+		if sbom.Packages[a].Type != sbom.Packages[b].Type {
+			return sbom.Packages[a].Type < sbom.Packages[b].Type
+		}
+		return sbom.Packages[a].Name < sbom.Packages[b].Name
+	})
+	return sbom
+}
+
+// parseStanzas splits RFC 822 style databases (dpkg status, apk installed,
+// Python METADATA) into one field map per blank-line separated paragraph.
+// Continuation lines are dropped since none of the fields we read span lines.
+func parseStanzas(data []byte, sep string) []map[string]string {
+	var stanzas []map[string]string
+	current := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				stanzas = append(stanzas, current)
+				current = map[string]string{}
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, sep)
+		if !ok {
+			continue
+		}
+		if _, exists := current[key]; !exists {
+			current[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(current) > 0 {
+		stanzas = append(stanzas, current)
+	}
+	return stanzas
+}
+
+func parseOSRelease(data []byte) model.OSRelease {
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok {
+			values[key] = strings.Trim(value, `"'`)
+		}
+	}
+	return model.OSRelease{ID: values["ID"], VersionID: values["VERSION_ID"], PrettyName: values["PRETTY_NAME"]}
+}
+
+func parseDpkgStatus(data []byte, osRelease model.OSRelease) []model.Package {
+	distro := osRelease.ID
+	if distro == "" {
+		distro = "debian"
+	}
+	var pkgs []model.Package
+	for _, s := range parseStanzas(data, ":") {
+		if s["Package"] == "" {
+			continue
+		}
+		// Distroless images ship status.d files without a Status field.
+		if status, ok := s["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		qualifiers := url.Values{}
+		if s["Architecture"] != "" {
+			qualifiers.Set("arch", s["Architecture"])
+		}
+		if osRelease.ID != "" && osRelease.VersionID != "" {
+			qualifiers.Set("distro", osRelease.ID+"-"+osRelease.VersionID)
+		}
+		pkgs = append(pkgs, model.Package{
+			Name:    s["Package"],
+			Version: s["Version"],
+			Type:    model.PackageTypeDeb,
+			Arch:    s["Architecture"],
+			PURL:    purl("deb", distro, s["Package"], s["Version"], qualifiers),
+		})
+	}
+	return pkgs
+}
+
+func parseAPKInstalled(data []byte) []model.Package {
+	var pkgs []model.Package
+	for _, s := range parseStanzas(data, ":") {
+		if s["P"] == "" {
+			continue
+		}
+		qualifiers := url.Values{}
+		if s["A"] != "" {
+			qualifiers.Set("arch", s["A"])
+		}
+		pkgs = append(pkgs, model.Package{
+			Name:    s["P"],
+			Version: s["V"],
+			Type:    model.PackageTypeAPK,
+			Arch:    s["A"],
+			License: s["L"],
+			PURL:    purl("apk", "alpine", s["P"], s["V"], qualifiers),
+		})
+	}
+	return pkgs
+}
+
+func parseNPMLock(data []byte) []model.Package {
+	type dependency struct {
+		Version      string                `json:"version"`
+		License      string                `json:"license"`
+		Link         bool                  `json:"link"`
+		Dependencies map[string]dependency `json:"dependencies"`
+	}
+	var lock struct {
+		Packages     map[string]dependency `json:"packages"`
+		Dependencies map[string]dependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil
+	}
+
+	var pkgs []model.Package
+	add := func(name string, dep dependency) {
+		if name == "" || dep.Version == "" || dep.Link {
+			return
+		}
+		pkgs = append(pkgs, model.Package{
+			Name:    name,
+			Version: dep.Version,
+			Type:    model.PackageTypeNPM,
+			License: dep.License,
+			PURL:    purl("npm", "", name, dep.Version, nil),
+		})
+	}
+
+	// Lockfile v2/v3 lists every installed package under "packages", keyed by
+	// its node_modules path; v1 only has the nested "dependencies" tree.
+	if len(lock.Packages) > 0 {
+		for key, dep := range lock.Packages {
+			if i := strings.LastIndex(key, "node_modules/"); i >= 0 {
+				add(key[i+len("node_modules/"):], dep)
+			}
+		}
+		return pkgs
+	}
+	var walk func(deps map[string]dependency)
+	walk = func(deps map[string]dependency) {
+		for name, dep := range deps {
+			add(name, dep)
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return pkgs
+}
+
+func parsePythonMetadata(data []byte) []model.Package {
+	stanzas := parseStanzas(data, ": ")
+	if len(stanzas) == 0 || stanzas[0]["Name"] == "" {
+		return nil
+	}
+	s := stanzas[0]
+	name := strings.ToLower(strings.ReplaceAll(s["Name"], "_", "-"))
+	return []model.Package{{
+		Name:    s["Name"],
+		Version: s["Version"],
+		Type:    model.PackageTypePyPI,
+		License: s["License"],
+		PURL:    purl("pypi", "", name, s["Version"], nil),
+	}}
+}
+
+// purl builds a package URL (https://github.com/package-url/purl-spec).
+func purl(typ, namespace, name, version string, qualifiers url.Values) string {
+	var b strings.Builder
+	b.WriteString("pkg:" + typ + "/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			b.WriteString(purlEscape(segment) + "/")
+		}
+	}
+	// Scoped npm packages carry their scope as the namespace.
+	if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+		b.WriteString(purlEscape(scope) + "/")
+		name = rest
+	}
+	b.WriteString(purlEscape(name))
+	if version != "" {
+		b.WriteString("@" + purlEscape(version))
+	}
+	if len(qualifiers) > 0 {
+		b.WriteString("?" + qualifiers.Encode())
+	}
+	return b.String()
+}
+
+func purlEscape(s string) string {
+	return strings.NewReplacer("@", "%40", ":", "%3A").Replace(url.PathEscape(s))
+}
@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// lockfiles maps the base names of the lockfiles catalogued besides
+// package-lock.json to their parser.
+var lockfiles = map[string]func([]byte) []model.Package{
+	"yarn.lock":      parseYarnLock,
+	"pnpm-lock.yaml": parsePnpmLock,
+	"go.sum":         parseGoSum,
+	"Cargo.lock":     parseCargoLock,
+	"poetry.lock":    parsePoetryLock,
+	"Gemfile.lock":   parseGemfileLock,
+}
+
+// lines splits a text file into lines without their terminators.
+func lines(data []byte) []string {
+	result := strings.Split(string(data), "\n")
+	for i, line := range result {
+		result[i] = strings.TrimSuffix(line, "\r")
+	}
+	return result
+}
+
+func npmPackage(name, version string) model.Package {
+	return model.Package{
+		Name:    name,
+		Version: version,
+		Type:    model.PackageTypeNPM,
+		PURL:    purl("npm", "", name, version, nil),
+	}
+}
+
+// parseYarnLock reads both the classic yarn.lock format, where entries look
+// like `lodash@^4.17.0, lodash@^4.17.21:` followed by `  version "4.17.21"`,
+// and the Berry one, `"lodash@npm:^4.17.21":` with `  version: 4.17.21`.
+func parseYarnLock(data []byte) []model.Package {
+	var pkgs []model.Package
+	name := ""
+	for _, line := range lines(data) {
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case line[0] != ' ':
+			spec, _, _ := strings.Cut(strings.TrimSuffix(line, ":"), ",")
+			spec = strings.Trim(spec, `"`)
+			name = ""
+			if n, rest, ok := cutPackageSpec(spec); ok && !isLocalYarnSpec(rest) {
+				name = n
+			}
+		case name != "" && !strings.HasPrefix(line, "   "):
+			field := strings.TrimSpace(line)
+			if rest, ok := strings.CutPrefix(field, "version"); ok && (strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, ":")) {
+				version := strings.Trim(strings.TrimSpace(strings.TrimPrefix(rest, ":")), `"`)
+				pkgs = append(pkgs, npmPackage(name, version))
+				name = ""
+			}
+		}
+	}
+	return pkgs
+}
+
+// cutPackageSpec splits an npm "name@range" at the first @ that does not
+// start a scope.
+func cutPackageSpec(spec string) (name, rest string, ok bool) {
+	if spec == "" {
+		return "", "", false
+	}
+	i := strings.Index(spec[1:], "@")
+	if i < 0 {
+		return "", "", false
+	}
+	return spec[:i+1], spec[i+2:], true
+}
+
+// isLocalYarnSpec reports whether a Berry range points into the project
+// rather than at a published package.
+func isLocalYarnSpec(spec string) bool {
+	for _, protocol := range []string{"workspace:", "link:", "portal:", "file:"} {
+		if strings.HasPrefix(spec, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePnpmLock reads the keys of the packages section of pnpm-lock.yaml:
+// `/name/1.0.0_peer@2.0.0:` up to lockfile version 5, `/name@1.0.0(peer@2.0.0):`
+// in version 6 and `name@1.0.0:` from version 9 on.
+func parsePnpmLock(data []byte) []model.Package {
+	var pkgs []model.Package
+	legacy, inPackages := false, false
+	for _, line := range lines(data) {
+		if line == "" {
+			continue
+		}
+		if line[0] != ' ' {
+			if v, ok := strings.CutPrefix(line, "lockfileVersion:"); ok {
+				major, _, _ := strings.Cut(strings.Trim(strings.TrimSpace(v), `'"`), ".")
+				n, err := strconv.Atoi(major)
+				legacy = err == nil && n < 6
+			}
+			inPackages = line == "packages:"
+			continue
+		}
+		if !inPackages || strings.HasPrefix(line, "   ") || !strings.HasSuffix(line, ":") {
+			continue
+		}
+		key := strings.TrimPrefix(strings.Trim(strings.TrimSuffix(strings.TrimSpace(line), ":"), `'"`), "/")
+		var name, version string
+		if legacy {
+			if i := strings.LastIndex(key, "/"); i > 0 {
+				name, version = key[:i], key[i+1:]
+				version, _, _ = strings.Cut(version, "_")
+			}
+		} else {
+			key, _, _ = strings.Cut(key, "(")
+			name, version, _ = cutPackageSpec(key)
+		}
+		// Tarball and git dependencies are keyed by their location.
+		if name == "" || version == "" || version[0] < '0' || version[0] > '9' {
+			continue
+		}
+		pkgs = append(pkgs, npmPackage(name, version))
+	}
+	return pkgs
+}
+
+func goPackage(module, version string) model.Package {
+	namespace, name := path.Split(module)
+	return model.Package{
+		Name:    module,
+		Version: version,
+		Type:    model.PackageTypeGo,
+		PURL:    purl("golang", strings.TrimSuffix(namespace, "/"), name, version, nil),
+	}
+}
+
+// parseGoSum lists the modules of go.sum, skipping the lines that only
+// check a go.mod file.
+func parseGoSum(data []byte) []model.Package {
+	var pkgs []model.Package
+	for _, line := range lines(data) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		pkgs = append(pkgs, goPackage(fields[0], fields[1]))
+	}
+	return pkgs
+}
+
+// parseGoModInfo lists the modules linked into a Go binary from its
+// embedded module lines. A "=>" line replaces the module above it, and a
+// replacement by a local directory has no version to report.
+func parseGoModInfo(modinfo string) []model.Package {
+	var pkgs []model.Package
+	last := false
+	for _, line := range lines([]byte(modinfo)) {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		switch fields[0] {
+		case "mod", "dep":
+			// The main module of a binary built from a checkout.
+			last = fields[2] != "(devel)"
+			if last {
+				pkgs = append(pkgs, goPackage(fields[1], fields[2]))
+			}
+		case "=>":
+			if last {
+				pkgs = pkgs[:len(pkgs)-1]
+			}
+			if last = fields[2] != ""; last {
+				pkgs = append(pkgs, goPackage(fields[1], fields[2]))
+			}
+		}
+	}
+	return pkgs
+}
+
+// parseTOMLPackages returns the string fields of every [[package]] table
+// of a TOML lockfile. Only the `key = "value"` lines both Cargo and Poetry
+// write for names and versions are read.
+func parseTOMLPackages(data []byte) []map[string]string {
+	var tables []map[string]string
+	var current map[string]string
+	for _, line := range lines(data) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			if line == "[[package]]" {
+				current = map[string]string{}
+				tables = append(tables, current)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if current == nil || !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			current[strings.TrimSpace(key)] = value[1 : len(value)-1]
+		}
+	}
+	return tables
+}
+
+func parseCargoLock(data []byte) []model.Package {
+	var pkgs []model.Package
+	for _, t := range parseTOMLPackages(data) {
+		if t["name"] == "" || t["version"] == "" {
+			continue
+		}
+		pkgs = append(pkgs, model.Package{
+			Name:    t["name"],
+			Version: t["version"],
+			Type:    model.PackageTypeCargo,
+			PURL:    purl("cargo", "", t["name"], t["version"], nil),
+		})
+	}
+	return pkgs
+}
+
+func parsePoetryLock(data []byte) []model.Package {
+	var pkgs []model.Package
+	for _, t := range parseTOMLPackages(data) {
+		if t["name"] == "" || t["version"] == "" {
+			continue
+		}
+		name := strings.ToLower(strings.ReplaceAll(t["name"], "_", "-"))
+		pkgs = append(pkgs, model.Package{
+			Name:    t["name"],
+			Version: t["version"],
+			Type:    model.PackageTypePyPI,
+			PURL:    purl("pypi", "", name, t["version"], nil),
+		})
+	}
+	return pkgs
+}
+
+// parseGemfileLock reads the gems listed under the specs of each source
+// section, `    rack (2.2.8)`; deeper indented lines are their dependencies.
+func parseGemfileLock(data []byte) []model.Package {
+	var pkgs []model.Package
+	inSpecs := false
+	for _, line := range lines(data) {
+		switch {
+		case line == "" || line[0] != ' ':
+			inSpecs = false
+		case strings.TrimSpace(line) == "specs:":
+			inSpecs = true
+		case inSpecs && strings.HasPrefix(line, "    ") && line[4] != ' ':
+			name, version, ok := strings.Cut(strings.TrimSpace(line), " (")
+			if !ok {
+				continue
+			}
+			version = strings.TrimSuffix(version, ")")
+			pkgs = append(pkgs, model.Package{
+				Name:    name,
+				Version: version,
+				Type:    model.PackageTypeGem,
+				PURL:    purl("gem", "", name, version, nil),
+			})
+		}
+	}
+	return pkgs
+}
@@ -0,0 +1,284 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivernova/orcahub/internal/docker/images/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// scanQueueSize bounds how many scans may wait for a free worker.
+const scanQueueSize = 256
+
+var (
+	ErrScannerNotConfigured = errors.New("vulnerability scanning is not configured")
+	ErrScanNotFound         = errors.New("image has not been scanned")
+	ErrScanQueueFull        = errors.New("scan queue is full")
+)
+
+var severityRank = map[string]int{
+	model.SeverityCritical: 0,
+	model.SeverityHigh:     1,
+	model.SeverityMedium:   2,
+	model.SeverityLow:      3,
+	model.SeverityUnknown:  4,
+}
+
+// ScanQueue runs vulnerability scans in the background and keeps the latest
+// report for every image, keyed by image ID (its config digest) so that
+// retagging an image does not invalidate its findings. Finished reports are
+// written to a directory when one is configured, so they survive restarts.
+// A rescan never hides the previous report: it stays visible until the new
+// scan has finished.
+type ScanQueue struct {
+	adapter adapter.ImageAdapter
+	jobs    chan string
+
+	mu      sync.RWMutex
+	scanner adapter.Scanner
+	dir     string
+	reports map[string]*model.ScanReport
+	pending map[string]*model.ScanReport
+}
+
+func NewScanQueue(adapter adapter.ImageAdapter) *ScanQueue {
+	return &ScanQueue{
+		adapter: adapter,
+		jobs:    make(chan string, scanQueueSize),
+		reports: make(map[string]*model.ScanReport),
+		pending: make(map[string]*model.ScanReport),
+	}
+}
+
+// UseReportDir loads the reports saved in dir and saves every finished
+// report there from now on.
+func (q *ScanQueue) UseReportDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create scan report directory: %w", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	reports := make(map[string]*model.ScanReport, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read scan report: %w", err)
+		}
+		var r model.ScanReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return fmt.Errorf("failed to parse scan report %s: %w", file, err)
+		}
+		reports[r.ImageID] = &r
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dir = dir
+	for id, r := range reports {
+		if _, ok := q.reports[id]; !ok {
+			q.reports[id] = r
+		}
+	}
+	return nil
+}
+
+// Start enables scanning with the given scanner and launches concurrency
+// workers that stop when ctx is done.
+func (q *ScanQueue) Start(ctx context.Context, scanner adapter.Scanner, concurrency int) {
+	q.mu.Lock()
+	q.scanner = scanner
+	q.mu.Unlock()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for i := 0; i < concurrency; i++ {
+		go q.work(ctx)
+	}
+}
+
+// Enqueue schedules a scan of the image. If a scan of the same image is
+// already pending its report is returned instead of queueing another one.
+func (q *ScanQueue) Enqueue(ctx context.Context, id string) (*model.ScanReport, error) {
+	q.mu.RLock()
+	scanner := q.scanner
+	q.mu.RUnlock()
+	if scanner == nil {
+		return nil, ErrScannerNotConfigured
+	}
+
+	img, err := q.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.pending[img.ID]; ok {
+		return q.view(img.ID), nil
+	}
+	select {
+	case q.jobs <- img.ID:
+	default:
+		return nil, ErrScanQueueFull
+	}
+	q.pending[img.ID] = &model.ScanReport{
+		ImageID:  img.ID,
+		Scanner:  scanner.Name(),
+		Status:   model.ScanQueued,
+		QueuedAt: time.Now().Unix(),
+	}
+	return q.view(img.ID), nil
+}
+
+// Report returns the full report of the image identified by id or reference.
+func (q *ScanQueue) Report(ctx context.Context, id string) (*model.ScanReport, error) {
+	img, err := q.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	report := q.view(img.ID)
+	if report == nil {
+		return nil, ErrScanNotFound
+	}
+	return report, nil
+}
+
+// Summary returns the report of the image without the vulnerability list,
+// or nil if the image has never been scanned.
+func (q *ScanQueue) Summary(imageID string) *model.ScanReport {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	summary := q.view(imageID)
+	if summary != nil {
+		summary.Vulnerabilities = nil
+	}
+	return summary
+}
+
+// view returns a copy of what is known about the image: the last completed
+// report with the state of any pending rescan, or the pending scan alone when
+// there is nothing better to show. The caller must hold q.mu.
+func (q *ScanQueue) view(id string) *model.ScanReport {
+	last, done := q.reports[id]
+	pending, queued := q.pending[id]
+	switch {
+	case queued && (!done || last.Status != model.ScanCompleted):
+		report := *pending
+		return &report
+	case done:
+		report := *last
+		if queued {
+			report.Pending = pending.Status
+		}
+		return &report
+	default:
+		return nil
+	}
+}
+
+func (q *ScanQueue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.jobs:
+			q.run(ctx, id)
+		}
+	}
+}
+
+func (q *ScanQueue) run(ctx context.Context, id string) {
+	q.mu.Lock()
+	scanner := q.scanner
+	report := *q.pending[id]
+	report.Status = model.ScanRunning
+	report.StartedAt = time.Now().Unix()
+	q.pending[id] = &report
+	q.mu.Unlock()
+
+	vulns, err := q.scan(ctx, scanner, id)
+
+	report.FinishedAt = time.Now().Unix()
+	if err != nil {
+		log.Printf("vulnerability scan of %s failed: %v", id, err)
+		report.Status = model.ScanFailed
+		report.Error = err.Error()
+	} else {
+		sort.SliceStable(vulns, func(a, b int) bool {
+			if severityRank[vulns[a].Severity] != severityRank[vulns[b].Severity] {
+				return severityRank[vulns[a].Severity] < severityRank[vulns[b].Severity]
+			}
+			return vulns[a].ID < vulns[b].ID
+		})
+		report.Status = model.ScanCompleted
+		report.Vulnerabilities = vulns
+		for _, v := range vulns {
+			report.Counts.Add(v.Severity)
+		}
+	}
+
+	q.mu.Lock()
+	delete(q.pending, id)
+	// A failed rescan keeps the last completed report, noting the failure.
+	if last, ok := q.reports[id]; ok && last.Status == model.ScanCompleted && report.Status == model.ScanFailed {
+		kept := *last
+		kept.Error = report.Error
+		report = kept
+	}
+	q.reports[id] = &report
+	dir := q.dir
+	q.mu.Unlock()
+
+	if dir != "" {
+		if err := saveScanReport(dir, &report); err != nil {
+			log.Printf("failed to save scan report of %s: %v", id, err)
+		}
+	}
+}
+
+// saveScanReport writes the report to dir, named after the image digest,
+// atomically so a crash never leaves it half written.
+func saveScanReport(dir string, report *model.ScanReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(dir, strings.ReplaceAll(report.ImageID, ":", "_")+".json")
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (q *ScanQueue) scan(ctx context.Context, scanner adapter.Scanner, id string) ([]model.Vulnerability, error) {
+	archive, err := q.adapter.Save(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	return scanner.Scan(ctx, archive)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
 )
@@ -12,7 +13,17 @@ type ImageService interface {
 	Delete(ctx context.Context, id string, opts model.RemoveOptions) (*model.RemoveResult, error)
 	Pull(ctx context.Context, opts model.PullOptions) error
 	Build(ctx context.Context, opts model.BuildOptions) (*model.BuildResult, error)
-	Prune(ctx context.Context) (model.PruneResult, error)
+	Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error)
 	Tag(ctx context.Context, opts model.TagOptions) error
 	History(ctx context.Context, id string) ([]model.HistoryEntry, error)
+	Layers(ctx context.Context, id string) (*model.LayerAnalysis, error)
+	Save(ctx context.Context, refs []string) (io.ReadCloser, error)
+	Load(ctx context.Context, input io.Reader) (*model.LoadResult, error)
+	Scan(ctx context.Context, id string) (*model.ScanReport, error)
+	ScanReport(ctx context.Context, id string) (*model.ScanReport, error)
+	Updates(ctx context.Context, refresh bool) ([]model.ImageUpdate, error)
+	SBOM(ctx context.Context, id string) (*model.SBOM, error)
+	Manifests(ctx context.Context, ref string, opts model.ManifestOptions) (*model.ManifestList, error)
+	VerifySignature(ctx context.Context, id string, refresh bool) (*model.SignatureReport, error)
+	Dependents(ctx context.Context, id string) (*model.ImageDependents, error)
 }
@@ -2,6 +2,11 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
 
 	"github.com/rivernova/orcahub/internal/docker/images/adapter"
 	model "github.com/rivernova/orcahub/internal/docker/images/model"
@@ -9,18 +14,41 @@ import (
 
 type ImageServiceImpl struct {
 	adapter adapter.ImageAdapter
+	updates *UpdateChecker
+	scans   *ScanQueue
+	sboms   *SBOMGenerator
+	sigs    *SignatureChecker
 }
 
 func NewImageServiceImpl(adapter adapter.ImageAdapter) *ImageServiceImpl {
-	return &ImageServiceImpl{adapter: adapter}
+	return &ImageServiceImpl{
+		adapter: adapter,
+		updates: NewUpdateChecker(adapter),
+		scans:   NewScanQueue(adapter),
+		sboms:   NewSBOMGenerator(adapter),
+		sigs:    NewSignatureChecker(adapter),
+	}
 }
 
 func (s *ImageServiceImpl) List(ctx context.Context) ([]model.Image, error) {
-	return s.adapter.List(ctx)
+	images, err := s.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		images[i].Scan = s.scans.Summary(images[i].ID)
+	}
+	return images, nil
 }
 
 func (s *ImageServiceImpl) Inspect(ctx context.Context, id string) (*model.Image, error) {
-	return s.adapter.Inspect(ctx, id)
+	img, err := s.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	img.Scan = s.scans.Summary(img.ID)
+	img.Signature = s.sigs.Cached(img.ID)
+	return img, nil
 }
 
 func (s *ImageServiceImpl) Delete(ctx context.Context, id string, opts model.RemoveOptions) (*model.RemoveResult, error) {
@@ -28,6 +56,9 @@ func (s *ImageServiceImpl) Delete(ctx context.Context, id string, opts model.Rem
 }
 
 func (s *ImageServiceImpl) Pull(ctx context.Context, opts model.PullOptions) error {
+	if err := validatePlatform(opts.Platform); err != nil {
+		return err
+	}
 	return s.adapter.Pull(ctx, opts)
 }
 
@@ -35,8 +66,33 @@ func (s *ImageServiceImpl) Build(ctx context.Context, opts model.BuildOptions) (
 	return s.adapter.Build(ctx, opts)
 }
 
-func (s *ImageServiceImpl) Prune(ctx context.Context) (model.PruneResult, error) {
-	return s.adapter.Prune(ctx)
+// Prune removes unused images matching opts. Plain filters are delegated to
+// the daemon; dry runs and KeepLast need a plan computed from the image list.
+func (s *ImageServiceImpl) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	if opts.KeepLast < 0 {
+		return model.PruneResult{}, fmt.Errorf("%w: keep_last must not be negative", ErrInvalidPruneOptions)
+	}
+	var cutoff time.Time
+	if opts.Until != "" {
+		var err error
+		if cutoff, err = parseUntil(opts.Until, time.Now()); err != nil {
+			return model.PruneResult{}, err
+		}
+		opts.Until = strconv.FormatInt(cutoff.Unix(), 10)
+	}
+	if !opts.DryRun && opts.KeepLast == 0 {
+		return s.adapter.Prune(ctx, opts)
+	}
+
+	images, err := s.adapter.ListUsage(ctx)
+	if err != nil {
+		return model.PruneResult{}, err
+	}
+	actions := planPrune(images, opts, cutoff)
+	if opts.DryRun {
+		return previewPrune(actions), nil
+	}
+	return s.executePrune(ctx, actions), nil
 }
 
 func (s *ImageServiceImpl) Tag(ctx context.Context, opts model.TagOptions) error {
@@ -46,3 +102,116 @@ func (s *ImageServiceImpl) Tag(ctx context.Context, opts model.TagOptions) error
 func (s *ImageServiceImpl) History(ctx context.Context, id string) ([]model.HistoryEntry, error) {
 	return s.adapter.History(ctx, id)
 }
+
+func (s *ImageServiceImpl) Layers(ctx context.Context, id string) (*model.LayerAnalysis, error) {
+	contents, err := s.adapter.LayerContents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeLayers(contents), nil
+}
+
+func (s *ImageServiceImpl) Save(ctx context.Context, refs []string) (io.ReadCloser, error) {
+	return s.adapter.Save(ctx, refs)
+}
+
+func (s *ImageServiceImpl) Load(ctx context.Context, input io.Reader) (*model.LoadResult, error) {
+	return s.adapter.Load(ctx, input)
+}
+
+func (s *ImageServiceImpl) Scan(ctx context.Context, id string) (*model.ScanReport, error) {
+	return s.scans.Enqueue(ctx, id)
+}
+
+func (s *ImageServiceImpl) ScanReport(ctx context.Context, id string) (*model.ScanReport, error) {
+	return s.scans.Report(ctx, id)
+}
+
+func (s *ImageServiceImpl) SBOM(ctx context.Context, id string) (*model.SBOM, error) {
+	return s.sboms.Generate(ctx, id)
+}
+
+// Manifests lists the platforms of ref, as stored locally or, when
+// opts.Remote is set, as published in the registry.
+func (s *ImageServiceImpl) Manifests(ctx context.Context, ref string, opts model.ManifestOptions) (*model.ManifestList, error) {
+	if opts.Remote {
+		return s.adapter.RemoteManifests(ctx, ref, opts.Auth)
+	}
+	img, err := s.adapter.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	list := &model.ManifestList{Ref: ref, Digest: img.ID, Manifests: img.Manifests}
+	if len(list.Manifests) == 0 {
+		// The classic image store keeps a single platform per image.
+		list.Manifests = []model.Manifest{{
+			Digest:    img.ID,
+			Kind:      model.ManifestKindImage,
+			Platform:  model.Platform{OS: img.Os, Architecture: img.Architecture, Variant: img.Variant},
+			Size:      img.Size,
+			Available: true,
+		}}
+	}
+	return list, nil
+}
+
+func (s *ImageServiceImpl) VerifySignature(ctx context.Context, id string, refresh bool) (*model.SignatureReport, error) {
+	return s.sigs.Verify(ctx, id, refresh)
+}
+
+// Admit rejects images whose signature cannot be verified and returns the
+// verified repo@digest, which callers must create containers from so the tag
+// cannot move to other content after the check. It lets the service act as
+// the image policy of the container service.
+func (s *ImageServiceImpl) Admit(ctx context.Context, ref string) (string, error) {
+	report, err := s.sigs.Verify(ctx, ref, false)
+	if err != nil {
+		return "", err
+	}
+	if !report.Verified {
+		if report.Error != "" {
+			return "", fmt.Errorf("%w: %s: %s", ErrUnsignedImage, ref, report.Error)
+		}
+		return "", fmt.Errorf("%w: %s", ErrUnsignedImage, ref)
+	}
+	return report.Ref, nil
+}
+
+// Rejected reports whether err is an Admit refusal, as opposed to a failure
+// to look up or verify the image.
+func (s *ImageServiceImpl) Rejected(err error) bool {
+	return errors.Is(err, ErrUnsignedImage)
+}
+
+// UseVerifier enables signature verification. Without it, VerifySignature
+// and Admit report ErrVerifierNotConfigured.
+func (s *ImageServiceImpl) UseVerifier(verifier adapter.Verifier) {
+	s.sigs.Configure(verifier)
+}
+
+// UseScanReportDir keeps finished scan reports in dir across restarts.
+func (s *ImageServiceImpl) UseScanReportDir(dir string) error {
+	return s.scans.UseReportDir(dir)
+}
+
+// StartScanner enables vulnerability scanning with up to concurrency scans
+// running at once. Without it, Scan reports ErrScannerNotConfigured.
+func (s *ImageServiceImpl) StartScanner(ctx context.Context, scanner adapter.Scanner, concurrency int) {
+	s.scans.Start(ctx, scanner, concurrency)
+}
+
+// Updates returns the cached update report, running a check first when
+// refresh is requested or no check has completed yet.
+func (s *ImageServiceImpl) Updates(ctx context.Context, refresh bool) ([]model.ImageUpdate, error) {
+	if !refresh {
+		if results, ok := s.updates.Results(); ok {
+			return results, nil
+		}
+	}
+	return s.updates.Check(ctx)
+}
+
+// WatchUpdates refreshes the update report every interval until ctx is done.
+func (s *ImageServiceImpl) WatchUpdates(ctx context.Context, interval time.Duration) {
+	s.updates.Run(ctx, interval)
+}
@@ -3,7 +3,12 @@ package domain_test
 import (
 	"context"
 	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rivernova/orcahub/internal/docker/images/domain"
 	"github.com/rivernova/orcahub/internal/docker/images/model"
@@ -42,10 +47,99 @@ func (m *mockImageAdapter) Build(ctx context.Context, opts model.BuildOptions) (
 	return args.Get(0).(*model.BuildResult), args.Error(1)
 }
 
-func (m *mockImageAdapter) Prune(ctx context.Context) (model.PruneResult, error) {
+func (m *mockImageAdapter) ListUsage(ctx context.Context) ([]model.Image, error) {
 	args := m.Called(ctx)
+	return args.Get(0).([]model.Image), args.Error(1)
+}
+func (m *mockImageAdapter) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := m.Called(ctx, opts)
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
+func (m *mockImageAdapter) Tag(ctx context.Context, opts model.TagOptions) error {
+	return m.Called(ctx, opts).Error(0)
+}
+func (m *mockImageAdapter) History(ctx context.Context, id string) ([]model.HistoryEntry, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.HistoryEntry), args.Error(1)
+}
+func (m *mockImageAdapter) LayerContents(ctx context.Context, id string) ([]model.LayerContent, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]model.LayerContent), args.Error(1)
+}
+func (m *mockImageAdapter) ReadFiles(ctx context.Context, id string, match func(path string) bool) (*model.ImageFiles, error) {
+	args := m.Called(ctx, id, mock.Anything)
+	var files model.ImageFiles
+	switch f := args.Get(0).(type) {
+	case map[string][]byte:
+		files.Files = f
+	case *model.ImageFiles:
+		files = *f
+	}
+	selected := &model.ImageFiles{Files: make(map[string][]byte), GoBinaries: files.GoBinaries}
+	for p, data := range files.Files {
+		if match(p) {
+			selected.Files[p] = data
+		}
+	}
+	return selected, args.Error(1)
+}
+func (m *mockImageAdapter) Save(ctx context.Context, refs []string) (io.ReadCloser, error) {
+	args := m.Called(ctx, refs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+func (m *mockImageAdapter) Load(ctx context.Context, input io.Reader) (*model.LoadResult, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LoadResult), args.Error(1)
+}
+func (m *mockImageAdapter) DistributionDigest(ctx context.Context, ref string) (string, error) {
+	args := m.Called(ctx, ref)
+	return args.String(0), args.Error(1)
+}
+func (m *mockImageAdapter) RemoteManifests(ctx context.Context, ref string, auth *model.RegistryAuth) (*model.ManifestList, error) {
+	args := m.Called(ctx, ref, auth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ManifestList), args.Error(1)
+}
+func (m *mockImageAdapter) ImageGraph(ctx context.Context) ([]model.ImageNode, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ImageNode), args.Error(1)
+}
+func (m *mockImageAdapter) ContainerImages(ctx context.Context) ([]model.ContainerImage, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.ContainerImage), args.Error(1)
+}
+
+type fakeScanner struct {
+	vulns []model.Vulnerability
+	err   error
+}
+
+func (f *fakeScanner) Name() string { return "fake" }
+func (f *fakeScanner) Scan(ctx context.Context, archive io.Reader) ([]model.Vulnerability, error) {
+	io.Copy(io.Discard, archive)
+	return f.vulns, f.err
+}
+
+type fakeVerifier struct {
+	calls  int
+	report model.SignatureReport
+}
+
+func (f *fakeVerifier) Name() string { return "fake" }
+func (f *fakeVerifier) Verify(ctx context.Context, ref string) (*model.SignatureReport, error) {
+	f.calls++
+	report := f.report
+	report.Ref = ref
+	return &report, nil
+}
 
 func TestImageService_List(t *testing.T) {
 	a := &mockImageAdapter{}
@@ -128,6 +222,29 @@ func TestImageService_Pull_WithAuth(t *testing.T) {
 	assert.NoError(t, svc.Pull(ctx, opts))
 }
 
+func TestImageService_Pull_Platform(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	opts := model.PullOptions{Image: "nginx:latest", Platform: "linux/arm64/v8"}
+	a.On("Pull", ctx, opts).Return(nil)
+
+	assert.NoError(t, svc.Pull(ctx, opts))
+}
+
+func TestImageService_Pull_InvalidPlatform(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	for _, platform := range []string{"arm64", "linux/", "linux/arm/v7/extra", "linux/arm 64"} {
+		err := svc.Pull(ctx, model.PullOptions{Image: "nginx:latest", Platform: platform})
+		assert.ErrorIs(t, err, domain.ErrInvalidPlatform, platform)
+	}
+	a.AssertNotCalled(t, "Pull", mock.Anything, mock.Anything)
+}
+
 func TestImageService_Build(t *testing.T) {
 	a := &mockImageAdapter{}
 	svc := domain.NewImageServiceImpl(a)
@@ -148,9 +265,912 @@ func TestImageService_Prune(t *testing.T) {
 	ctx := context.Background()
 
 	expected := model.PruneResult{Deleted: []string{"i1"}, SpaceReclaimed: 7890}
-	a.On("Prune", ctx).Return(expected, nil)
+	a.On("Prune", ctx, model.PruneOptions{}).Return(expected, nil)
 
-	result, err := svc.Prune(ctx)
+	result, err := svc.Prune(ctx, model.PruneOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestImageService_Prune_NormalizesUntil(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Prune", ctx, model.PruneOptions{DanglingOnly: true, Until: "1704067200", Labels: []string{"env=dev"}}).
+		Return(model.PruneResult{Deleted: []string{}}, nil)
+
+	_, err := svc.Prune(ctx, model.PruneOptions{DanglingOnly: true, Until: "2024-01-01", Labels: []string{"env=dev"}})
+	assert.NoError(t, err)
+	a.AssertExpectations(t)
+}
+
+func TestImageService_Prune_InvalidOptions(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	_, err := svc.Prune(ctx, model.PruneOptions{Until: "last tuesday"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPruneOptions)
+
+	_, err = svc.Prune(ctx, model.PruneOptions{KeepLast: -1})
+	assert.ErrorIs(t, err, domain.ErrInvalidPruneOptions)
+}
+
+func pruneFixture() []model.Image {
+	day := int64(24 * 60 * 60)
+	now := time.Now().Unix()
+	return []model.Image{
+		{ID: "sha256:app1", Tags: []string{"app:1"}, Created: now - 30*day, Size: 100, SharedSize: 40},
+		{ID: "sha256:app2", Tags: []string{"app:2", "mirror/app:2"}, Created: now - 20*day, Size: 100, SharedSize: 40},
+		{ID: "sha256:app3", Tags: []string{"app:3"}, Created: now - 9*day, Size: 100, SharedSize: 40},
+		{ID: "sha256:used", Tags: []string{"db:1"}, Created: now - 40*day, Size: 500, Containers: 1},
+		{ID: "sha256:dangling", Tags: []string{"<none>:<none>"}, Created: now - 5*day, Size: 50, SharedSize: -1},
+		{ID: "sha256:keep", Tags: []string{"tools:1"}, Created: now - 50*day, Size: 70, Labels: map[string]string{"keep": "true"}},
+	}
+}
+
+func TestImageService_Prune_DryRun(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ListUsage", ctx).Return(pruneFixture(), nil)
+
+	result, err := svc.Prune(ctx, model.PruneOptions{Until: "240h", ExcludeLabels: []string{"keep=true"}, DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{"sha256:app1", "sha256:app2"}, result.Deleted)
+	assert.Equal(t, []string{"app:1", "app:2", "mirror/app:2"}, result.Untagged)
+	assert.Equal(t, int64(120), result.SpaceReclaimed)
+	a.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	a.AssertNotCalled(t, "Prune", mock.Anything, mock.Anything)
+}
+
+func TestImageService_Prune_DanglingOnlyDryRun(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ListUsage", ctx).Return(pruneFixture(), nil)
+
+	result, err := svc.Prune(ctx, model.PruneOptions{DanglingOnly: true, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sha256:dangling"}, result.Deleted)
+	assert.Empty(t, result.Untagged)
+	assert.Equal(t, int64(50), result.SpaceReclaimed)
+}
+
+func TestImageService_Prune_KeepLast(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ListUsage", ctx).Return(pruneFixture(), nil)
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{{ContainerName: "db", ImageID: "sha256:used"}}, nil)
+	// app:2 and app:3 are the two newest app tags and mirror/app:2 is the only
+	// tag of its repository, so only app:1 and the dangling image go. Removing
+	// the last tag of app1 deletes the image, nothing is forced.
+	a.On("Delete", ctx, "app:1", model.RemoveOptions{}).
+		Return(&model.RemoveResult{Untagged: []string{"app:1"}, Deleted: []string{"sha256:app1"}}, nil)
+	a.On("Delete", ctx, "sha256:dangling", model.RemoveOptions{PruneChildren: true}).
+		Return(nil, errors.New("conflict"))
+
+	result, err := svc.Prune(ctx, model.PruneOptions{KeepLast: 2, ExcludeLabels: []string{"keep"}})
+	assert.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, []string{"sha256:app1"}, result.Deleted)
+	assert.Equal(t, []string{"app:1"}, result.Untagged)
+	assert.Equal(t, int64(60), result.SpaceReclaimed)
+	assert.Equal(t, []string{"conflict"}, result.Errors)
+	a.AssertExpectations(t)
+}
+
+func TestImageService_Prune_SkipsImagesNowInUse(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ListUsage", ctx).Return(pruneFixture(), nil)
+	// A container started using app1 after it was listed.
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{
+		{ContainerName: "db", ImageID: "sha256:used"},
+		{ContainerName: "late", ImageID: "sha256:app1"},
+	}, nil)
+	a.On("Delete", ctx, "sha256:dangling", model.RemoveOptions{PruneChildren: true}).
+		Return(&model.RemoveResult{Deleted: []string{"sha256:dangling"}}, nil)
+
+	result, err := svc.Prune(ctx, model.PruneOptions{KeepLast: 2, ExcludeLabels: []string{"keep"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sha256:dangling"}, result.Deleted)
+	assert.Empty(t, result.Untagged)
+	assert.Equal(t, []string{"skipped image sha256:app1: now used by container late"}, result.Errors)
+	a.AssertNotCalled(t, "Delete", ctx, "app:1", mock.Anything)
+	a.AssertExpectations(t)
+}
+
+func TestImageService_Layers(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("LayerContents", ctx, "sha256:abc").Return([]model.LayerContent{
+		{Digest: "sha256:l0", CreatedBy: "ADD rootfs /", Entries: []model.LayerEntry{
+			{Path: "/etc", Mode: fs.ModeDir | 0o755},
+			{Path: "/etc/config", Size: 100},
+			{Path: "/tmp/cache", Size: 300},
+			{Path: "/tmp/cache/a", Size: 50},
+		}},
+		{Digest: "sha256:l1", CreatedBy: "RUN update", Entries: []model.LayerEntry{
+			{Path: "/etc/config", Size: 120},
+			{Path: "/bin/app", Size: 200},
+		}},
+		{Digest: "sha256:l2", CreatedBy: "RUN cleanup", Entries: []model.LayerEntry{
+			{Path: "/tmp/cache", Whiteout: true},
+		}},
+	}, nil)
+
+	result, err := svc.Layers(ctx, "sha256:abc")
+	assert.NoError(t, err)
+	assert.Len(t, result.Layers, 3)
+
+	assert.Equal(t, int64(450), result.Layers[0].Size)
+	assert.Equal(t, []model.LayerFile{
+		{Path: "/bin/app", Size: 200, Change: model.FileAdded},
+		{Path: "/etc/config", Size: 120, Change: model.FileModified},
+	}, result.Layers[1].Files)
+	assert.Equal(t, []model.LayerFile{
+		{Path: "/tmp/cache", Size: 300, Change: model.FileRemoved},
+	}, result.Layers[2].Files)
+
+	assert.Equal(t, int64(770), result.TotalSize)
+	assert.Equal(t, int64(450), result.WastedSize)
+	assert.InDelta(t, 320.0/770.0, result.Efficiency, 0.0001)
+	assert.Equal(t, "/tmp/cache", result.Inefficiencies[0].Path)
+	assert.Equal(t, int64(300), result.Inefficiencies[0].WastedSize)
+	assert.Equal(t, 1, result.Inefficiencies[0].Occurrences)
+}
+
+func TestImageService_Layers_Occurrences(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("LayerContents", ctx, "img").Return([]model.LayerContent{
+		{Entries: []model.LayerEntry{
+			{Path: "/etc/config", Size: 5},
+			{Path: "/var/cache/apt/a/b", Size: 7},
+		}},
+		{Entries: []model.LayerEntry{{Path: "/etc/config", Size: 6}}},
+		{Entries: []model.LayerEntry{
+			{Path: "/etc/config", Size: 8},
+			{Path: "/var/cache", Whiteout: true},
+		}},
+	}, nil)
+
+	result, err := svc.Layers(ctx, "img")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.WastedFile{
+		{Path: "/etc/config", Occurrences: 2, WastedSize: 11},
+		{Path: "/var/cache/apt/a/b", Occurrences: 1, WastedSize: 7},
+	}, result.Inefficiencies)
+	assert.Equal(t, []model.LayerFile{
+		{Path: "/etc/config", Size: 8, Change: model.FileModified},
+		{Path: "/var/cache", Change: model.FileRemoved},
+	}, result.Layers[2].Files)
+}
+
+func TestImageService_Layers_Opaque(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("LayerContents", ctx, "img").Return([]model.LayerContent{
+		{Entries: []model.LayerEntry{{Path: "/app/old.js", Size: 10}}},
+		{Entries: []model.LayerEntry{
+			{Path: "/app/new.js", Size: 20},
+			{Path: "/app", Opaque: true},
+		}},
+	}, nil)
+
+	result, err := svc.Layers(ctx, "img")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LayerFile{
+		{Path: "/app/new.js", Size: 20, Change: model.FileAdded},
+		{Path: "/app/old.js", Size: 10, Change: model.FileRemoved},
+	}, result.Layers[1].Files)
+	assert.Equal(t, int64(10), result.WastedSize)
+}
+
+func TestImageService_Layers_Error(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("LayerContents", ctx, "nope").Return([]model.LayerContent(nil), errors.New("no such image"))
+
+	_, err := svc.Layers(ctx, "nope")
+	assert.Error(t, err)
+}
+
+func TestImageService_Save(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	refs := []string{"nginx:latest", "redis:7"}
+	a.On("Save", ctx, refs).Return(io.NopCloser(strings.NewReader("tar")), nil)
+
+	reader, err := svc.Save(ctx, refs)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	assert.Equal(t, "tar", string(data))
+}
+
+func TestImageService_Load(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	input := strings.NewReader("tar")
+	a.On("Load", ctx, input).Return(&model.LoadResult{Loaded: []string{"nginx:latest"}}, nil)
+
+	result, err := svc.Load(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx:latest"}, result.Loaded)
+}
+
+func TestImageService_Updates(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{
+		{ContainerID: "c1", ContainerName: "web", Image: "nginx", ImageID: "sha256:old"},
+		{ContainerID: "c2", ContainerName: "web-2", Image: "nginx:latest", ImageID: "sha256:old"},
+		{ContainerID: "c3", ContainerName: "cache", Image: "redis:7", ImageID: "sha256:redis"},
+	}, nil).Once()
+	a.On("Inspect", ctx, "sha256:old").Return(&model.Image{
+		RepoDigests: []string{"nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+	}, nil).Once()
+	a.On("Inspect", ctx, "sha256:redis").Return(&model.Image{
+		RepoDigests: []string{"redis@sha256:2222222222222222222222222222222222222222222222222222222222222222"},
+	}, nil).Once()
+	a.On("DistributionDigest", ctx, "nginx:latest").
+		Return("sha256:3333333333333333333333333333333333333333333333333333333333333333", nil).Once()
+	a.On("DistributionDigest", ctx, "redis:7").
+		Return("sha256:2222222222222222222222222222222222222222222222222222222222222222", nil).Once()
+
+	result, err := svc.Updates(ctx, false)
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.True(t, result[0].UpdateAvailable)
+	assert.True(t, result[1].UpdateAvailable)
+	assert.False(t, result[2].UpdateAvailable)
+	assert.Empty(t, result[2].Error)
+
+	// Served from cache: no further adapter calls expected.
+	cached, err := svc.Updates(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, result, cached)
+	a.AssertExpectations(t)
+}
+
+func TestImageService_Updates_Unresolvable(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{
+		{ContainerID: "c1", Image: "sha256:abc", ImageID: "sha256:abc"},
+		{ContainerID: "c2", Image: "myapp:dev", ImageID: "sha256:local"},
+		{ContainerID: "c3", Image: "private.example.com/app:1", ImageID: "sha256:priv"},
+	}, nil)
+	a.On("Inspect", ctx, "sha256:local").Return(&model.Image{}, nil)
+	a.On("Inspect", ctx, "sha256:priv").Return(&model.Image{
+		RepoDigests: []string{"private.example.com/app@sha256:4444444444444444444444444444444444444444444444444444444444444444"},
+	}, nil)
+	a.On("DistributionDigest", ctx, "private.example.com/app:1").Return("", errors.New("unauthorized"))
+
+	result, err := svc.Updates(ctx, true)
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	for _, u := range result {
+		assert.False(t, u.UpdateAvailable)
+		assert.NotEmpty(t, u.Error)
+	}
+	assert.NotEmpty(t, result[2].LocalDigest)
+}
+
+func TestImageService_Updates_PinnedContainer(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{{
+		ContainerID: "c1",
+		Image:       "nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		Tag:         "nginx:1.27",
+		ImageID:     "sha256:old",
+	}}, nil)
+	a.On("Inspect", ctx, "sha256:old").Return(&model.Image{
+		RepoDigests: []string{"nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+	}, nil)
+	a.On("DistributionDigest", ctx, "nginx:1.27").
+		Return("sha256:3333333333333333333333333333333333333333333333333333333333333333", nil)
+
+	result, err := svc.Updates(ctx, true)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Empty(t, result[0].Error)
+	assert.Equal(t, "nginx:1.27", result[0].Image)
+	assert.True(t, result[0].UpdateAvailable)
+}
+
+func TestImageService_Updates_ConcurrentRefresh(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{
+		{ContainerID: "c1", Image: "sha256:abc", ImageID: "sha256:abc"},
+	}, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+
+	results := make(chan []model.ImageUpdate, 2)
+	go func() {
+		result, _ := svc.Updates(ctx, true)
+		results <- result
+	}()
+	<-started
+	go func() {
+		result, _ := svc.Updates(ctx, true)
+		results <- result
+	}()
+	// Give the second refresh time to queue behind the first.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	first, second := <-results, <-results
+	assert.Len(t, first, 1)
+	assert.Equal(t, first, second)
+	a.AssertNumberOfCalls(t, "ContainerImages", 1)
+}
+
+func TestImageService_Updates_Error(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{}, errors.New("daemon error"))
+
+	_, err := svc.Updates(ctx, false)
+	assert.Error(t, err)
+}
+
+func TestImageService_Scan(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc.StartScanner(ctx, &fakeScanner{vulns: []model.Vulnerability{
+		{ID: "CVE-2", Severity: model.SeverityLow},
+		{ID: "CVE-1", Severity: model.SeverityCritical},
+		{ID: "CVE-3", Severity: model.SeverityCritical},
+	}}, 1)
+
+	a.On("Inspect", mock.Anything, "nginx:latest").Return(&model.Image{ID: "sha256:abc"}, nil)
+	a.On("Save", mock.Anything, []string{"sha256:abc"}).Return(io.NopCloser(strings.NewReader("tar")), nil)
+	a.On("List", ctx).Return([]model.Image{{ID: "sha256:abc"}, {ID: "sha256:other"}}, nil)
+
+	report, err := svc.Scan(ctx, "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:abc", report.ImageID)
+	assert.Equal(t, "fake", report.Scanner)
+
+	assert.Eventually(t, func() bool {
+		r, err := svc.ScanReport(ctx, "nginx:latest")
+		return err == nil && r.Status == model.ScanCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	report, err = svc.ScanReport(ctx, "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, model.SeverityCounts{Critical: 2, Low: 1}, report.Counts)
+	assert.Equal(t, []string{"CVE-1", "CVE-3", "CVE-2"}, []string{
+		report.Vulnerabilities[0].ID, report.Vulnerabilities[1].ID, report.Vulnerabilities[2].ID,
+	})
+
+	images, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, images[0].Scan.Counts.Critical)
+	assert.Nil(t, images[0].Scan.Vulnerabilities)
+	assert.Nil(t, images[1].Scan)
+}
+
+func TestImageService_Scan_Failed(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc.StartScanner(ctx, &fakeScanner{err: errors.New("db download failed")}, 1)
+	a.On("Inspect", mock.Anything, "sha256:abc").Return(&model.Image{ID: "sha256:abc"}, nil)
+	a.On("Save", mock.Anything, []string{"sha256:abc"}).Return(io.NopCloser(strings.NewReader("tar")), nil)
+
+	_, err := svc.Scan(ctx, "sha256:abc")
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		r, err := svc.ScanReport(ctx, "sha256:abc")
+		return err == nil && r.Status == model.ScanFailed && r.Error == "db download failed"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestImageService_Scan_PersistsReports(t *testing.T) {
+	dir := t.TempDir()
+	a := &mockImageAdapter{}
+	a.On("Inspect", mock.Anything, "nginx:latest").Return(&model.Image{ID: "sha256:abc"}, nil)
+	a.On("Save", mock.Anything, []string{"sha256:abc"}).Return(io.NopCloser(strings.NewReader("tar")), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc := domain.NewImageServiceImpl(a)
+	assert.NoError(t, svc.UseScanReportDir(dir))
+	svc.StartScanner(ctx, &fakeScanner{vulns: []model.Vulnerability{{ID: "CVE-1", Severity: model.SeverityHigh}}}, 1)
+	_, err := svc.Scan(ctx, "nginx:latest")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		return len(files) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	restarted := domain.NewImageServiceImpl(a)
+	assert.NoError(t, restarted.UseScanReportDir(dir))
+	report, err := restarted.ScanReport(ctx, "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScanCompleted, report.Status)
+	assert.Equal(t, "CVE-1", report.Vulnerabilities[0].ID)
+}
+
+// gatedScanner returns its results one scan at a time, when released.
+type gatedScanner struct {
+	results chan error
+}
+
+func (g *gatedScanner) Name() string { return "gated" }
+func (g *gatedScanner) Scan(ctx context.Context, archive io.Reader) ([]model.Vulnerability, error) {
+	io.Copy(io.Discard, archive)
+	if err := <-g.results; err != nil {
+		return nil, err
+	}
+	return []model.Vulnerability{{ID: "CVE-1", Severity: model.SeverityLow}}, nil
+}
+
+func TestImageService_Scan_RescanKeepsLastReport(t *testing.T) {
+	a := &mockImageAdapter{}
+	a.On("Inspect", mock.Anything, "sha256:abc").Return(&model.Image{ID: "sha256:abc"}, nil)
+	a.On("Save", mock.Anything, []string{"sha256:abc"}).Return(io.NopCloser(strings.NewReader("tar")), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scanner := &gatedScanner{results: make(chan error)}
+	svc := domain.NewImageServiceImpl(a)
+	svc.StartScanner(ctx, scanner, 1)
+
+	_, err := svc.Scan(ctx, "sha256:abc")
+	assert.NoError(t, err)
+	scanner.results <- nil
+	assert.Eventually(t, func() bool {
+		r, err := svc.ScanReport(ctx, "sha256:abc")
+		return err == nil && r.Status == model.ScanCompleted && r.Pending == ""
+	}, time.Second, 10*time.Millisecond)
+
+	report, err := svc.Scan(ctx, "sha256:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScanCompleted, report.Status)
+	assert.Equal(t, model.ScanQueued, report.Pending)
+	assert.Len(t, report.Vulnerabilities, 1)
+
+	scanner.results <- errors.New("db download failed")
+	assert.Eventually(t, func() bool {
+		r, err := svc.ScanReport(ctx, "sha256:abc")
+		return err == nil && r.Pending == "" && r.Error == "db download failed"
+	}, time.Second, 10*time.Millisecond)
+	report, err = svc.ScanReport(ctx, "sha256:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScanCompleted, report.Status)
+	assert.Len(t, report.Vulnerabilities, 1)
+}
+
+func TestImageService_Scan_NotConfigured(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+
+	_, err := svc.Scan(context.Background(), "nginx:latest")
+	assert.ErrorIs(t, err, domain.ErrScannerNotConfigured)
+}
+
+func TestImageService_ScanReport_NotScanned(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "nginx:latest").Return(&model.Image{ID: "sha256:abc"}, nil)
+
+	_, err := svc.ScanReport(ctx, "nginx:latest")
+	assert.ErrorIs(t, err, domain.ErrScanNotFound)
+}
+
+func TestImageService_SBOM(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	files := map[string][]byte{
+		"/etc/os-release": []byte("ID=debian\nVERSION_ID=\"12\"\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\n"),
+		"/var/lib/dpkg/status": []byte("Package: libc6\nStatus: install ok installed\nArchitecture: amd64\nVersion: 2.36-9\nDescription: GNU C Library\n continuation line\n\n" +
+			"Package: removed\nStatus: deinstall ok config-files\nVersion: 1.0\n"),
+		"/app/package-lock.json": []byte(`{"lockfileVersion": 3, "packages": {
+			"": {"name": "app", "version": "1.0.0"},
+			"node_modules/@types/node": {"version": "20.1.0", "license": "MIT"},
+			"node_modules/express": {"version": "4.18.2", "license": "MIT"}
+		}}`),
+		"/usr/lib/python3/dist-packages/PyYAML-6.0.dist-info/METADATA": []byte("Metadata-Version: 2.1\nName: PyYAML\nVersion: 6.0\nLicense: MIT\n\nlong description\n"),
+		"/usr/share/doc/readme.txt":                                    []byte("ignored"),
+	}
+	a.On("Inspect", ctx, "app:1.0").Return(&model.Image{ID: "sha256:abc", Tags: []string{"app:1.0"}}, nil)
+	a.On("ReadFiles", ctx, "sha256:abc", mock.Anything).Return(files, nil).Once()
+
+	sbom, err := svc.SBOM(ctx, "app:1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "app:1.0", sbom.Name)
+	assert.Equal(t, "debian", sbom.OS.ID)
+
+	purls := make([]string, 0, len(sbom.Packages))
+	for _, p := range sbom.Packages {
+		purls = append(purls, p.PURL)
+	}
+	assert.Equal(t, []string{
+		"pkg:deb/debian/libc6@2.36-9?arch=amd64&distro=debian-12",
+		"pkg:npm/%40types/node@20.1.0",
+		"pkg:npm/express@4.18.2",
+		"pkg:pypi/pyyaml@6.0",
+	}, purls)
+	assert.Equal(t, "/app/package-lock.json", sbom.Packages[1].Location)
+
+	// A second request for the same image is served from the cache.
+	again, err := svc.SBOM(ctx, "app:1.0")
+	assert.NoError(t, err)
+	assert.Same(t, sbom, again)
+	a.AssertNumberOfCalls(t, "ReadFiles", 1)
+}
+
+func TestImageService_SBOM_Alpine(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	files := map[string][]byte{
+		"/etc/os-release":       []byte("ID=alpine\nVERSION_ID=3.19.1\n"),
+		"/lib/apk/db/installed": []byte("C:Q1abc=\nP:musl\nV:1.2.4-r2\nA:x86_64\nL:MIT\n\nP:busybox\nV:1.36.1-r15\nA:x86_64\nL:GPL-2.0-only\n"),
+	}
+	a.On("Inspect", ctx, "sha256:def").Return(&model.Image{ID: "sha256:def"}, nil)
+	a.On("ReadFiles", ctx, "sha256:def", mock.Anything).Return(files, nil)
+
+	sbom, err := svc.SBOM(ctx, "sha256:def")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:def", sbom.Name)
+	assert.Len(t, sbom.Packages, 2)
+	assert.Equal(t, "busybox", sbom.Packages[0].Name)
+	assert.Equal(t, "GPL-2.0-only", sbom.Packages[0].License)
+	assert.Equal(t, "pkg:apk/alpine/musl@1.2.4-r2?arch=x86_64", sbom.Packages[1].PURL)
+}
+
+func TestImageService_SBOM_RPMIncomplete(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	files := map[string][]byte{
+		"/etc/os-release":                    []byte("ID=\"rhel\"\nVERSION_ID=\"9.3\"\n"),
+		"/usr/lib/sysimage/rpm/rpmdb.sqlite": []byte("SQLite format 3\x00"),
+	}
+	a.On("Inspect", ctx, "ubi9").Return(&model.Image{ID: "sha256:ubi"}, nil)
+	a.On("ReadFiles", ctx, "sha256:ubi", mock.Anything).Return(files, nil)
+
+	sbom, err := svc.SBOM(ctx, "ubi9")
+	assert.NoError(t, err)
+	assert.Equal(t, "rhel", sbom.OS.ID)
+	assert.Empty(t, sbom.Packages)
+	assert.True(t, sbom.Incomplete)
+	assert.Equal(t, []string{"rpm packages in /usr/lib/sysimage/rpm/rpmdb.sqlite are not catalogued"}, sbom.Warnings)
+}
+
+func TestImageService_SBOM_Lockfiles(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	files := &model.ImageFiles{
+		Files: map[string][]byte{
+			"/app/yarn.lock": []byte("# yarn lockfile v1\n\n" +
+				"\"@babel/code-frame@^7.0.0\", \"@babel/code-frame@^7.10.4\":\n  version \"7.12.13\"\n  dependencies:\n    chalk \"^2.0.0\"\n\n" +
+				"lodash@^4.17.21:\n  version \"4.17.21\"\n"),
+			"/berry/yarn.lock": []byte("__metadata:\n  version: 6\n\n" +
+				"\"app@workspace:.\":\n  version: 0.0.0-use.local\n\n" +
+				"\"left-pad@npm:^1.3.0\":\n  version: 1.3.0\n  resolution: \"left-pad@npm:1.3.0\"\n"),
+			"/web/pnpm-lock.yaml": []byte("lockfileVersion: '6.0'\n\ndependencies:\n  react:\n    version: 18.2.0\n\n" +
+				"packages:\n\n  /react@18.2.0:\n    resolution: {integrity: sha512-x}\n\n" +
+				"  /@types/react@18.2.0(react@18.2.0):\n    dev: true\n"),
+			"/old/pnpm-lock.yaml": []byte("lockfileVersion: 5.4\n\npackages:\n\n  /string_decoder/1.3.0_react@18.2.0:\n    dev: false\n"),
+			"/src/go.sum":         []byte("golang.org/x/text v0.14.0 h1:abc=\ngolang.org/x/text v0.14.0/go.mod h1:def=\n"),
+			"/src/Cargo.lock":     []byte("version = 3\n\n[[package]]\nname = \"serde\"\nversion = \"1.0.193\"\nsource = \"registry+https://github.com/rust-lang/crates.io-index\"\n\n[metadata]\nname = \"ignored\"\n"),
+			"/src/poetry.lock":    []byte("[[package]]\nname = \"Flask_Login\"\nversion = \"0.6.3\"\n\n[package.dependencies]\nflask = \">=1.0.4\"\n"),
+			"/src/Gemfile.lock":   []byte("GEM\r\n  remote: https://rubygems.org/\r\n  specs:\r\n    rack (2.2.8)\r\n    rails (7.1.2)\r\n      rack (>= 2.2.4)\r\n\r\nPLATFORMS\r\n  ruby\r\n"),
+		},
+		GoBinaries: map[string]string{
+			"/usr/local/bin/server": "path\texample.com/server\nmod\texample.com/server\t(devel)\t\n" +
+				"dep\tgithub.com/gin-gonic/gin\tv1.9.1\th1:x=\n" +
+				"dep\tgolang.org/x/net\tv0.17.0\th1:y=\n=>\tgolang.org/x/net\tv0.19.0\th1:z=\n" +
+				"dep\texample.com/local\tv1.0.0\t\n=>\t../local\t\t\n",
+			"/usr/local/bin/legacy": "",
+		},
+	}
+	a.On("Inspect", ctx, "app:2.0").Return(&model.Image{ID: "sha256:lock"}, nil)
+	a.On("ReadFiles", ctx, "sha256:lock", mock.Anything).Return(files, nil)
+
+	sbom, err := svc.SBOM(ctx, "app:2.0")
+	assert.NoError(t, err)
+	purls := make([]string, 0, len(sbom.Packages))
+	for _, p := range sbom.Packages {
+		purls = append(purls, p.PURL)
+	}
+	assert.ElementsMatch(t, []string{
+		"pkg:npm/%40babel/code-frame@7.12.13",
+		"pkg:npm/lodash@4.17.21",
+		"pkg:npm/left-pad@1.3.0",
+		"pkg:npm/react@18.2.0",
+		"pkg:npm/%40types/react@18.2.0",
+		"pkg:npm/string_decoder@1.3.0",
+		"pkg:golang/golang.org/x/text@v0.14.0",
+		"pkg:golang/github.com/gin-gonic/gin@v1.9.1",
+		"pkg:golang/golang.org/x/net@v0.19.0",
+		"pkg:cargo/serde@1.0.193",
+		"pkg:pypi/flask-login@0.6.3",
+		"pkg:gem/rack@2.2.8",
+		"pkg:gem/rails@7.1.2",
+	}, purls)
+	assert.True(t, sbom.Incomplete)
+	assert.Equal(t, []string{"modules of Go binary /usr/local/bin/legacy are not catalogued: it was built before Go 1.18"}, sbom.Warnings)
+}
+
+func TestImageService_SBOM_JavaArchives(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	files := map[string][]byte{
+		"/app/app.jar":            []byte("PK"),
+		"/usr/local/tomcat/a.war": []byte("PK"),
+	}
+	a.On("Inspect", ctx, "tomcat").Return(&model.Image{ID: "sha256:java"}, nil)
+	a.On("ReadFiles", ctx, "sha256:java", mock.Anything).Return(files, nil)
+
+	sbom, err := svc.SBOM(ctx, "tomcat")
+	assert.NoError(t, err)
+	assert.Empty(t, sbom.Packages)
+	assert.True(t, sbom.Incomplete)
+	assert.Equal(t, []string{"Java archives are not catalogued: /app/app.jar and 1 more"}, sbom.Warnings)
+}
+
+func TestImageService_SBOM_InspectError(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "nope").Return(nil, errors.New("no such image"))
+
+	_, err := svc.SBOM(ctx, "nope")
+	assert.Error(t, err)
+}
+
+func TestImageService_Manifests_Local(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	manifests := []model.Manifest{
+		{Digest: "sha256:amd", Kind: model.ManifestKindImage, Platform: model.Platform{OS: "linux", Architecture: "amd64"}, Available: true},
+		{Digest: "sha256:arm", Kind: model.ManifestKindImage, Platform: model.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+	}
+	a.On("Inspect", ctx, "nginx:latest").Return(&model.Image{ID: "sha256:index", Manifests: manifests}, nil)
+
+	list, err := svc.Manifests(ctx, "nginx:latest", model.ManifestOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:index", list.Digest)
+	assert.False(t, list.Remote)
+	assert.Equal(t, manifests, list.Manifests)
+}
+
+func TestImageService_Manifests_ClassicStore(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "nginx:latest").Return(&model.Image{
+		ID: "sha256:abc", Os: "linux", Architecture: "arm", Variant: "v7", Size: 1024,
+	}, nil)
+
+	list, err := svc.Manifests(ctx, "nginx:latest", model.ManifestOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Manifests, 1)
+	assert.Equal(t, "linux/arm/v7", list.Manifests[0].Platform.String())
+	assert.True(t, list.Manifests[0].Available)
+}
+
+func TestImageService_Manifests_Remote(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	expected := &model.ManifestList{Ref: "nginx:latest", Digest: "sha256:index", Remote: true}
+	auth := &model.RegistryAuth{Username: "bot", Password: "s3cret"}
+	a.On("RemoteManifests", ctx, "nginx:latest", auth).Return(expected, nil)
+
+	list, err := svc.Manifests(ctx, "nginx:latest", model.ManifestOptions{Remote: true, Auth: auth})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, list)
+	a.AssertNotCalled(t, "Inspect", mock.Anything, mock.Anything)
+}
+
+func TestImageService_VerifySignature(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+	verifier := &fakeVerifier{report: model.SignatureReport{
+		Verifier:   "fake",
+		Verified:   true,
+		Signatures: []model.Signature{{Digest: "sha256:abc"}},
+	}}
+	svc.UseVerifier(verifier)
+
+	a.On("Inspect", ctx, "ghcr.io/acme/app:1.0").Return(&model.Image{
+		ID:          "sha256:img",
+		RepoDigests: []string{"docker.io/library/app@sha256:other", "ghcr.io/acme/app@sha256:abc"},
+	}, nil)
+
+	report, err := svc.VerifySignature(ctx, "ghcr.io/acme/app:1.0", false)
+	assert.NoError(t, err)
+	assert.True(t, report.Verified)
+	assert.Equal(t, "sha256:img", report.ImageID)
+	assert.Equal(t, "ghcr.io/acme/app@sha256:abc", report.Ref)
+
+	// The result is reused until it expires or a refresh is requested.
+	_, err = svc.VerifySignature(ctx, "ghcr.io/acme/app:1.0", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, verifier.calls)
+	_, err = svc.VerifySignature(ctx, "ghcr.io/acme/app:1.0", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, verifier.calls)
+
+	img, err := svc.Inspect(ctx, "ghcr.io/acme/app:1.0")
+	assert.NoError(t, err)
+	assert.NotNil(t, img.Signature)
+	assert.True(t, img.Signature.Verified)
+}
+
+func TestImageService_VerifySignature_NotConfigured(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+
+	_, err := svc.VerifySignature(context.Background(), "nginx:latest", false)
+	assert.ErrorIs(t, err, domain.ErrVerifierNotConfigured)
+}
+
+func TestImageService_Admit(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+	verifier := &fakeVerifier{}
+	svc.UseVerifier(verifier)
+
+	a.On("Inspect", ctx, "local:dev").Return(&model.Image{ID: "sha256:local"}, nil)
+	a.On("Inspect", ctx, "nginx:latest").Return(&model.Image{
+		ID: "sha256:nginx", RepoDigests: []string{"nginx@sha256:abc"},
+	}, nil)
+
+	_, err := svc.Admit(ctx, "local:dev")
+	assert.ErrorIs(t, err, domain.ErrUnsignedImage)
+	assert.Contains(t, err.Error(), "no registry digest")
+	assert.Equal(t, 0, verifier.calls)
+
+	verifier.report = model.SignatureReport{Error: "no signatures found"}
+	_, err = svc.Admit(ctx, "nginx:latest")
+	assert.ErrorIs(t, err, domain.ErrUnsignedImage)
+	assert.True(t, svc.Rejected(err))
+
+	verifier.report = model.SignatureReport{Verified: true}
+	_, err = svc.VerifySignature(ctx, "nginx:latest", true)
+	assert.NoError(t, err)
+	pinned, err := svc.Admit(ctx, "nginx:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx@sha256:abc", pinned)
+}
+
+func TestImageService_Admit_MissingImage(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+	svc.UseVerifier(&fakeVerifier{})
+
+	a.On("Inspect", ctx, "nope:1").Return(nil, errors.New("no such image"))
+
+	_, err := svc.Admit(ctx, "nope:1")
+	assert.Error(t, err)
+	assert.False(t, svc.Rejected(err))
+}
+
+func TestImageService_Admit_OtherRepository(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+	verifier := &fakeVerifier{report: model.SignatureReport{Verified: true}}
+	svc.UseVerifier(verifier)
+
+	// The same content was pulled from a signed repository and retagged as
+	// evil/app; the signed digest says nothing about evil/app.
+	img := &model.Image{ID: "sha256:0123abcd", RepoDigests: []string{"ghcr.io/acme/app@sha256:abc"}}
+	a.On("Inspect", ctx, "evil/app:1").Return(img, nil)
+	a.On("Inspect", ctx, "ghcr.io/acme/app@sha256:def").Return(img, nil)
+	a.On("Inspect", ctx, "0123ab").Return(img, nil)
+
+	_, err := svc.Admit(ctx, "evil/app:1")
+	assert.ErrorIs(t, err, domain.ErrUnsignedImage)
+	assert.Contains(t, err.Error(), "requested repository")
+	_, err = svc.Admit(ctx, "ghcr.io/acme/app@sha256:def")
+	assert.ErrorIs(t, err, domain.ErrUnsignedImage)
+	assert.Equal(t, 0, verifier.calls)
+
+	// By ID no repository was named, so any of the image's digests will do.
+	pinned, err := svc.Admit(ctx, "0123ab")
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcr.io/acme/app@sha256:abc", pinned)
+}
+
+func TestImageService_Dependents(t *testing.T) {
+	a := &mockImageAdapter{}
+	svc := domain.NewImageServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "debian:12").Return(&model.Image{ID: "sha256:base", Tags: []string{"debian:12"}}, nil)
+	a.On("ContainerImages", ctx).Return([]model.ContainerImage{
+		{ContainerID: "c1", ContainerName: "shell", Image: "debian:12", ImageID: "sha256:base", State: "exited"},
+		{ContainerID: "c2", ContainerName: "web", Image: "nginx", ImageID: "sha256:nginx", State: "running"},
+	}, nil)
+	a.On("ImageGraph", ctx).Return([]model.ImageNode{
+		{ID: "sha256:base", Layers: []string{"l1"}, Created: 1},
+		// Pulled images only share the layer chain.
+		{ID: "sha256:app", Tags: []string{"app:1"}, Layers: []string{"l1", "l2"}, Created: 2},
+		{ID: "sha256:app-debug", Tags: []string{"app:debug"}, Layers: []string{"l1", "l2", "l3"}, Created: 3},
+		// Local builds link to their parent.
+		{ID: "sha256:step", ParentID: "sha256:base", Layers: []string{"l1"}, Created: 4},
+		{ID: "sha256:tool", ParentID: "sha256:step", Tags: []string{"tool:1"}, Layers: []string{"l1", "l4"}, Created: 5},
+		{ID: "sha256:nginx", Layers: []string{"n1"}, Created: 6},
+	}, nil)
+
+	deps, err := svc.Dependents(ctx, "debian:12")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:base", deps.ImageID)
+	assert.Len(t, deps.Containers, 1)
+	assert.Equal(t, "c1", deps.Containers[0].ContainerID)
+	assert.Equal(t, []model.ChildImage{
+		{ID: "sha256:app", Tags: []string{"app:1"}, Created: 2, Direct: true},
+		{ID: "sha256:app-debug", Tags: []string{"app:debug"}, Created: 3, Direct: false},
+		{ID: "sha256:step", Created: 4, Direct: true},
+		{ID: "sha256:tool", Tags: []string{"tool:1"}, Created: 5, Direct: false},
+	}, deps.Children)
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/rivernova/orcahub/internal/docker/images/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+// signatureTTL is how long a verification result is reused. Signatures can
+// be added to or revoked from an immutable digest, so results do expire.
+const signatureTTL = 15 * time.Minute
+
+var (
+	ErrVerifierNotConfigured = errors.New("signature verification is not configured")
+	ErrUnsignedImage         = errors.New("image signature could not be verified")
+)
+
+// SignatureChecker verifies image signatures in the registry and keeps the
+// latest result per image ID.
+type SignatureChecker struct {
+	adapter adapter.ImageAdapter
+
+	mu       sync.RWMutex
+	verifier adapter.Verifier
+	reports  map[string]*model.SignatureReport
+}
+
+func NewSignatureChecker(adapter adapter.ImageAdapter) *SignatureChecker {
+	return &SignatureChecker{adapter: adapter, reports: make(map[string]*model.SignatureReport)}
+}
+
+func (c *SignatureChecker) Configure(verifier adapter.Verifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.verifier = verifier
+	c.reports = make(map[string]*model.SignatureReport)
+}
+
+// Verify returns the signature report of the image identified by id or
+// reference, reusing a recent result unless refresh is set.
+func (c *SignatureChecker) Verify(ctx context.Context, id string, refresh bool) (*model.SignatureReport, error) {
+	c.mu.RLock()
+	verifier := c.verifier
+	c.mu.RUnlock()
+	if verifier == nil {
+		return nil, ErrVerifierNotConfigured
+	}
+
+	img, err := c.adapter.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ref := signedRef(img, id)
+	if !refresh {
+		// A cached result only counts for the same repo@digest: the image
+		// may also be known under a repository whose signatures differ.
+		if cached := c.Cached(img.ID); cached != nil && cached.Ref == ref && time.Since(time.Unix(cached.CheckedAt, 0)) < signatureTTL {
+			return cached, nil
+		}
+	}
+
+	var report *model.SignatureReport
+	switch {
+	case ref == "" && len(img.RepoDigests) > 0:
+		report = &model.SignatureReport{Verifier: verifier.Name(), Error: "image has no registry digest from the requested repository"}
+	case ref == "":
+		// Signatures live next to the manifest in the registry, so an image
+		// that was built locally and never pushed cannot carry any.
+		report = &model.SignatureReport{Verifier: verifier.Name(), Error: "image has no registry digest to verify"}
+	default:
+		if report, err = verifier.Verify(ctx, ref); err != nil {
+			return nil, err
+		}
+	}
+	report.Ref = ref
+	report.ImageID = img.ID
+	report.CheckedAt = time.Now().Unix()
+
+	c.mu.Lock()
+	c.reports[img.ID] = report
+	c.mu.Unlock()
+	result := *report
+	return &result, nil
+}
+
+// Cached returns the last report of the image, or nil if it was never
+// verified.
+func (c *SignatureChecker) Cached(imageID string) *model.SignatureReport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.reports[imageID]
+	if !ok {
+		return nil
+	}
+	report := *r
+	return &report
+}
+
+// signedRef picks the repo@digest reference to verify. An image requested
+// by repository must have been pulled from that repository: a digest of the
+// same image from another repository proves nothing about the one the
+// caller named, so "" is returned instead. An image requested by ID may be
+// verified through any of its repositories.
+func signedRef(img *model.Image, requested string) string {
+	if len(img.RepoDigests) == 0 {
+		return ""
+	}
+	if isImageID(img.ID, requested) {
+		return img.RepoDigests[0]
+	}
+	named, err := reference.ParseNormalizedNamed(requested)
+	if err != nil {
+		return ""
+	}
+	canonical, pinned := named.(reference.Canonical)
+	for _, d := range img.RepoDigests {
+		repo, digest, _ := strings.Cut(d, "@")
+		digested, err := reference.ParseNormalizedNamed(repo)
+		if err != nil || digested.Name() != named.Name() {
+			continue
+		}
+		// A requested digest must be the one verified, not a sibling.
+		if !pinned || canonical.Digest().String() == digest {
+			return d
+		}
+	}
+	return ""
+}
+
+// isImageID reports whether requested is the image's ID or an abbreviation
+// of it rather than a reference.
+func isImageID(id, requested string) bool {
+	hexID := strings.TrimPrefix(id, "sha256:")
+	requested = strings.TrimPrefix(requested, "sha256:")
+	if len(requested) < 4 || !strings.HasPrefix(hexID, requested) {
+		return false
+	}
+	return strings.Trim(requested, "0123456789abcdef") == ""
+}
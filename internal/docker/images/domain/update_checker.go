@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/rivernova/orcahub/internal/docker/images/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/images/model"
+)
+
+var (
+	errImageIDRef = errors.New("container was created from an image ID, not a tag")
+	errDigestRef  = errors.New("image reference is pinned by digest")
+)
+
+// UpdateChecker compares the images used by containers against their
+// registries and keeps the latest results in memory.
+type UpdateChecker struct {
+	adapter adapter.ImageAdapter
+
+	// refresh serializes checks so the ticker and on-demand requests do not
+	// each run a pass against the registries.
+	refresh   sync.Mutex
+	mu        sync.RWMutex
+	results   []model.ImageUpdate
+	checked   bool
+	checkedAt time.Time
+}
+
+func NewUpdateChecker(adapter adapter.ImageAdapter) *UpdateChecker {
+	return &UpdateChecker{adapter: adapter}
+}
+
+// Results returns the outcome of the last check and whether a check has run yet.
+func (u *UpdateChecker) Results() ([]model.ImageUpdate, bool) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	result := make([]model.ImageUpdate, len(u.results))
+	copy(result, u.results)
+	return result, u.checked
+}
+
+// Run checks for updates immediately and then every interval until ctx is done.
+// A non-positive interval disables the schedule.
+func (u *UpdateChecker) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := u.Check(ctx); err != nil {
+			log.Printf("image update check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check queries the registry for every container image and caches the result.
+// Per-container failures are reported in ImageUpdate.Error rather than aborting the check.
+// A call that waited for another check to finish returns that check's result.
+func (u *UpdateChecker) Check(ctx context.Context) ([]model.ImageUpdate, error) {
+	requested := time.Now()
+	u.refresh.Lock()
+	defer u.refresh.Unlock()
+	u.mu.RLock()
+	fresh := u.checked && u.checkedAt.After(requested)
+	u.mu.RUnlock()
+	if fresh {
+		result, _ := u.Results()
+		return result, nil
+	}
+
+	containers, err := u.adapter.ContainerImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	remoteDigests := make(map[string]string)
+	remoteErrors := make(map[string]string)
+	repoDigests := make(map[string][]string)
+
+	result := make([]model.ImageUpdate, 0, len(containers))
+	for _, c := range containers {
+		// A container pinned by the image policy is checked against the tag
+		// it was requested with.
+		image := c.Image
+		if c.Tag != "" {
+			image = c.Tag
+		}
+		update := model.ImageUpdate{
+			ContainerID:   c.ContainerID,
+			ContainerName: c.ContainerName,
+			Image:         image,
+			ImageID:       c.ImageID,
+			CheckedAt:     now,
+		}
+
+		named, err := parseTaggedRef(image)
+		if err != nil {
+			update.Error = err.Error()
+			result = append(result, update)
+			continue
+		}
+
+		digests, ok := repoDigests[c.ImageID]
+		if !ok {
+			img, err := u.adapter.Inspect(ctx, c.ImageID)
+			if err != nil {
+				update.Error = err.Error()
+				result = append(result, update)
+				continue
+			}
+			digests = img.RepoDigests
+			repoDigests[c.ImageID] = digests
+		}
+		update.LocalDigest = localDigest(named, digests)
+		if update.LocalDigest == "" {
+			update.Error = "image has no registry digest (built or loaded locally)"
+			result = append(result, update)
+			continue
+		}
+
+		ref := reference.FamiliarString(named)
+		if _, ok := remoteDigests[ref]; !ok {
+			if _, failed := remoteErrors[ref]; !failed {
+				digest, err := u.adapter.DistributionDigest(ctx, ref)
+				if err != nil {
+					remoteErrors[ref] = err.Error()
+				} else {
+					remoteDigests[ref] = digest
+				}
+			}
+		}
+		if msg, failed := remoteErrors[ref]; failed {
+			update.Error = msg
+			result = append(result, update)
+			continue
+		}
+
+		update.RemoteDigest = remoteDigests[ref]
+		update.UpdateAvailable = update.RemoteDigest != update.LocalDigest
+		result = append(result, update)
+	}
+
+	u.mu.Lock()
+	u.results = result
+	u.checked = true
+	u.checkedAt = time.Now()
+	u.mu.Unlock()
+
+	return result, nil
+}
+
+// parseTaggedRef normalizes an image reference, defaulting to the latest tag.
+// References that cannot move (image IDs and digests) are rejected.
+func parseTaggedRef(image string) (reference.Named, error) {
+	if strings.HasPrefix(image, "sha256:") {
+		return nil, errImageIDRef
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return nil, errDigestRef
+	}
+	return reference.TagNameOnly(named), nil
+}
+
+// localDigest returns the digest recorded for the reference's repository.
+func localDigest(named reference.Named, repoDigests []string) string {
+	for _, rd := range repoDigests {
+		parsed, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		digested, ok := parsed.(reference.Digested)
+		if ok && parsed.Name() == named.Name() {
+			return digested.Digest().String()
+		}
+	}
+	return ""
+}
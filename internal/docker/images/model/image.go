@@ -1,14 +1,18 @@
 package model
 
+import "io/fs"
+
 type Image struct {
 	ID           string
 	Tags         []string
+	RepoDigests  []string
 	Size         int64
 	Created      int64
 	Labels       map[string]string
 	Containers   int64
 	Os           string
 	Architecture string
+	Variant      string
 	Author       string
 	Comment      string
 	Cmd          []string
@@ -18,11 +22,70 @@ type Image struct {
 	ExposedPorts []string
 	Layers       int
 	VirtualSize  int64
+	SharedSize   int64 // bytes shared with other images, -1 when not computed
+	Scan         *ScanReport
+	Signature    *SignatureReport
+	Manifests    []Manifest // per-platform manifests, reported by the containerd image store only
+}
+
+type Platform struct {
+	OS           string
+	Architecture string
+	Variant      string
+	OSVersion    string
+}
+
+// String formats the platform as os/arch[/variant], as accepted by pull.
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+const (
+	ManifestKindImage       = "image"
+	ManifestKindAttestation = "attestation"
+	ManifestKindUnknown     = "unknown"
+)
+
+type Manifest struct {
+	Digest       string
+	MediaType    string
+	Kind         string
+	Platform     Platform
+	Size         int64 // bytes of content (manifest, config and layers) for this platform
+	UnpackedSize int64
+	Available    bool // whether the platform's content is present locally
+	Containers   []string
+}
+
+// ManifestList describes a manifest list / OCI index and the platforms it
+// references, either from the local store or from the registry.
+type ManifestList struct {
+	Ref       string
+	Digest    string
+	MediaType string
+	Size      int64
+	Remote    bool
+	Manifests []Manifest
+	// Warning says why the manifests of a remote list only carry their
+	// platform.
+	Warning string
+}
+
+// ManifestOptions selects where Manifests reads from. Auth authenticates
+// with the registry when Remote is set.
+type ManifestOptions struct {
+	Remote bool
+	Auth   *RegistryAuth
 }
 
 type PullOptions struct {
-	Image string
-	Auth  *RegistryAuth
+	Image    string
+	Platform string // os/arch[/variant], empty for the daemon's platform
+	Auth     *RegistryAuth
 }
 
 type RegistryAuth struct {
@@ -56,9 +119,21 @@ type RemoveResult struct {
 	Untagged []string
 }
 
+type PruneOptions struct {
+	DanglingOnly  bool
+	Until         string   // duration ("24h"), RFC 3339 time, date or Unix timestamp
+	Labels        []string // key or key=value, all must match
+	ExcludeLabels []string // key or key=value, none may match
+	KeepLast      int      // newest tags to keep per repository, 0 keeps none
+	DryRun        bool
+}
+
 type PruneResult struct {
 	Deleted        []string `json:"deleted"`
+	Untagged       []string `json:"untagged,omitempty"`
 	SpaceReclaimed int64    `json:"space_reclaimed"`
+	DryRun         bool     `json:"dry_run"`
+	Errors         []string `json:"errors,omitempty"`
 }
 
 type TagOptions struct {
@@ -66,6 +141,10 @@ type TagOptions struct {
 	Target string
 }
 
+type LoadResult struct {
+	Loaded []string
+}
+
 type HistoryEntry struct {
 	ID        string   `json:"id"`
 	Created   int64    `json:"created"`
@@ -74,3 +153,131 @@ type HistoryEntry struct {
 	Comment   string   `json:"comment"`
 	Tags      []string `json:"tags"`
 }
+
+// ImageTagLabel records the tag a container was requested with when the
+// image policy pinned it to a digest, so its updates can still be tracked.
+const ImageTagLabel = "io.orcahub.image-tag"
+
+// ContainerImage is the image reference a container was created from.
+// Tag is set when Image was pinned by digest from that tag.
+type ContainerImage struct {
+	ContainerID   string
+	ContainerName string
+	Image         string
+	Tag           string
+	ImageID       string
+	State         string
+}
+
+// ImageNode is an image with the layer chain used to relate it to the images
+// built on top of it.
+type ImageNode struct {
+	ID       string
+	ParentID string // only set for images built locally with the classic builder
+	Tags     []string
+	Created  int64
+	Layers   []string
+}
+
+type ChildImage struct {
+	ID      string
+	Tags    []string
+	Created int64
+	Direct  bool // built directly on the image rather than on one of its children
+}
+
+// ImageDependents lists everything that references an image.
+type ImageDependents struct {
+	ImageID     string
+	Tags        []string
+	RepoDigests []string
+	Containers  []ContainerImage
+	Children    []ChildImage
+}
+
+// ImageUpdate compares a container's local image digest with the digest
+// currently published by the registry for the same reference.
+type ImageUpdate struct {
+	ContainerID     string
+	ContainerName   string
+	Image           string
+	ImageID         string
+	LocalDigest     string
+	RemoteDigest    string
+	UpdateAvailable bool
+	CheckedAt       int64
+	Error           string
+}
+
+// LayerEntry is a single tar entry inside an image layer as stored on disk,
+// before whiteouts are resolved against lower layers.
+type LayerEntry struct {
+	Path     string
+	Size     int64
+	Mode     fs.FileMode
+	Whiteout bool // Path was deleted by this layer
+	Opaque   bool // contents of directory Path from lower layers were hidden
+}
+
+// LayerContent is the raw content of one filesystem layer together with the
+// history entry that created it.
+type LayerContent struct {
+	Digest    string
+	Created   int64
+	CreatedBy string
+	Comment   string
+	Entries   []LayerEntry
+	Files     map[string][]byte // contents of captured files, when requested
+	// GoBinaries holds the module information of the layer's Go
+	// executables, read along with Files.
+	GoBinaries map[string]string
+}
+
+// ImageFiles is what was read from an image's final filesystem. GoBinaries
+// maps each Go executable to the module lines its linker embedded (path,
+// mod, dep and => records, tab separated); it is "" for binaries built
+// before Go 1.18, which keep them out of line.
+type ImageFiles struct {
+	Files      map[string][]byte
+	GoBinaries map[string]string
+}
+
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileRemoved  = "removed"
+)
+
+type LayerFile struct {
+	Path   string
+	Size   int64
+	Mode   fs.FileMode
+	Change string
+}
+
+type Layer struct {
+	Index     int
+	Digest    string
+	Size      int64
+	Created   int64
+	CreatedBy string
+	Comment   string
+	Files     []LayerFile
+}
+
+// WastedFile is a path whose bytes are shipped in more than one layer, either
+// because it was overwritten or because it was later removed. Occurrences
+// counts the discarded copies, not the layers the path appears in.
+type WastedFile struct {
+	Path        string
+	Occurrences int
+	WastedSize  int64
+}
+
+type LayerAnalysis struct {
+	Layers         []Layer
+	TotalSize      int64
+	WastedSize     int64
+	Efficiency     float64
+	Inefficiencies []WastedFile
+}
@@ -0,0 +1,46 @@
+package model
+
+const (
+	SBOMFormatSPDX      = "spdx-json"
+	SBOMFormatCycloneDX = "cyclonedx-json"
+)
+
+const (
+	PackageTypeDeb   = "deb"
+	PackageTypeAPK   = "apk"
+	PackageTypeNPM   = "npm"
+	PackageTypePyPI  = "pypi"
+	PackageTypeGo    = "golang"
+	PackageTypeCargo = "cargo"
+	PackageTypeGem   = "gem"
+)
+
+type OSRelease struct {
+	ID         string
+	VersionID  string
+	PrettyName string
+}
+
+type Package struct {
+	Name     string
+	Version  string
+	Type     string
+	Arch     string
+	License  string
+	PURL     string
+	Location string // file the package was catalogued from
+}
+
+// SBOM is the software bill of materials of an image, independent of the
+// document format it is exported in. Incomplete is set when the image has
+// package databases or binaries that could not be catalogued; Warnings says
+// which.
+type SBOM struct {
+	ImageID    string
+	Name       string
+	Created    int64
+	OS         OSRelease
+	Packages   []Package
+	Incomplete bool
+	Warnings   []string
+}
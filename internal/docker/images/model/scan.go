@@ -0,0 +1,65 @@
+package model
+
+const (
+	ScanQueued    = "queued"
+	ScanRunning   = "running"
+	ScanCompleted = "completed"
+	ScanFailed    = "failed"
+)
+
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+	SeverityUnknown  = "UNKNOWN"
+)
+
+type Vulnerability struct {
+	ID               string
+	PkgName          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         string
+	Title            string
+	URL              string
+}
+
+type SeverityCounts struct {
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+	Unknown  int
+}
+
+func (c *SeverityCounts) Add(severity string) {
+	switch severity {
+	case SeverityCritical:
+		c.Critical++
+	case SeverityHigh:
+		c.High++
+	case SeverityMedium:
+		c.Medium++
+	case SeverityLow:
+		c.Low++
+	default:
+		c.Unknown++
+	}
+}
+
+// ScanReport is the latest scan of an image, keyed by its content digest.
+// While a rescan is queued or running, the previous completed report is kept
+// and Pending holds the state of the new scan.
+type ScanReport struct {
+	ImageID         string
+	Scanner         string
+	Status          string
+	Pending         string
+	Error           string
+	QueuedAt        int64
+	StartedAt       int64
+	FinishedAt      int64
+	Counts          SeverityCounts
+	Vulnerabilities []Vulnerability
+}
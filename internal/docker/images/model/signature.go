@@ -0,0 +1,26 @@
+package model
+
+// SignatureReport is the outcome of verifying an image's signatures against
+// the configured key or keyless identity.
+type SignatureReport struct {
+	ImageID      string
+	Ref          string // repo@digest the signatures were looked up for
+	Verifier     string
+	Verified     bool
+	Error        string
+	CheckedAt    int64
+	Signatures   []Signature
+	Attestations []Attestation
+}
+
+type Signature struct {
+	DockerReference string
+	Digest          string
+	Issuer          string // OIDC issuer of keyless signatures
+	Subject         string // certificate identity of keyless signatures
+}
+
+type Attestation struct {
+	PredicateType string
+	Subjects      []string // digests the in-toto statement is about
+}
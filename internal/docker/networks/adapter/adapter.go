@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"context"
+	"io"
 
 	"github.com/rivernova/orcahub/internal/docker/networks/model"
 )
@@ -11,7 +12,16 @@ type NetworkAdapter interface {
 	Inspect(ctx context.Context, id string) (*model.Network, error)
 	Create(ctx context.Context, opts model.CreateNetworkOptions) (*model.Network, error)
 	Delete(ctx context.Context, id string) error
-	Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error
+	Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error)
 	Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error
 	Prune(ctx context.Context) (model.PruneResult, error)
+	// ContainerNetworks inspects every container, running or not, for its
+	// endpoints and published ports.
+	ContainerNetworks(ctx context.Context) ([]model.ContainerNetworks, error)
+	InspectContainer(ctx context.Context, id string) (*model.ContainerNetworks, error)
+	// Probe runs commands in a helper container sharing the network
+	// namespace of container id, which must be running.
+	Probe(ctx context.Context, id string, cmds [][]string) ([]model.ProbeResult, error)
+	ProbeNetwork(ctx context.Context, networkID string, cmds [][]string) ([]model.ProbeResult, error)
+	Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error)
 }
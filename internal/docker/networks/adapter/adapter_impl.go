@@ -3,7 +3,13 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
@@ -11,7 +17,9 @@ import (
 )
 
 type NetworkAdapterImpl struct {
-	client *client.Client
+	client       *client.Client
+	helperImage  string
+	captureImage string
 }
 
 func NewNetworkAdapterImpl() (*NetworkAdapterImpl, error) {
@@ -19,7 +27,7 @@ func NewNetworkAdapterImpl() (*NetworkAdapterImpl, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
-	return &NetworkAdapterImpl{client: cli}, nil
+	return &NetworkAdapterImpl{client: cli, helperImage: DefaultHelperImage, captureImage: DefaultCaptureImage}, nil
 }
 
 var _ NetworkAdapter = (*NetworkAdapterImpl)(nil)
@@ -42,25 +50,34 @@ func (a *NetworkAdapterImpl) List(ctx context.Context) ([]model.Network, error)
 			Labels:     n.Labels,
 			Options:    n.Options,
 			Created:    n.Created.String(),
+			EnableIPv6: n.EnableIPv6,
+			ConfigFrom: n.ConfigFrom.Network,
+			ConfigOnly: n.ConfigOnly,
+			IPAM:       toIPAM(n.IPAM),
 		})
 	}
 	return result, nil
 }
 
+func toIPAM(ipam network.IPAM) model.IPAM {
+	pools := make([]model.IPAMPool, 0, len(ipam.Config))
+	for _, p := range ipam.Config {
+		pools = append(pools, model.IPAMPool{
+			Subnet:       p.Subnet,
+			Gateway:      p.Gateway,
+			IPRange:      p.IPRange,
+			AuxAddresses: p.AuxAddress,
+		})
+	}
+	return model.IPAM{Driver: ipam.Driver, Options: ipam.Options, Config: pools}
+}
+
 func (a *NetworkAdapterImpl) Inspect(ctx context.Context, id string) (*model.Network, error) {
 	n, err := a.client.NetworkInspect(ctx, id, network.InspectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to inspect network %s: %w", id, err)
 	}
 
-	pools := make([]model.IPAMPool, 0, len(n.IPAM.Config))
-	for _, p := range n.IPAM.Config {
-		pools = append(pools, model.IPAMPool{
-			Subnet:  p.Subnet,
-			Gateway: p.Gateway,
-		})
-	}
-
 	containers := make(map[string]model.ContainerEndpoint, len(n.Containers))
 	for cid, c := range n.Containers {
 		containers[cid] = model.ContainerEndpoint{
@@ -68,6 +85,7 @@ func (a *NetworkAdapterImpl) Inspect(ctx context.Context, id string) (*model.Net
 			EndpointID:  c.EndpointID,
 			MacAddress:  c.MacAddress,
 			IPv4Address: c.IPv4Address,
+			IPv6Address: c.IPv6Address,
 		}
 	}
 
@@ -81,21 +99,24 @@ func (a *NetworkAdapterImpl) Inspect(ctx context.Context, id string) (*model.Net
 		Labels:     n.Labels,
 		Options:    n.Options,
 		Created:    n.Created.String(),
-		IPAM: model.IPAM{
-			Driver: n.IPAM.Driver,
-			Config: pools,
-		},
+		EnableIPv6: n.EnableIPv6,
+		ConfigFrom: n.ConfigFrom.Network,
+		ConfigOnly: n.ConfigOnly,
+		IPAM:       toIPAM(n.IPAM),
 		Containers: containers,
 	}, nil
 }
 
 func (a *NetworkAdapterImpl) Create(ctx context.Context, opts model.CreateNetworkOptions) (*model.Network, error) {
-	ipamConfig := []network.IPAMConfig{}
+	ipam := &network.IPAM{Config: []network.IPAMConfig{}}
 	if opts.IPAM != nil {
+		ipam.Driver, ipam.Options = opts.IPAM.Driver, opts.IPAM.Options
 		for _, p := range opts.IPAM.Config {
-			ipamConfig = append(ipamConfig, network.IPAMConfig{
-				Subnet:  p.Subnet,
-				Gateway: p.Gateway,
+			ipam.Config = append(ipam.Config, network.IPAMConfig{
+				Subnet:     p.Subnet,
+				Gateway:    p.Gateway,
+				IPRange:    p.IPRange,
+				AuxAddress: p.AuxAddresses,
 			})
 		}
 	}
@@ -105,16 +126,22 @@ func (a *NetworkAdapterImpl) Create(ctx context.Context, opts model.CreateNetwor
 		driver = "bridge"
 	}
 
-	resp, err := a.client.NetworkCreate(ctx, opts.Name, network.CreateOptions{
+	create := network.CreateOptions{
 		Driver:     driver,
 		Internal:   opts.Internal,
 		Attachable: opts.Attachable,
+		ConfigOnly: opts.ConfigOnly,
 		Labels:     opts.Labels,
 		Options:    opts.Options,
-		IPAM: &network.IPAM{
-			Config: ipamConfig,
-		},
-	})
+		IPAM:       ipam,
+	}
+	if opts.EnableIPv6 {
+		create.EnableIPv6 = &opts.EnableIPv6
+	}
+	if opts.ConfigFrom != "" {
+		create.ConfigFrom = &network.ConfigReference{Network: opts.ConfigFrom}
+	}
+	resp, err := a.client.NetworkCreate(ctx, opts.Name, create)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network %s: %w", opts.Name, err)
 	}
@@ -129,15 +156,42 @@ func (a *NetworkAdapterImpl) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (a *NetworkAdapterImpl) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error {
-	err := a.client.NetworkConnect(ctx, networkID, opts.ContainerID, &network.EndpointSettings{
-		IPAddress: opts.IPv4Address,
-		Aliases:   opts.Aliases,
-	})
+func (a *NetworkAdapterImpl) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error) {
+	settings := &network.EndpointSettings{
+		Aliases:    opts.Aliases,
+		Links:      opts.Links,
+		MacAddress: opts.MacAddress,
+		DriverOpts: opts.DriverOpts,
+	}
+	// The daemon only honours static addresses given as IPAM config; the
+	// top-level IPAddress is output only.
+	if opts.IPv4Address != "" || opts.IPv6Address != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address: opts.IPv4Address,
+			IPv6Address: opts.IPv6Address,
+		}
+	}
+	// networkID may be a name or a short ID; resolve it so the endpoint is
+	// looked up by the exact ID below.
+	n, err := a.client.NetworkInspect(ctx, networkID, network.InspectOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to connect container %s to network %s: %w", opts.ContainerID, networkID, err)
+		return nil, fmt.Errorf("failed to inspect network %s: %w", networkID, err)
 	}
-	return nil
+	if err := a.client.NetworkConnect(ctx, n.ID, opts.ContainerID, settings); err != nil {
+		return nil, fmt.Errorf("failed to connect container %s to network %s: %w", opts.ContainerID, n.Name, err)
+	}
+
+	// The container is connected from here on, so failing to read the
+	// endpoint back only leaves its details out.
+	cn, err := a.InspectContainer(ctx, opts.ContainerID)
+	if err == nil {
+		if ep, ok := cn.Endpoints[n.ID]; ok {
+			return &ep, nil
+		}
+		err = fmt.Errorf("no endpoint on network %s", n.Name)
+	}
+	log.Printf("connected container %s to network %s but could not read its endpoint: %v", opts.ContainerID, n.Name, err)
+	return &model.EndpointInfo{NetworkID: n.ID, NetworkName: n.Name}, nil
 }
 
 func (a *NetworkAdapterImpl) Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error {
@@ -156,3 +210,78 @@ func (a *NetworkAdapterImpl) Prune(ctx context.Context) (model.PruneResult, erro
 		Deleted: report.NetworksDeleted,
 	}, nil
 }
+
+func (a *NetworkAdapterImpl) ContainerNetworks(ctx context.Context) ([]model.ContainerNetworks, error) {
+	containers, err := a.client.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make([]model.ContainerNetworks, 0, len(containers))
+	for _, c := range containers {
+		info, err := a.client.ContainerInspect(ctx, c.ID)
+		if cerrdefs.IsNotFound(err) {
+			continue // removed since the list
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", c.ID, err)
+		}
+		result = append(result, toContainerNetworks(info))
+	}
+	return result, nil
+}
+
+func toContainerNetworks(info container.InspectResponse) model.ContainerNetworks {
+	cn := model.ContainerNetworks{
+		ID:        info.ID,
+		Name:      strings.TrimPrefix(info.Name, "/"),
+		Endpoints: map[string]model.EndpointInfo{},
+	}
+	if info.State != nil {
+		cn.State = info.State.Status
+	}
+	if info.HostConfig != nil {
+		cn.NetworkMode = string(info.HostConfig.NetworkMode)
+	}
+	if info.NetworkSettings == nil {
+		return cn
+	}
+	for name, ep := range info.NetworkSettings.Networks {
+		if ep == nil {
+			continue
+		}
+		cn.Endpoints[ep.NetworkID] = model.EndpointInfo{
+			NetworkID:   ep.NetworkID,
+			NetworkName: name,
+			EndpointID:  ep.EndpointID,
+			IPv4Address: ep.IPAddress,
+			IPv6Address: ep.GlobalIPv6Address,
+			Gateway:     ep.Gateway,
+			IPv6Gateway: ep.IPv6Gateway,
+			MacAddress:  ep.MacAddress,
+			Aliases:     ep.Aliases,
+			Links:       ep.Links,
+			DriverOpts:  ep.DriverOpts,
+		}
+	}
+	for port, bindings := range info.NetworkSettings.Ports {
+		for _, b := range bindings {
+			cn.Ports = append(cn.Ports, model.PublishedPort{
+				HostIP:        b.HostIP,
+				HostPort:      b.HostPort,
+				ContainerPort: port.Port(),
+				Protocol:      port.Proto(),
+			})
+		}
+	}
+	sort.Slice(cn.Ports, func(i, j int) bool {
+		pi, pj := cn.Ports[i], cn.Ports[j]
+		if pi.ContainerPort != pj.ContainerPort {
+			ci, _ := strconv.Atoi(pi.ContainerPort)
+			cj, _ := strconv.Atoi(pj.ContainerPort)
+			return ci < cj
+		}
+		return pi.HostIP+":"+pi.HostPort < pj.HostIP+":"+pj.HostPort
+	})
+	return cn
+}
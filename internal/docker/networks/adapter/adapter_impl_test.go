@@ -65,3 +65,15 @@ func TestNetworkAdapter_Inspect_NotFound(t *testing.T) {
 	_, err = a.Inspect(context.Background(), "nonexistent-network-id")
 	assert.Error(t, err)
 }
+
+func TestNetworkAdapter_ContainerNetworks(t *testing.T) {
+	a, err := adapter.NewNetworkAdapterImpl()
+	require.NoError(t, err)
+
+	containers, err := a.ContainerNetworks(context.Background())
+	assert.NoError(t, err)
+	for _, c := range containers {
+		assert.NotEmpty(t, c.ID)
+		assert.NotNil(t, c.Endpoints)
+	}
+}
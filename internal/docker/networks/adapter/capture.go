@@ -0,0 +1,120 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/pkg/stdcopy"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// DefaultCaptureImage is the image of the capture helpers. It needs tcpdump,
+// which busybox lacks.
+const DefaultCaptureImage = "nicolaka/netshoot:v0.13"
+
+// UseCaptureImage overrides DefaultCaptureImage.
+func (a *NetworkAdapterImpl) UseCaptureImage(image string) {
+	a.captureImage = image
+}
+
+// Capture runs tcpdump in a helper container sharing the network namespace
+// of opts.ContainerID and streams the pcap it writes. The stream ends when
+// tcpdump exits, after opts.MaxPackets packets or when opts.Duration has
+// passed; closing it early stops the capture and removes the helper.
+func (a *NetworkAdapterImpl) Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error) {
+	if err := a.ensureImage(ctx, a.captureImage); err != nil {
+		return nil, err
+	}
+	// -U flushes each packet so the download keeps pace with the capture.
+	cmd := []string{"tcpdump", "-i", opts.Interface, "-U", "-w", "-", "-s", strconv.Itoa(opts.SnapLen)}
+	if opts.MaxPackets > 0 {
+		cmd = append(cmd, "-c", strconv.Itoa(opts.MaxPackets))
+	}
+	if opts.Filter != "" {
+		cmd = append(cmd, opts.Filter)
+	}
+	resp, err := a.client.ContainerCreate(ctx,
+		&container.Config{
+			Image:        a.captureImage,
+			Cmd:          cmd,
+			Entrypoint:   []string{},
+			AttachStdout: true,
+			AttachStderr: true,
+			Labels:       map[string]string{helperLabel: opts.ContainerID},
+		},
+		&container.HostConfig{
+			NetworkMode: container.NetworkMode("container:" + opts.ContainerID),
+			CapAdd:      strslice.StrSlice{"NET_RAW", "NET_ADMIN"},
+		},
+		nil, nil, "",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture helper for %s: %w", opts.ContainerID, err)
+	}
+	// Attach before starting so no output is lost.
+	attached, err := a.client.ContainerAttach(ctx, resp.ID, container.AttachOptions{Stream: true, Stdout: true, Stderr: true})
+	if err != nil {
+		a.removeHelper(resp.ID)
+		return nil, fmt.Errorf("failed to attach to capture helper for %s: %w", opts.ContainerID, err)
+	}
+	if err := a.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		attached.Close()
+		a.removeHelper(resp.ID)
+		return nil, fmt.Errorf("failed to start capture helper for %s: %w", opts.ContainerID, err)
+	}
+
+	// SIGINT makes tcpdump flush and exit cleanly, ending the pcap on a
+	// packet boundary.
+	timer := time.AfterFunc(opts.Duration, func() {
+		_ = a.client.ContainerKill(context.Background(), resp.ID, "INT")
+	})
+	pr, pw := io.Pipe()
+	go func() {
+		var stderr bytes.Buffer
+		_, err := stdcopy.StdCopy(pw, &stderr, attached.Reader)
+		if err == nil {
+			err = a.captureExit(resp.ID, stderr.String())
+		}
+		pw.CloseWithError(err)
+	}()
+	return &captureStream{PipeReader: pr, close: func() {
+		timer.Stop()
+		attached.Close()
+		a.removeHelper(resp.ID)
+	}}, nil
+}
+
+// captureExit reports a non-zero tcpdump exit, such as a filter that does
+// not compile, with the last line tcpdump printed.
+func (a *NetworkAdapterImpl) captureExit(id, stderr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	waitC, errC := a.client.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case status := <-waitC:
+		if status.StatusCode == 0 {
+			return nil
+		}
+		lines := strings.Split(strings.TrimSpace(stderr), "\n")
+		return fmt.Errorf("tcpdump exited with status %d: %s", status.StatusCode, lines[len(lines)-1])
+	case err := <-errC:
+		return fmt.Errorf("failed to wait for capture helper: %w", err)
+	}
+}
+
+type captureStream struct {
+	*io.PipeReader
+	close func()
+}
+
+func (s *captureStream) Close() error {
+	s.close()
+	return s.PipeReader.Close()
+}
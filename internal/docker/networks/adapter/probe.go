@@ -0,0 +1,136 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/stdcopy"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// DefaultHelperImage is the image of the probe containers run in another
+// container's network namespace. It needs nslookup, ping and nc.
+const DefaultHelperImage = "busybox:1.36"
+
+const helperLabel = "io.orcahub.network-helper"
+
+// UseHelperImage overrides DefaultHelperImage, e.g. to use a mirror on hosts
+// without access to Docker Hub.
+func (a *NetworkAdapterImpl) UseHelperImage(image string) {
+	a.helperImage = image
+}
+
+func (a *NetworkAdapterImpl) InspectContainer(ctx context.Context, id string) (*model.ContainerNetworks, error) {
+	info, err := a.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", id, err)
+	}
+	cn := toContainerNetworks(info)
+	return &cn, nil
+}
+
+// Probe runs cmds one after the other in a helper container sharing the
+// network namespace, and so the DNS configuration, of container id. The
+// source image needs no tools of its own.
+func (a *NetworkAdapterImpl) Probe(ctx context.Context, id string, cmds [][]string) ([]model.ProbeResult, error) {
+	return a.probe(ctx, id, container.NetworkMode("container:"+id), cmds)
+}
+
+// ProbeNetwork runs cmds in a helper container attached to networkID, so
+// they see the network as any container on it would, embedded DNS included.
+func (a *NetworkAdapterImpl) ProbeNetwork(ctx context.Context, networkID string, cmds [][]string) ([]model.ProbeResult, error) {
+	return a.probe(ctx, networkID, container.NetworkMode(networkID), cmds)
+}
+
+func (a *NetworkAdapterImpl) probe(ctx context.Context, target string, mode container.NetworkMode, cmds [][]string) ([]model.ProbeResult, error) {
+	if err := a.ensureImage(ctx, a.helperImage); err != nil {
+		return nil, err
+	}
+	resp, err := a.client.ContainerCreate(ctx,
+		&container.Config{
+			Image:      a.helperImage,
+			Cmd:        []string{"sleep", "300"},
+			Entrypoint: []string{},
+			Labels:     map[string]string{helperLabel: target},
+		},
+		&container.HostConfig{NetworkMode: mode},
+		nil, nil, "",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network helper for %s: %w", target, err)
+	}
+	defer a.removeHelper(resp.ID)
+	if err := a.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to start network helper for %s: %w", target, err)
+	}
+
+	results := make([]model.ProbeResult, 0, len(cmds))
+	for _, cmd := range cmds {
+		result, err := a.exec(ctx, resp.ID, cmd)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (a *NetworkAdapterImpl) exec(ctx context.Context, id string, cmd []string) (model.ProbeResult, error) {
+	start := time.Now()
+	created, err := a.client.ContainerExecCreate(ctx, id, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return model.ProbeResult{}, fmt.Errorf("failed to exec %s: %w", cmd[0], err)
+	}
+	attached, err := a.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return model.ProbeResult{}, fmt.Errorf("failed to attach to %s: %w", cmd[0], err)
+	}
+	defer attached.Close()
+
+	var out bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &out, attached.Reader); err != nil {
+		return model.ProbeResult{}, fmt.Errorf("failed to read %s output: %w", cmd[0], err)
+	}
+	inspect, err := a.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return model.ProbeResult{}, fmt.Errorf("failed to inspect %s: %w", cmd[0], err)
+	}
+	return model.ProbeResult{
+		ExitCode: inspect.ExitCode,
+		Output:   out.String(),
+		Duration: time.Since(start),
+	}, nil
+}
+
+func (a *NetworkAdapterImpl) ensureImage(ctx context.Context, ref string) error {
+	if _, err := a.client.ImageInspect(ctx, ref); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to inspect helper image %s: %w", ref, err)
+	}
+	reader, err := a.client.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull helper image %s: %w", ref, err)
+	}
+	defer reader.Close()
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// removeHelper runs detached from the request context so helpers are cleaned
+// up even when the client disconnects.
+func (a *NetworkAdapterImpl) removeHelper(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_ = a.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true})
+}
@@ -0,0 +1,188 @@
+package adapter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// DefaultProcRoot is where ProcRoutes reads routes from when OrcaHub runs
+// on the Docker host itself.
+const DefaultProcRoot = "/proc"
+
+// RouteReader lists the host's routes, for spotting Docker subnets that
+// clash with VPNs or LAN routes.
+type RouteReader interface {
+	HostRoutes() ([]model.HostRoute, error)
+}
+
+// errContainerRoutes is returned when the procfs shows the network namespace
+// of OrcaHub's own container rather than the host's.
+var errContainerRoutes = errors.New("procfs shows the container's own network namespace, not the host's; mount the host /proc and set its PID 1 as the proc root")
+
+// ProcRoutes reads the routing tables from a procfs. When OrcaHub runs in a
+// container, point it at the host's, e.g. "/host/proc/1" with the host
+// /proc mounted at /host/proc.
+type ProcRoutes struct {
+	root string
+	// selfNetNS is OrcaHub's own network namespace link and containerFiles
+	// the files whose presence means OrcaHub runs in a container.
+	selfNetNS      string
+	containerFiles []string
+}
+
+func NewProcRoutes(root string) *ProcRoutes {
+	return &ProcRoutes{
+		root:           root,
+		selfNetNS:      "/proc/self/ns/net",
+		containerFiles: []string{"/.dockerenv", "/run/.containerenv"},
+	}
+}
+
+var _ RouteReader = (*ProcRoutes)(nil)
+
+// HostRoutes returns the IPv4 routes and, where IPv6 is enabled, the IPv6
+// routes. Default, loopback, link-local and multicast routes are left out.
+func (p *ProcRoutes) HostRoutes() ([]model.HostRoute, error) {
+	if p.containerNamespace() {
+		return nil, errContainerRoutes
+	}
+	routes, err := p.readFile("route", parseIPv4Route)
+	if err != nil {
+		return nil, err
+	}
+	v6, err := p.readFile("ipv6_route", parseIPv6Route)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return append(routes, v6...), nil
+}
+
+// containerNamespace reports whether OrcaHub runs in a container and the
+// procfs shows its own network namespace. The namespace of a process
+// directory such as /proc/1 is under ns/, that of a procfs root under
+// self/ns/. Namespaces that cannot be compared count as the host's.
+func (p *ProcRoutes) containerNamespace() bool {
+	inContainer := false
+	for _, f := range p.containerFiles {
+		if _, err := os.Stat(f); err == nil {
+			inContainer = true
+		}
+	}
+	if !inContainer {
+		return false
+	}
+	self, err := os.Readlink(p.selfNetNS)
+	if err != nil {
+		return false
+	}
+	for _, link := range []string{filepath.Join(p.root, "ns", "net"), filepath.Join(p.root, "self", "ns", "net")} {
+		if ns, err := os.Readlink(link); err == nil {
+			return ns == self
+		}
+	}
+	return false
+}
+
+func (p *ProcRoutes) readFile(name string, parse func([]string) (model.HostRoute, bool)) ([]model.HostRoute, error) {
+	f, err := os.Open(filepath.Join(p.root, "net", name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var routes []model.HostRoute
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r, ok := parse(strings.Fields(scanner.Text())); ok {
+			routes = append(routes, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s routes: %w", name, err)
+	}
+	return routes, nil
+}
+
+// parseIPv4Route parses a line of /proc/net/route: Iface Destination
+// Gateway Flags RefCnt Use Metric Mask ..., with addresses as little-endian
+// hex. The header line fails to parse and is skipped.
+func parseIPv4Route(fields []string) (model.HostRoute, bool) {
+	if len(fields) < 8 || fields[0] == "lo" {
+		return model.HostRoute{}, false
+	}
+	dest, ok1 := littleEndianAddr(fields[1])
+	gw, ok2 := littleEndianAddr(fields[2])
+	mask, ok3 := littleEndianAddr(fields[7])
+	if !ok1 || !ok2 || !ok3 {
+		return model.HostRoute{}, false
+	}
+	ones := 0
+	for _, b := range mask.AsSlice() {
+		ones += bits.OnesCount8(b)
+	}
+	prefix := netip.PrefixFrom(dest, ones).Masked()
+	if ones == 0 || prefix.Addr().IsLoopback() || prefix.Addr().IsLinkLocalUnicast() || prefix.Addr().IsMulticast() {
+		return model.HostRoute{}, false
+	}
+	route := model.HostRoute{Destination: prefix.String(), Interface: fields[0]}
+	if !gw.IsUnspecified() {
+		route.Gateway = gw.String()
+	}
+	return route, true
+}
+
+// parseIPv6Route parses a line of /proc/net/ipv6_route: dest dest_len src
+// src_len next_hop metric refcnt use flags iface, in network order hex.
+func parseIPv6Route(fields []string) (model.HostRoute, bool) {
+	if len(fields) < 10 || fields[9] == "lo" {
+		return model.HostRoute{}, false
+	}
+	dest, ok1 := hexAddr16(fields[0])
+	hop, ok2 := hexAddr16(fields[4])
+	ones, err := strconv.ParseUint(fields[1], 16, 8)
+	if !ok1 || !ok2 || err != nil || ones == 0 || ones > 128 {
+		return model.HostRoute{}, false
+	}
+	prefix := netip.PrefixFrom(dest, int(ones)).Masked()
+	addr := prefix.Addr()
+	if addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsMulticast() {
+		return model.HostRoute{}, false
+	}
+	route := model.HostRoute{Destination: prefix.String(), Interface: fields[9]}
+	if !hop.IsUnspecified() {
+		route.Gateway = hop.String()
+	}
+	return route, true
+}
+
+func littleEndianAddr(s string) (netip.Addr, bool) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(v))
+	return netip.AddrFrom4(b), true
+}
+
+func hexAddr16(s string) (netip.Addr, bool) {
+	var b [16]byte
+	if len(s) != 32 {
+		return netip.Addr{}, false
+	}
+	if _, err := hex.Decode(b[:], []byte(s)); err != nil {
+		return netip.Addr{}, false
+	}
+	return netip.AddrFrom16(b), true
+}
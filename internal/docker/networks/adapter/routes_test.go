@@ -0,0 +1,86 @@
+package adapter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procRoute = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0101A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0001A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+tun0	000010AC	0100080A	0003	0	0	0	0000F0FF	0	0	0
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+`
+
+const procIPv6Route = `fd000000000000000000000000000000 08 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001     wg0
+fe800000000000000000000000000000 40 00000000000000000000000000000000 00 00000000000000000000000000000000 00000100 00000001 00000000 00000001    eth0
+00000000000000000000000000000001 80 00000000000000000000000000000000 00 00000000000000000000000000000000 00000000 00000002 00000000 80200001       lo
+`
+
+func TestProcRoutes_HostRoutes(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "net", "route"), []byte(procRoute), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "net", "ipv6_route"), []byte(procIPv6Route), 0o644))
+
+	routes, err := NewProcRoutes(root).HostRoutes()
+	require.NoError(t, err)
+	assert.Equal(t, []model.HostRoute{
+		{Destination: "192.168.1.0/24", Interface: "eth0"},
+		{Destination: "172.16.0.0/12", Gateway: "10.8.0.1", Interface: "tun0"},
+		{Destination: "172.17.0.0/16", Interface: "docker0"},
+		{Destination: "fd00::/8", Interface: "wg0"},
+	}, routes)
+}
+
+func TestProcRoutes_NoIPv6(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "net", "route"), []byte(procRoute), 0o644))
+
+	routes, err := NewProcRoutes(root).HostRoutes()
+	require.NoError(t, err)
+	assert.Len(t, routes, 3)
+
+	_, err = NewProcRoutes(t.TempDir()).HostRoutes()
+	assert.Error(t, err)
+}
+
+func TestProcRoutes_ContainerNamespace(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, ".dockerenv")
+	require.NoError(t, os.WriteFile(marker, nil, 0o644))
+	self := filepath.Join(dir, "self-net")
+	require.NoError(t, os.Symlink("net:[4026531840]", self))
+
+	procRoot := func(ns string) string {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, "net", "route"), []byte(procRoute), 0o644))
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "ns"), 0o755))
+		require.NoError(t, os.Symlink(ns, filepath.Join(root, "ns", "net")))
+		return root
+	}
+	reader := func(root string, containerFiles ...string) *ProcRoutes {
+		r := NewProcRoutes(root)
+		r.selfNetNS, r.containerFiles = self, containerFiles
+		return r
+	}
+
+	_, err := reader(procRoot("net:[4026531840]"), marker).HostRoutes()
+	assert.ErrorIs(t, err, errContainerRoutes)
+
+	routes, err := reader(procRoot("net:[4026532008]"), marker).HostRoutes()
+	require.NoError(t, err)
+	assert.Len(t, routes, 3)
+
+	// Outside a container the own namespace is the host's.
+	routes, err = reader(procRoot("net:[4026531840]")).HostRoutes()
+	require.NoError(t, err)
+	assert.Len(t, routes, 3)
+}
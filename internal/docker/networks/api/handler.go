@@ -1,8 +1,14 @@
 package api
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	mappers "github.com/rivernova/orcahub/internal/docker/networks/api/mappers"
 	requests "github.com/rivernova/orcahub/internal/docker/networks/api/requests"
@@ -48,19 +54,27 @@ func (h *Handler) Create(c *gin.Context) {
 		Driver:     req.Driver,
 		Internal:   req.Internal,
 		Attachable: req.Attachable,
+		EnableIPv6: req.EnableIPv6,
+		ConfigFrom: req.ConfigFrom,
+		ConfigOnly: req.ConfigOnly,
 		Labels:     req.Labels,
 		Options:    req.Options,
 	}
 	if req.IPAM != nil {
 		pools := make([]model.IPAMPool, 0, len(req.IPAM.Config))
 		for _, p := range req.IPAM.Config {
-			pools = append(pools, model.IPAMPool{Subnet: p.Subnet, Gateway: p.Gateway})
+			pools = append(pools, model.IPAMPool{
+				Subnet:       p.Subnet,
+				Gateway:      p.Gateway,
+				IPRange:      p.IPRange,
+				AuxAddresses: p.AuxAddresses,
+			})
 		}
-		opts.IPAM = &model.IPAM{Driver: req.IPAM.Driver, Config: pools}
+		opts.IPAM = &model.IPAM{Driver: req.IPAM.Driver, Options: req.IPAM.Options, Config: pools}
 	}
 	result, err := h.service.Create(c.Request.Context(), opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(createErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, mappers.ToNetworkResponse(*result))
@@ -82,15 +96,20 @@ func (h *Handler) Connect(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.service.Connect(c.Request.Context(), id, model.ConnectOptions{
+	endpoint, err := h.service.Connect(c.Request.Context(), id, model.ConnectOptions{
 		ContainerID: req.ContainerID,
 		IPv4Address: req.IPv4Address,
+		IPv6Address: req.IPv6Address,
 		Aliases:     req.Aliases,
-	}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		Links:       req.Links,
+		MacAddress:  req.MacAddress,
+		DriverOpts:  req.DriverOpts,
+	})
+	if err != nil {
+		c.JSON(connectErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "container connected to network"})
+	c.JSON(http.StatusOK, mappers.ToEndpointResponse(endpoint))
 }
 
 func (h *Handler) Disconnect(c *gin.Context) {
@@ -121,3 +140,150 @@ func (h *Handler) Prune(c *gin.Context) {
 		"space_reclaimed": result.SpaceReclaimed,
 	})
 }
+
+func (h *Handler) Topology(c *gin.Context) {
+	topology, err := h.service.Topology(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToTopologyResponse(topology))
+}
+
+func (h *Handler) IPAM(c *gin.Context) {
+	report, err := h.service.IPAMReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToIPAMReportResponse(report))
+}
+
+func (h *Handler) Lookup(c *gin.Context) {
+	id := c.Param("id")
+	var req requests.LookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Lookup(c.Request.Context(), id, model.LookupOptions{Name: req.Name, Type: req.Type})
+	if err != nil {
+		c.JSON(lookupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToLookupResponse(result))
+}
+
+func (h *Handler) Diagnose(c *gin.Context) {
+	var req requests.DiagnoseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	diagnosis, err := h.service.Diagnose(c.Request.Context(), model.DiagnoseOptions{
+		Source: req.Source,
+		Target: req.Target,
+		Host:   req.Host,
+		Port:   req.Port,
+	})
+	if err != nil {
+		c.JSON(lookupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToDiagnosisResponse(diagnosis))
+}
+
+// Capture streams a pcap for download. The first bytes are read before the
+// response starts so that a capture tcpdump refuses, e.g. for a filter that
+// does not compile, still gets a JSON error.
+func (h *Handler) Capture(c *gin.Context) {
+	var req requests.CaptureRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reader, err := h.service.Capture(c.Request.Context(), model.CaptureOptions{
+		ContainerID: req.Container,
+		Interface:   req.Interface,
+		Filter:      req.Filter,
+		Duration:    time.Duration(req.Duration) * time.Second,
+		MaxPackets:  req.MaxPackets,
+		SnapLen:     req.SnapLen,
+	})
+	if err != nil {
+		c.JSON(captureErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	pcap := bufio.NewReader(reader)
+	if _, err := pcap.Peek(pcapHeaderSize); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "application/vnd.tcpdump.pcap", pcap, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, captureName(req.Container, time.Now())),
+	})
+}
+
+// pcapHeaderSize is the length of the pcap global header tcpdump writes as
+// soon as it has opened the interface.
+const pcapHeaderSize = 24
+
+func captureName(container string, at time.Time) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, container)
+	return fmt.Sprintf("%s-%s.pcap", safe, at.UTC().Format("20060102T150405Z"))
+}
+
+func lookupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidNetwork):
+		return http.StatusBadRequest
+	case cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func captureErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCapture):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrContainerNotRunning):
+		return http.StatusConflict
+	case cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func createErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidNetwork):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrSubnetOverlap), errors.Is(err, domain.ErrParentInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func connectErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidNetwork), cerrdefs.IsInvalidArgument(err):
+		return http.StatusBadRequest
+	case cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	case cerrdefs.IsConflict(err):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
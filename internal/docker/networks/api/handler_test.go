@@ -5,12 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	networkapi "github.com/rivernova/orcahub/internal/docker/networks/api"
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
 	"github.com/rivernova/orcahub/internal/docker/networks/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -41,8 +47,12 @@ func (m *mockNetworkService) Create(ctx context.Context, opts model.CreateNetwor
 func (m *mockNetworkService) Delete(ctx context.Context, id string) error {
 	return m.Called(ctx, id).Error(0)
 }
-func (m *mockNetworkService) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error {
-	return m.Called(ctx, networkID, opts).Error(0)
+func (m *mockNetworkService) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error) {
+	args := m.Called(ctx, networkID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EndpointInfo), args.Error(1)
 }
 func (m *mockNetworkService) Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error {
 	return m.Called(ctx, networkID, opts).Error(0)
@@ -53,16 +63,61 @@ func (m *mockNetworkService) Prune(ctx context.Context) (model.PruneResult, erro
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
 
+func (m *mockNetworkService) Topology(ctx context.Context) (*model.Topology, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Topology), args.Error(1)
+}
+
+func (m *mockNetworkService) IPAMReport(ctx context.Context) (*model.IPAMReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.IPAMReport), args.Error(1)
+}
+
+func (m *mockNetworkService) Diagnose(ctx context.Context, opts model.DiagnoseOptions) (*model.Diagnosis, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Diagnosis), args.Error(1)
+}
+
+func (m *mockNetworkService) Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockNetworkService) Lookup(ctx context.Context, networkID string, opts model.LookupOptions) (*model.LookupResult, error) {
+	args := m.Called(ctx, networkID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LookupResult), args.Error(1)
+}
+
 func setupNetworkRouter(svc *mockNetworkService) *gin.Engine {
 	r := gin.New()
 	h := networkapi.NewHandler(svc)
 	r.GET("/networks", h.List)
+	r.GET("/networks/topology", h.Topology)
+	r.GET("/networks/ipam", h.IPAM)
 	r.GET("/networks/:id", h.Inspect)
 	r.POST("/networks", h.Create)
 	r.DELETE("/networks/:id", h.Delete)
 	r.POST("/networks/:id/connect", h.Connect)
 	r.POST("/networks/:id/disconnect", h.Disconnect)
+	r.POST("/networks/:id/lookup", h.Lookup)
 	r.POST("/networks/prune", h.Prune)
+	r.POST("/networks/diagnose", h.Diagnose)
+	r.GET("/networks/capture", h.Capture)
 	return r
 }
 
@@ -144,6 +199,62 @@ func TestNetworkHandler_Create_OK(t *testing.T) {
 	assert.Equal(t, "newnet", resp["id"])
 }
 
+func TestNetworkHandler_Create_IPAM(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Create", mock.Anything, model.CreateNetworkOptions{
+		Name:       "lan",
+		Driver:     "macvlan",
+		EnableIPv6: true,
+		Options:    map[string]string{"parent": "eth0.10"},
+		IPAM: &model.IPAM{
+			Driver:  "default",
+			Options: map[string]string{"opt": "1"},
+			Config: []model.IPAMPool{{
+				Subnet:       "192.168.10.0/24",
+				Gateway:      "192.168.10.1",
+				IPRange:      "192.168.10.128/25",
+				AuxAddresses: map[string]string{"router": "192.168.10.2"},
+			}},
+		},
+	}).Return(&model.Network{ID: "lan1", Name: "lan", Driver: "macvlan"}, nil)
+
+	body := `{"name":"lan","driver":"macvlan","enable_ipv6":true,"options":{"parent":"eth0.10"},
+		"ipam":{"driver":"default","options":{"opt":"1"},"config":[{"subnet":"192.168.10.0/24","gateway":"192.168.10.1",
+		"ip_range":"192.168.10.128/25","aux_addresses":{"router":"192.168.10.2"}}]}}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/networks", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestNetworkHandler_Create_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{domain.ErrInvalidNetwork, http.StatusBadRequest},
+		{domain.ErrSubnetOverlap, http.StatusConflict},
+		{domain.ErrParentInUse, http.StatusConflict},
+		{errors.New("daemon error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		svc := &mockNetworkService{}
+		r := setupNetworkRouter(svc)
+		svc.On("Create", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/networks", bytes.NewBufferString(`{"name":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, tt.err.Error())
+	}
+}
+
 func TestNetworkHandler_Delete_OK(t *testing.T) {
 	svc := &mockNetworkService{}
 	r := setupNetworkRouter(svc)
@@ -160,7 +271,9 @@ func TestNetworkHandler_Connect_OK(t *testing.T) {
 	svc := &mockNetworkService{}
 	r := setupNetworkRouter(svc)
 
-	svc.On("Connect", mock.Anything, "net1", mock.AnythingOfType("model.ConnectOptions")).Return(nil)
+	svc.On("Connect", mock.Anything, "net1", mock.AnythingOfType("model.ConnectOptions")).Return(&model.EndpointInfo{
+		NetworkID: "net1", NetworkName: "app", IPv4Address: "172.18.0.5",
+	}, nil)
 
 	body, _ := json.Marshal(map[string]string{"container_id": "abc123"})
 	w := httptest.NewRecorder()
@@ -171,6 +284,60 @@ func TestNetworkHandler_Connect_OK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 }
 
+func TestNetworkHandler_Connect_FullEndpoint(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Connect", mock.Anything, "net1", model.ConnectOptions{
+		ContainerID: "abc123",
+		IPv4Address: "172.18.0.5",
+		IPv6Address: "fd00::5",
+		Aliases:     []string{"api"},
+		Links:       []string{"db:database"},
+		MacAddress:  "02:42:ac:12:00:05",
+		DriverOpts:  map[string]string{"com.example.opt": "1"},
+	}).Return(&model.EndpointInfo{
+		NetworkID: "net1", NetworkName: "app", EndpointID: "ep1",
+		IPv4Address: "172.18.0.5", IPv6Address: "fd00::5", Gateway: "172.18.0.1",
+		MacAddress: "02:42:ac:12:00:05", Aliases: []string{"api"}, Links: []string{"db:database"},
+	}, nil)
+
+	body := `{"container_id":"abc123","ipv4_address":"172.18.0.5","ipv6_address":"fd00::5","aliases":["api"],
+		"links":["db:database"],"mac_address":"02:42:ac:12:00:05","driver_opts":{"com.example.opt":"1"}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/net1/connect", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "ep1", resp["endpoint_id"])
+	assert.Equal(t, "fd00::5", resp["ipv6_address"])
+	assert.Equal(t, []interface{}{"db:database"}, resp["links"])
+}
+
+func TestNetworkHandler_Connect_Errors(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want int
+	}{
+		"invalid":   {fmt.Errorf("%w: bad", domain.ErrInvalidNetwork), http.StatusBadRequest},
+		"not found": {cerrdefs.ErrNotFound, http.StatusNotFound},
+		"conflict":  {cerrdefs.ErrConflict, http.StatusConflict},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc := &mockNetworkService{}
+			r := setupNetworkRouter(svc)
+			svc.On("Connect", mock.Anything, "net1", mock.Anything).Return(nil, tc.err)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/net1/connect", strings.NewReader(`{"container_id":"abc123"}`)))
+
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
+
 func TestNetworkHandler_Disconnect_OK(t *testing.T) {
 	svc := &mockNetworkService{}
 	r := setupNetworkRouter(svc)
@@ -185,3 +352,240 @@ func TestNetworkHandler_Disconnect_OK(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestNetworkHandler_Topology_OK(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Topology", mock.Anything).Return(&model.Topology{
+		Nodes: []model.TopologyNode{
+			{ID: "host", Type: model.TopologyNodeHost, Name: "host"},
+			{ID: "network:n1", Type: model.TopologyNodeNetwork, Name: "frontend", Driver: "bridge"},
+			{ID: "container:c1", Type: model.TopologyNodeContainer, Name: "web", State: "running"},
+		},
+		Edges: []model.TopologyEdge{
+			{Source: "container:c1", Target: "network:n1", Type: model.TopologyEdgeEndpoint, IPv4Address: "172.20.0.2"},
+			{Source: "host", Target: "container:c1", Type: model.TopologyEdgePort, Ports: []model.PublishedPort{{HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}}},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/topology", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []map[string]interface{} `json:"edges"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp.Nodes, 3)
+	assert.Equal(t, "172.20.0.2", resp.Edges[0]["ipv4_address"])
+	port := resp.Edges[1]["ports"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "8080", port["host_port"])
+	svc.AssertNotCalled(t, "Inspect", mock.Anything, mock.Anything)
+}
+
+func TestNetworkHandler_Topology_Error(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Topology", mock.Anything).Return(nil, errors.New("daemon error"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/topology", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestNetworkHandler_IPAM_OK(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("IPAMReport", mock.Anything).Return(&model.IPAMReport{
+		RoutesChecked: true,
+		Networks: []model.NetworkIPAM{{
+			ID: "n1", Name: "bridge", Driver: "bridge",
+			Subnets: []model.SubnetUsage{{
+				Subnet: "172.17.0.0/16", Size: 65536, Allocatable: 65533, Used: 1, Free: 65532,
+				Assignments: []model.IPAssignment{{Address: "172.17.0.2", Kind: "container", ContainerName: "web"}},
+				Conflicts:   []model.IPAMConflict{{Kind: model.IPAMConflictRoute, With: "tun0", Subnet: "172.16.0.0/12"}},
+			}},
+		}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/ipam", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["routes_checked"])
+	subnet := resp["networks"].([]interface{})[0].(map[string]interface{})["subnets"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(65532), subnet["free"])
+	assert.Equal(t, "tun0", subnet["conflicts"].([]interface{})[0].(map[string]interface{})["with"])
+}
+
+func TestNetworkHandler_Diagnose_OK(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Diagnose", mock.Anything, model.DiagnoseOptions{Source: "api", Target: "web", Port: 80}).Return(&model.Diagnosis{
+		Source: "api", Target: "web", Address: "172.20.0.2", Reachable: true,
+		Steps: []model.DiagnoseStep{{Name: "tcp", Status: model.DiagnoseStepOK, Command: "nc -z -w 3 172.20.0.2 80", Duration: 12 * time.Millisecond}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/diagnose", strings.NewReader(`{"source":"api","target":"web","port":80}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["reachable"])
+	step := resp["steps"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(12), step["duration_ms"])
+}
+
+func TestNetworkHandler_Diagnose_BadPort(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/diagnose", strings.NewReader(`{"source":"api","target":"web","port":70000}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "Diagnose", mock.Anything, mock.Anything)
+}
+
+func TestNetworkHandler_Diagnose_NotFound(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Diagnose", mock.Anything, mock.Anything).Return(nil, cerrdefs.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/diagnose", strings.NewReader(`{"source":"ghost","target":"web"}`)))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNetworkHandler_Diagnose_InvalidHost(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Diagnose", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: invalid host", domain.ErrInvalidNetwork))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/diagnose", strings.NewReader(`{"source":"api","target":"web","host":"-w100"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// pcapHeader is a little-endian pcap global header for Linux cooked capture.
+var pcapHeader = string([]byte{0xd4, 0xc3, 0xb2, 0xa1, 2, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 0, 113, 0, 0, 0})
+
+func TestNetworkHandler_Capture_OK(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Capture", mock.Anything, model.CaptureOptions{
+		ContainerID: "web", Filter: "tcp port 80", Duration: 10 * time.Second, MaxPackets: 100,
+	}).Return(io.NopCloser(strings.NewReader(pcapHeader+"packets")), nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/capture?container=web&filter=tcp+port+80&duration=10&max_packets=100", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/vnd.tcpdump.pcap", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="web-\d{8}T\d{6}Z\.pcap"$`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, pcapHeader+"packets", w.Body.String())
+}
+
+func TestNetworkHandler_Capture_TcpdumpFails(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	pr, pw := io.Pipe()
+	pw.CloseWithError(errors.New("tcpdump exited with status 1: tcpdump: syntax error"))
+	svc.On("Capture", mock.Anything, mock.Anything).Return(pr, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/capture?container=web&filter=tcp+prot+80", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "syntax error")
+}
+
+func TestNetworkHandler_Capture_Errors(t *testing.T) {
+	cases := map[string]struct {
+		query string
+		err   error
+		want  int
+	}{
+		"missing container": {"", nil, http.StatusBadRequest},
+		"duration too long": {"container=web&duration=3600", nil, http.StatusBadRequest},
+		"invalid":           {"container=web", fmt.Errorf("%w: bad", domain.ErrInvalidCapture), http.StatusBadRequest},
+		"not running":       {"container=web", fmt.Errorf("%w: web is exited", domain.ErrContainerNotRunning), http.StatusConflict},
+		"not found":         {"container=web", cerrdefs.ErrNotFound, http.StatusNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc := &mockNetworkService{}
+			r := setupNetworkRouter(svc)
+			if tc.err != nil {
+				svc.On("Capture", mock.Anything, mock.Anything).Return(nil, tc.err)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/networks/capture?"+tc.query, nil))
+
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
+
+func TestNetworkHandler_Lookup_OK(t *testing.T) {
+	svc := &mockNetworkService{}
+	r := setupNetworkRouter(svc)
+
+	svc.On("Lookup", mock.Anything, "net1", model.LookupOptions{Name: "web", Type: "A"}).Return(&model.LookupResult{
+		Network: "app", Name: "web", Server: "127.0.0.11", EmbeddedDNS: true,
+		Answers: []model.LookupAnswer{{Address: "172.20.0.2", ContainerID: "c-web", ContainerName: "web"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/net1/lookup", strings.NewReader(`{"name":"web","type":"A"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["embedded_dns"])
+	answer := resp["answers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "web", answer["container_name"])
+}
+
+func TestNetworkHandler_Lookup_Errors(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		err  error
+		want int
+	}{
+		"missing name": {`{}`, nil, http.StatusBadRequest},
+		"bad type":     {`{"name":"web","type":"MX"}`, nil, http.StatusBadRequest},
+		"invalid":      {`{"name":"web"}`, fmt.Errorf("%w: bad", domain.ErrInvalidNetwork), http.StatusBadRequest},
+		"not found":    {`{"name":"web"}`, cerrdefs.ErrNotFound, http.StatusNotFound},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc := &mockNetworkService{}
+			r := setupNetworkRouter(svc)
+			if tc.err != nil {
+				svc.On("Lookup", mock.Anything, "net1", mock.Anything).Return(nil, tc.err)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/networks/net1/lookup", strings.NewReader(tc.body)))
+
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
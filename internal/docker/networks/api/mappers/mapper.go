@@ -29,7 +29,12 @@ func ToNetworkResponse(n model.Network) responses.NetworkResponse {
 func ToNetworkInspectResponse(n *model.Network) *responses.NetworkInspectResponse {
 	pools := make([]responses.IPAMPoolInfo, 0, len(n.IPAM.Config))
 	for _, p := range n.IPAM.Config {
-		pools = append(pools, responses.IPAMPoolInfo{Subnet: p.Subnet, Gateway: p.Gateway})
+		pools = append(pools, responses.IPAMPoolInfo{
+			Subnet:       p.Subnet,
+			Gateway:      p.Gateway,
+			IPRange:      p.IPRange,
+			AuxAddresses: p.AuxAddresses,
+		})
 	}
 
 	endpoints := make(map[string]responses.ContainerEndpoint, len(n.Containers))
@@ -39,16 +44,171 @@ func ToNetworkInspectResponse(n *model.Network) *responses.NetworkInspectRespons
 			EndpointID:  v.EndpointID,
 			MacAddress:  v.MacAddress,
 			IPv4Address: v.IPv4Address,
+			IPv6Address: v.IPv6Address,
 		}
 	}
 
 	return &responses.NetworkInspectResponse{
 		NetworkResponse: ToNetworkResponse(*n),
+		EnableIPv6:      n.EnableIPv6,
+		ConfigFrom:      n.ConfigFrom,
+		ConfigOnly:      n.ConfigOnly,
 		IPAM: responses.IPAMResponse{
-			Driver: n.IPAM.Driver,
-			Config: pools,
+			Driver:  n.IPAM.Driver,
+			Options: n.IPAM.Options,
+			Config:  pools,
 		},
 		Containers: endpoints,
 		Options:    n.Options,
 	}
 }
+
+func ToTopologyResponse(t *model.Topology) responses.TopologyResponse {
+	nodes := make([]responses.TopologyNodeResponse, 0, len(t.Nodes))
+	for _, n := range t.Nodes {
+		nodes = append(nodes, responses.TopologyNodeResponse{
+			ID:       n.ID,
+			Type:     n.Type,
+			Name:     n.Name,
+			Driver:   n.Driver,
+			Internal: n.Internal,
+			Subnets:  n.Subnets,
+			State:    n.State,
+		})
+	}
+
+	edges := make([]responses.TopologyEdgeResponse, 0, len(t.Edges))
+	for _, e := range t.Edges {
+		var ports []responses.PublishedPortResponse
+		for _, p := range e.Ports {
+			ports = append(ports, responses.PublishedPortResponse{
+				HostIP:        p.HostIP,
+				HostPort:      p.HostPort,
+				ContainerPort: p.ContainerPort,
+				Protocol:      p.Protocol,
+			})
+		}
+		edges = append(edges, responses.TopologyEdgeResponse{
+			Source:      e.Source,
+			Target:      e.Target,
+			Type:        e.Type,
+			IPv4Address: e.IPv4Address,
+			IPv6Address: e.IPv6Address,
+			MacAddress:  e.MacAddress,
+			Aliases:     e.Aliases,
+			Ports:       ports,
+		})
+	}
+	return responses.TopologyResponse{Nodes: nodes, Edges: edges}
+}
+
+func ToIPAMReportResponse(r *model.IPAMReport) responses.IPAMReportResponse {
+	networks := make([]responses.NetworkIPAMResponse, 0, len(r.Networks))
+	for _, n := range r.Networks {
+		subnets := make([]responses.SubnetUsageResponse, 0, len(n.Subnets))
+		for _, s := range n.Subnets {
+			subnets = append(subnets, toSubnetUsageResponse(s))
+		}
+		networks = append(networks, responses.NetworkIPAMResponse{
+			ID:         n.ID,
+			Name:       n.Name,
+			Driver:     n.Driver,
+			ConfigOnly: n.ConfigOnly,
+			Subnets:    subnets,
+		})
+	}
+	return responses.IPAMReportResponse{Networks: networks, RoutesChecked: r.RoutesChecked}
+}
+
+func toSubnetUsageResponse(s model.SubnetUsage) responses.SubnetUsageResponse {
+	assignments := make([]responses.IPAssignmentResponse, 0, len(s.Assignments))
+	for _, a := range s.Assignments {
+		assignments = append(assignments, responses.IPAssignmentResponse{
+			Address:       a.Address,
+			Kind:          a.Kind,
+			ContainerID:   a.ContainerID,
+			ContainerName: a.ContainerName,
+			Label:         a.Label,
+		})
+	}
+	conflicts := make([]responses.IPAMConflictResponse, 0, len(s.Conflicts))
+	for _, c := range s.Conflicts {
+		conflicts = append(conflicts, responses.IPAMConflictResponse{
+			Kind:    c.Kind,
+			With:    c.With,
+			Subnet:  c.Subnet,
+			Gateway: c.Gateway,
+		})
+	}
+	return responses.SubnetUsageResponse{
+		Subnet:      s.Subnet,
+		Gateway:     s.Gateway,
+		IPRange:     s.IPRange,
+		Size:        s.Size,
+		Allocatable: s.Allocatable,
+		Used:        s.Used,
+		Free:        s.Free,
+		Assignments: assignments,
+		Conflicts:   conflicts,
+	}
+}
+
+func ToEndpointResponse(e *model.EndpointInfo) responses.EndpointResponse {
+	aliases := e.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return responses.EndpointResponse{
+		NetworkID:   e.NetworkID,
+		NetworkName: e.NetworkName,
+		EndpointID:  e.EndpointID,
+		IPv4Address: e.IPv4Address,
+		IPv6Address: e.IPv6Address,
+		Gateway:     e.Gateway,
+		IPv6Gateway: e.IPv6Gateway,
+		MacAddress:  e.MacAddress,
+		Aliases:     aliases,
+		Links:       e.Links,
+		DriverOpts:  e.DriverOpts,
+	}
+}
+
+func ToDiagnosisResponse(d *model.Diagnosis) responses.DiagnosisResponse {
+	steps := make([]responses.DiagnoseStepResponse, 0, len(d.Steps))
+	for _, s := range d.Steps {
+		steps = append(steps, responses.DiagnoseStepResponse{
+			Name:       s.Name,
+			Status:     s.Status,
+			Detail:     s.Detail,
+			Command:    s.Command,
+			Output:     s.Output,
+			DurationMs: s.Duration.Milliseconds(),
+		})
+	}
+	return responses.DiagnosisResponse{
+		Source:    d.Source,
+		Target:    d.Target,
+		Address:   d.Address,
+		Reachable: d.Reachable,
+		Steps:     steps,
+	}
+}
+
+func ToLookupResponse(r *model.LookupResult) responses.LookupResponse {
+	answers := make([]responses.LookupAnswerResponse, 0, len(r.Answers))
+	for _, a := range r.Answers {
+		answers = append(answers, responses.LookupAnswerResponse{
+			Address:       a.Address,
+			ContainerID:   a.ContainerID,
+			ContainerName: a.ContainerName,
+		})
+	}
+	return responses.LookupResponse{
+		Network:     r.Network,
+		Name:        r.Name,
+		Server:      r.Server,
+		EmbeddedDNS: r.EmbeddedDNS,
+		Answers:     answers,
+		Output:      r.Output,
+	}
+}
@@ -5,25 +5,56 @@ type CreateNetworkRequest struct {
 	Driver     string            `json:"driver"`   // bridge, host, overlay, none. default "bridge"
 	Internal   bool              `json:"internal"` // aísla la red del exterior
 	Attachable bool              `json:"attachable"`
+	EnableIPv6 bool              `json:"enable_ipv6"`
+	ConfigFrom string            `json:"config_from"` // config-only network to take ipam and options from
+	ConfigOnly bool              `json:"config_only"`
 	Labels     map[string]string `json:"labels"`
-	Options    map[string]string `json:"options"`
+	Options    map[string]string `json:"options"` // e.g. {"parent": "eth0.10"} for macvlan and ipvlan
 	IPAM       *IPAMConfig       `json:"ipam"`
 }
 
 type IPAMConfig struct {
-	Driver string     `json:"driver"`
-	Config []IPAMPool `json:"config"`
+	Driver  string            `json:"driver"`
+	Options map[string]string `json:"options"`
+	Config  []IPAMPool        `json:"config"`
 }
 
 type IPAMPool struct {
-	Subnet  string `json:"subnet"`  // e.g. "172.20.0.0/16"
-	Gateway string `json:"gateway"` // e.g. "172.20.0.1"
+	Subnet       string            `json:"subnet"`        // e.g. "172.20.0.0/16"
+	Gateway      string            `json:"gateway"`       // e.g. "172.20.0.1"
+	IPRange      string            `json:"ip_range"`      // e.g. "172.20.10.0/24"
+	AuxAddresses map[string]string `json:"aux_addresses"` // e.g. {"router": "172.20.0.2"}
 }
 
 type ConnectContainerRequest struct {
-	ContainerID string   `json:"container_id" binding:"required"`
-	IPv4Address string   `json:"ipv4_address"` // opcional, asigna IP estática
-	Aliases     []string `json:"aliases"`      // DNS aliases dentro de la red
+	ContainerID string            `json:"container_id" binding:"required"`
+	IPv4Address string            `json:"ipv4_address"` // opcional, asigna IP estática
+	IPv6Address string            `json:"ipv6_address"` // opcional, requiere red con IPv6
+	Aliases     []string          `json:"aliases"`      // DNS aliases dentro de la red
+	Links       []string          `json:"links"`        // e.g. ["db:database"]
+	MacAddress  string            `json:"mac_address"`
+	DriverOpts  map[string]string `json:"driver_opts"`
+}
+
+type LookupRequest struct {
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type" binding:"omitempty,oneof=A AAAA"` // default both
+}
+
+type DiagnoseRequest struct {
+	Source string `json:"source" binding:"required"` // container name or ID to test from
+	Target string `json:"target" binding:"required"`
+	Host   string `json:"host"` // name to resolve instead of the target's, e.g. an alias
+	Port   int    `json:"port" binding:"omitempty,min=1,max=65535"`
+}
+
+type CaptureRequest struct {
+	Container  string `form:"container" binding:"required"`
+	Interface  string `form:"interface"`                                  // default "any"
+	Filter     string `form:"filter"`                                     // BPF expression, e.g. "tcp port 5432"
+	Duration   int    `form:"duration" binding:"omitempty,min=1,max=600"` // seconds, default 30
+	MaxPackets int    `form:"max_packets" binding:"omitempty,min=1,max=1000000"`
+	SnapLen    int    `form:"snaplen" binding:"omitempty,min=1,max=262144"`
 }
 
 type DisconnectContainerRequest struct {
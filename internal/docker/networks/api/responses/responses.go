@@ -13,19 +13,25 @@ type NetworkResponse struct {
 
 type NetworkInspectResponse struct {
 	NetworkResponse
+	EnableIPv6 bool                         `json:"enable_ipv6"`
+	ConfigFrom string                       `json:"config_from,omitempty"`
+	ConfigOnly bool                         `json:"config_only"`
 	IPAM       IPAMResponse                 `json:"ipam"`
 	Containers map[string]ContainerEndpoint `json:"containers"`
 	Options    map[string]string            `json:"options"`
 }
 
 type IPAMResponse struct {
-	Driver string         `json:"driver"`
-	Config []IPAMPoolInfo `json:"config"`
+	Driver  string            `json:"driver"`
+	Options map[string]string `json:"options,omitempty"`
+	Config  []IPAMPoolInfo    `json:"config"`
 }
 
 type IPAMPoolInfo struct {
-	Subnet  string `json:"subnet"`
-	Gateway string `json:"gateway"`
+	Subnet       string            `json:"subnet"`
+	Gateway      string            `json:"gateway"`
+	IPRange      string            `json:"ip_range,omitempty"`
+	AuxAddresses map[string]string `json:"aux_addresses,omitempty"`
 }
 
 type ContainerEndpoint struct {
@@ -33,4 +39,124 @@ type ContainerEndpoint struct {
 	EndpointID  string `json:"endpoint_id"`
 	MacAddress  string `json:"mac_address"`
 	IPv4Address string `json:"ipv4_address"`
+	IPv6Address string `json:"ipv6_address,omitempty"`
+}
+
+type EndpointResponse struct {
+	NetworkID   string            `json:"network_id"`
+	NetworkName string            `json:"network_name"`
+	EndpointID  string            `json:"endpoint_id"`
+	IPv4Address string            `json:"ipv4_address"`
+	IPv6Address string            `json:"ipv6_address,omitempty"`
+	Gateway     string            `json:"gateway"`
+	IPv6Gateway string            `json:"ipv6_gateway,omitempty"`
+	MacAddress  string            `json:"mac_address"`
+	Aliases     []string          `json:"aliases"`
+	Links       []string          `json:"links,omitempty"`
+	DriverOpts  map[string]string `json:"driver_opts,omitempty"`
+}
+
+type TopologyResponse struct {
+	Nodes []TopologyNodeResponse `json:"nodes"`
+	Edges []TopologyEdgeResponse `json:"edges"`
+}
+
+type TopologyNodeResponse struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"` // network, container or host
+	Name     string   `json:"name"`
+	Driver   string   `json:"driver,omitempty"`
+	Internal bool     `json:"internal,omitempty"`
+	Subnets  []string `json:"subnets,omitempty"`
+	State    string   `json:"state,omitempty"`
+}
+
+type TopologyEdgeResponse struct {
+	Source      string                  `json:"source"`
+	Target      string                  `json:"target"`
+	Type        string                  `json:"type"` // endpoint, port or netns
+	IPv4Address string                  `json:"ipv4_address,omitempty"`
+	IPv6Address string                  `json:"ipv6_address,omitempty"`
+	MacAddress  string                  `json:"mac_address,omitempty"`
+	Aliases     []string                `json:"aliases,omitempty"`
+	Ports       []PublishedPortResponse `json:"ports,omitempty"`
+}
+
+type PublishedPortResponse struct {
+	HostIP        string `json:"host_ip"`
+	HostPort      string `json:"host_port"`
+	ContainerPort string `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+type IPAMReportResponse struct {
+	Networks      []NetworkIPAMResponse `json:"networks"`
+	RoutesChecked bool                  `json:"routes_checked"`
+}
+
+type NetworkIPAMResponse struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Driver     string                `json:"driver"`
+	ConfigOnly bool                  `json:"config_only,omitempty"`
+	Subnets    []SubnetUsageResponse `json:"subnets"`
+}
+
+type SubnetUsageResponse struct {
+	Subnet      string                 `json:"subnet"`
+	Gateway     string                 `json:"gateway,omitempty"`
+	IPRange     string                 `json:"ip_range,omitempty"`
+	Size        uint64                 `json:"size"`
+	Allocatable uint64                 `json:"allocatable"`
+	Used        uint64                 `json:"used"`
+	Free        uint64                 `json:"free"`
+	Assignments []IPAssignmentResponse `json:"assignments"`
+	Conflicts   []IPAMConflictResponse `json:"conflicts"`
+}
+
+type IPAssignmentResponse struct {
+	Address       string `json:"address"`
+	Kind          string `json:"kind"` // container, gateway or aux
+	ContainerID   string `json:"container_id,omitempty"`
+	ContainerName string `json:"container_name,omitempty"`
+	Label         string `json:"label,omitempty"`
+}
+
+type IPAMConflictResponse struct {
+	Kind    string `json:"kind"` // network or route
+	With    string `json:"with"`
+	Subnet  string `json:"subnet"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+type DiagnosisResponse struct {
+	Source    string                 `json:"source"`
+	Target    string                 `json:"target"`
+	Address   string                 `json:"address,omitempty"`
+	Reachable bool                   `json:"reachable"`
+	Steps     []DiagnoseStepResponse `json:"steps"`
+}
+
+type DiagnoseStepResponse struct {
+	Name       string `json:"name"`   // source, target, network, dns, icmp or tcp
+	Status     string `json:"status"` // ok, warning, failed or skipped
+	Detail     string `json:"detail"`
+	Command    string `json:"command,omitempty"`
+	Output     string `json:"output,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+type LookupResponse struct {
+	Network     string                 `json:"network"`
+	Name        string                 `json:"name"`
+	Server      string                 `json:"server"`
+	EmbeddedDNS bool                   `json:"embedded_dns"`
+	Answers     []LookupAnswerResponse `json:"answers"`
+	Output      string                 `json:"output"`
+}
+
+type LookupAnswerResponse struct {
+	Address       string `json:"address"`
+	ContainerID   string `json:"container_id,omitempty"`
+	ContainerName string `json:"container_name,omitempty"`
 }
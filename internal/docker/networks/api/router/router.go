@@ -9,6 +9,11 @@ func Register(rg *gin.RouterGroup, handler *api.Handler) {
 	networks := rg.Group("/networks")
 	{
 		networks.GET("", handler.List)
+		networks.GET("/topology", handler.Topology)
+		networks.GET("/ipam", handler.IPAM)
+		networks.POST("/diagnose", handler.Diagnose)
+		networks.POST("/:id/lookup", handler.Lookup)
+		networks.GET("/capture", handler.Capture)
 		networks.GET("/:id", handler.Inspect)
 		networks.POST("", handler.Create)
 		networks.DELETE("/:id", handler.Delete)
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+var (
+	ErrInvalidCapture      = errors.New("invalid capture options")
+	ErrContainerNotRunning = errors.New("container is not running")
+)
+
+// Capture limits. A capture always ends, so a forgotten download cannot
+// fill the disk of whoever started it.
+const (
+	DefaultCaptureDuration = 30 * time.Second
+	MaxCaptureDuration     = 10 * time.Minute
+	DefaultCapturePackets  = 10000
+	MaxCapturePackets      = 1000000
+	MaxSnapLen             = 262144
+	maxFilterLength        = 1024
+)
+
+// Capture streams a pcap of the traffic in opts.ContainerID's network
+// namespace. Unset options take the defaults above; Interface defaults to
+// "any", which captures all interfaces in the namespace.
+func (s *NetworkServiceImpl) Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error) {
+	opts, err := captureDefaults(opts)
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.adapter.InspectContainer(ctx, opts.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	if c.State != "running" {
+		return nil, fmt.Errorf("%w: %s is %s", ErrContainerNotRunning, c.Name, c.State)
+	}
+	opts.ContainerID = c.ID
+	return s.adapter.Capture(ctx, opts)
+}
+
+func captureDefaults(opts model.CaptureOptions) (model.CaptureOptions, error) {
+	switch {
+	case opts.Duration < 0 || opts.Duration > MaxCaptureDuration:
+		return opts, fmt.Errorf("%w: duration must be at most %s", ErrInvalidCapture, MaxCaptureDuration)
+	case opts.MaxPackets < 0 || opts.MaxPackets > MaxCapturePackets:
+		return opts, fmt.Errorf("%w: packet limit must be at most %d", ErrInvalidCapture, MaxCapturePackets)
+	case opts.SnapLen < 0 || opts.SnapLen > MaxSnapLen:
+		return opts, fmt.Errorf("%w: snap length must be at most %d", ErrInvalidCapture, MaxSnapLen)
+	case opts.Interface != "" && !interfaceName.MatchString(opts.Interface):
+		return opts, fmt.Errorf("%w: invalid interface %q", ErrInvalidCapture, opts.Interface)
+	case len(opts.Filter) > maxFilterLength:
+		return opts, fmt.Errorf("%w: filter longer than %d characters", ErrInvalidCapture, maxFilterLength)
+	case strings.HasPrefix(strings.TrimSpace(opts.Filter), "-"):
+		// tcpdump would read it as an option.
+		return opts, fmt.Errorf("%w: filter cannot start with '-'", ErrInvalidCapture)
+	case strings.ContainsFunc(opts.Filter, func(r rune) bool { return unicode.IsControl(r) && r != '\t' }):
+		return opts, fmt.Errorf("%w: filter contains control characters", ErrInvalidCapture)
+	}
+	if opts.Duration == 0 {
+		opts.Duration = DefaultCaptureDuration
+	}
+	if opts.MaxPackets == 0 {
+		opts.MaxPackets = DefaultCapturePackets
+	}
+	if opts.SnapLen == 0 {
+		opts.SnapLen = MaxSnapLen
+	}
+	if opts.Interface == "" {
+		opts.Interface = "any"
+	}
+	opts.Filter = strings.TrimSpace(opts.Filter)
+	return opts, nil
+}
@@ -0,0 +1,76 @@
+package domain_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkService_Capture_Defaults(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("InspectContainer", ctx, "web").Return(&model.ContainerNetworks{ID: "c-web", Name: "web", State: "running"}, nil)
+	a.On("Capture", ctx, model.CaptureOptions{
+		ContainerID: "c-web",
+		Interface:   "any",
+		Filter:      "tcp port 80",
+		Duration:    domain.DefaultCaptureDuration,
+		MaxPackets:  domain.DefaultCapturePackets,
+		SnapLen:     domain.MaxSnapLen,
+	}).Return(io.NopCloser(strings.NewReader("pcap")), nil)
+
+	reader, err := svc.Capture(ctx, model.CaptureOptions{ContainerID: "web", Filter: " tcp port 80 "})
+	require.NoError(t, err)
+	data, _ := io.ReadAll(reader)
+	assert.Equal(t, "pcap", string(data))
+}
+
+func TestNetworkService_Capture_Invalid(t *testing.T) {
+	cases := map[string]model.CaptureOptions{
+		"too long":        {Duration: time.Hour},
+		"negative":        {Duration: -time.Second},
+		"too many":        {MaxPackets: domain.MaxCapturePackets + 1},
+		"snaplen":         {SnapLen: domain.MaxSnapLen + 1},
+		"interface":       {Interface: "eth0; reboot"},
+		"option filter":   {Filter: "-w /tmp/x"},
+		"control chars":   {Filter: "tcp\nport 80"},
+		"filter too long": {Filter: strings.Repeat("tcp or ", 200)},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+			opts.ContainerID = "web"
+
+			_, err := svc.Capture(context.Background(), opts)
+			assert.ErrorIs(t, err, domain.ErrInvalidCapture)
+			a.AssertNotCalled(t, "InspectContainer", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNetworkService_Capture_NotRunning(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("InspectContainer", ctx, "web").Return(&model.ContainerNetworks{ID: "c-web", Name: "web", State: "exited"}, nil)
+	a.On("InspectContainer", ctx, "ghost").Return(nil, cerrdefs.ErrNotFound)
+
+	_, err := svc.Capture(ctx, model.CaptureOptions{ContainerID: "web"})
+	assert.ErrorIs(t, err, domain.ErrContainerNotRunning)
+	_, err = svc.Capture(ctx, model.CaptureOptions{ContainerID: "ghost"})
+	assert.True(t, cerrdefs.IsNotFound(err))
+	a.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// Connect attaches a container to a network and returns its new endpoint.
+// Static addresses are checked against the network's subnets first, since
+// the daemon only reports a bare "invalid address" for them.
+func (s *NetworkServiceImpl) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error) {
+	if err := validateConnect(opts); err != nil {
+		return nil, err
+	}
+	if opts.IPv4Address != "" || opts.IPv6Address != "" {
+		n, err := s.adapter.Inspect(ctx, networkID)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range []string{opts.IPv4Address, opts.IPv6Address} {
+			if raw == "" {
+				continue
+			}
+			if err := checkStaticAddress(n, netip.MustParseAddr(raw)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.adapter.Connect(ctx, networkID, opts)
+}
+
+func validateConnect(opts model.ConnectOptions) error {
+	if opts.IPv4Address != "" {
+		if addr, err := netip.ParseAddr(opts.IPv4Address); err != nil || !addr.Is4() {
+			return fmt.Errorf("%w: invalid IPv4 address %q", ErrInvalidNetwork, opts.IPv4Address)
+		}
+	}
+	if opts.IPv6Address != "" {
+		if addr, err := netip.ParseAddr(opts.IPv6Address); err != nil || !addr.Is6() || addr.Is4In6() {
+			return fmt.Errorf("%w: invalid IPv6 address %q", ErrInvalidNetwork, opts.IPv6Address)
+		}
+	}
+	if opts.MacAddress != "" {
+		if mac, err := net.ParseMAC(opts.MacAddress); err != nil || len(mac) != 6 {
+			return fmt.Errorf("%w: invalid MAC address %q", ErrInvalidNetwork, opts.MacAddress)
+		}
+	}
+	for _, alias := range opts.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("%w: empty alias", ErrInvalidNetwork)
+		}
+	}
+	for _, link := range opts.Links {
+		name, alias, hasAlias := strings.Cut(link, ":")
+		if name == "" || (hasAlias && alias == "") {
+			return fmt.Errorf("%w: invalid link %q, want container or container:alias", ErrInvalidNetwork, link)
+		}
+	}
+	return nil
+}
+
+// checkStaticAddress requires addr to lie in a subnet of its family on n
+// and not to be that subnet's gateway. Addresses outside IPRange are fine:
+// that is how static addresses avoid the dynamic pool.
+func checkStaticAddress(n *model.Network, addr netip.Addr) error {
+	family := "IPv4"
+	if addr.Is6() {
+		family = "IPv6"
+		if !n.EnableIPv6 {
+			return fmt.Errorf("%w: network %s does not have IPv6 enabled", ErrInvalidNetwork, n.Name)
+		}
+	}
+	found := false
+	for _, pool := range n.IPAM.Config {
+		subnet, err := netip.ParsePrefix(pool.Subnet)
+		if err != nil || subnet.Addr().Is4() != addr.Is4() {
+			continue
+		}
+		found = true
+		if !subnet.Masked().Contains(addr) {
+			continue
+		}
+		if gw, err := netip.ParseAddr(pool.Gateway); err == nil && gw == addr {
+			return fmt.Errorf("%w: %s is the gateway of %s", ErrInvalidNetwork, addr, subnet)
+		}
+		return nil
+	}
+	if !found {
+		return fmt.Errorf("%w: network %s has no %s subnet configured; static addresses need one set at create", ErrInvalidNetwork, n.Name, family)
+	}
+	return fmt.Errorf("%w: %s is outside the subnets of network %s", ErrInvalidNetwork, addr, n.Name)
+}
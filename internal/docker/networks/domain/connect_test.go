@@ -0,0 +1,92 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func dualStackNetwork() *model.Network {
+	return &model.Network{
+		ID: "net1", Name: "app", EnableIPv6: true,
+		IPAM: model.IPAM{Config: []model.IPAMPool{
+			{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1", IPRange: "172.18.1.0/24"},
+			{Subnet: "fd00::/64", Gateway: "fd00::1"},
+		}},
+	}
+}
+
+func TestNetworkService_Connect_StaticAddresses(t *testing.T) {
+	cases := map[string]struct {
+		network *model.Network
+		opts    model.ConnectOptions
+		wantErr bool
+	}{
+		"ipv4 outside ip range":  {dualStackNetwork(), model.ConnectOptions{IPv4Address: "172.18.0.10"}, false},
+		"ipv4 and ipv6":          {dualStackNetwork(), model.ConnectOptions{IPv4Address: "172.18.0.10", IPv6Address: "fd00::10"}, false},
+		"ipv4 outside subnet":    {dualStackNetwork(), model.ConnectOptions{IPv4Address: "10.0.0.10"}, true},
+		"gateway":                {dualStackNetwork(), model.ConnectOptions{IPv4Address: "172.18.0.1"}, true},
+		"ipv6 outside subnet":    {dualStackNetwork(), model.ConnectOptions{IPv6Address: "fd01::10"}, true},
+		"ipv6 without ipv6":      {&model.Network{Name: "v4", IPAM: model.IPAM{Config: []model.IPAMPool{{Subnet: "172.18.0.0/16"}}}}, model.ConnectOptions{IPv6Address: "fd00::10"}, true},
+		"no configured subnet":   {&model.Network{Name: "auto"}, model.ConnectOptions{IPv4Address: "172.18.0.10"}, true},
+		"ipv6 given as ipv4":     {dualStackNetwork(), model.ConnectOptions{IPv4Address: "fd00::10"}, true},
+		"ipv4 mapped given ipv6": {dualStackNetwork(), model.ConnectOptions{IPv6Address: "::ffff:172.18.0.10"}, true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+			ctx := context.Background()
+			tc.opts.ContainerID = "abc123"
+
+			a.On("Inspect", ctx, "net1").Return(tc.network, nil).Maybe()
+			a.On("Connect", ctx, "net1", tc.opts).Return(&model.EndpointInfo{NetworkID: "net1"}, nil).Maybe()
+
+			_, err := svc.Connect(ctx, "net1", tc.opts)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidNetwork)
+				a.AssertNotCalled(t, "Connect", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNetworkService_Connect_Validation(t *testing.T) {
+	cases := map[string]model.ConnectOptions{
+		"bad mac":        {MacAddress: "02:42:ac"},
+		"infiniband mac": {MacAddress: "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"},
+		"empty alias":    {Aliases: []string{" "}},
+		"empty link":     {Links: []string{""}},
+		"link no alias":  {Links: []string{"db:"}},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+
+			_, err := svc.Connect(context.Background(), "net1", opts)
+			assert.ErrorIs(t, err, domain.ErrInvalidNetwork)
+			a.AssertNotCalled(t, "Connect", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNetworkService_Connect_NoStaticAddressSkipsInspect(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	opts := model.ConnectOptions{ContainerID: "abc123", Links: []string{"db:database"}, MacAddress: "02:42:ac:12:00:05"}
+	a.On("Connect", ctx, "net1", opts).Return(&model.EndpointInfo{NetworkID: "net1", Links: []string{"db:database"}}, nil)
+
+	endpoint, err := svc.Connect(ctx, "net1", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db:database"}, endpoint.Links)
+	a.AssertNotCalled(t, "Inspect", mock.Anything, mock.Anything)
+}
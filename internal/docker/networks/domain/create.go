@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+var (
+	ErrInvalidNetwork = errors.New("invalid network options")
+	ErrSubnetOverlap  = errors.New("subnet overlaps an existing network")
+	ErrParentInUse    = errors.New("parent interface already in use")
+)
+
+// interfaceName matches a Linux interface name, at most 15 bytes.
+var interfaceName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]{0,14}$`)
+
+// driverModes lists the accepted values of mode options per driver.
+var driverModes = map[string]map[string][]string{
+	"macvlan": {"macvlan_mode": {"bridge", "vepa", "passthru", "private"}},
+	"ipvlan":  {"ipvlan_mode": {"l2", "l3", "l3s"}, "ipvlan_flag": {"bridge", "private", "vepa"}},
+}
+
+// Create validates the options, checks them against existing networks and
+// creates the network. The daemon only reports overlaps within its default
+// address pools, so subnets given explicitly are checked here.
+func (s *NetworkServiceImpl) Create(ctx context.Context, opts model.CreateNetworkOptions) (*model.Network, error) {
+	if err := validateCreate(opts); err != nil {
+		return nil, err
+	}
+	if opts.ConfigOnly || (opts.ConfigFrom == "" && len(subnets(opts.IPAM)) == 0 && opts.Options["parent"] == "") {
+		return s.adapter.Create(ctx, opts)
+	}
+
+	existing, err := s.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	driver, options, ipam := opts.Driver, opts.Options, opts.IPAM
+	if opts.ConfigFrom != "" {
+		i := slices.IndexFunc(existing, func(n model.Network) bool {
+			return n.ConfigOnly && (n.Name == opts.ConfigFrom || n.ID == opts.ConfigFrom)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s is not a config-only network", ErrInvalidNetwork, opts.ConfigFrom)
+		}
+		options, ipam = existing[i].Options, &existing[i].IPAM
+	}
+	if err := checkConflicts(existing, driver, options, ipam); err != nil {
+		return nil, err
+	}
+	return s.adapter.Create(ctx, opts)
+}
+
+func validateCreate(opts model.CreateNetworkOptions) error {
+	if opts.ConfigFrom != "" {
+		switch {
+		case opts.ConfigOnly:
+			return fmt.Errorf("%w: a config-only network cannot use config_from", ErrInvalidNetwork)
+		case opts.IPAM != nil || len(opts.Options) > 0 || opts.EnableIPv6:
+			return fmt.Errorf("%w: ipam, options and ipv6 come from the config_from network", ErrInvalidNetwork)
+		}
+		return nil
+	}
+	if err := validateDriverOptions(opts.Driver, opts.Options); err != nil {
+		return err
+	}
+	if opts.IPAM == nil {
+		return nil
+	}
+
+	var seen []netip.Prefix
+	for _, p := range opts.IPAM.Config {
+		if p.Subnet == "" {
+			if p.Gateway != "" || p.IPRange != "" || len(p.AuxAddresses) > 0 {
+				return fmt.Errorf("%w: gateway, ip_range and aux_addresses need a subnet", ErrInvalidNetwork)
+			}
+			continue
+		}
+		subnet, err := parseSubnet(p.Subnet)
+		if err != nil {
+			return err
+		}
+		if subnet.Addr().Is6() && !opts.EnableIPv6 {
+			return fmt.Errorf("%w: ipv6 subnet %s needs enable_ipv6", ErrInvalidNetwork, subnet)
+		}
+		for _, other := range seen {
+			if other.Overlaps(subnet) {
+				return fmt.Errorf("%w: subnets %s and %s overlap", ErrInvalidNetwork, other, subnet)
+			}
+		}
+		seen = append(seen, subnet)
+
+		if p.Gateway != "" {
+			if err := checkAddress(subnet, "gateway", p.Gateway); err != nil {
+				return err
+			}
+		}
+		if p.IPRange != "" {
+			r, err := parseSubnet(p.IPRange)
+			if err != nil {
+				return err
+			}
+			if r.Bits() < subnet.Bits() || !subnet.Contains(r.Addr()) {
+				return fmt.Errorf("%w: ip_range %s is outside subnet %s", ErrInvalidNetwork, r, subnet)
+			}
+		}
+		for name, addr := range p.AuxAddresses {
+			if err := checkAddress(subnet, "aux address "+name, addr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseSubnet(raw string) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(raw)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w: invalid subnet %q", ErrInvalidNetwork, raw)
+	}
+	if p != p.Masked() {
+		return netip.Prefix{}, fmt.Errorf("%w: %s has host bits set, use %s", ErrInvalidNetwork, p, p.Masked())
+	}
+	return p, nil
+}
+
+func checkAddress(subnet netip.Prefix, what, raw string) error {
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s %q", ErrInvalidNetwork, what, raw)
+	}
+	if !subnet.Contains(addr) {
+		return fmt.Errorf("%w: %s %s is outside subnet %s", ErrInvalidNetwork, what, addr, subnet)
+	}
+	return nil
+}
+
+// validateDriverOptions checks the macvlan and ipvlan options. The parent
+// may carry a VLAN, e.g. "eth0.10", which the driver creates on demand.
+// Whether the interface exists can only be checked by the daemon.
+func validateDriverOptions(driver string, options map[string]string) error {
+	modes, ok := driverModes[driver]
+	if !ok {
+		return nil
+	}
+	if parent := options["parent"]; parent != "" {
+		if !interfaceName.MatchString(parent) {
+			return fmt.Errorf("%w: invalid parent interface %q", ErrInvalidNetwork, parent)
+		}
+		if i := strings.LastIndexByte(parent, '.'); i > 0 {
+			vlan, err := strconv.Atoi(parent[i+1:])
+			if err == nil && (vlan < 1 || vlan > 4094) {
+				return fmt.Errorf("%w: vlan %d of parent %s is outside 1-4094", ErrInvalidNetwork, vlan, parent)
+			}
+		}
+	}
+	for key, allowed := range modes {
+		if v, ok := options[key]; ok && !slices.Contains(allowed, v) {
+			return fmt.Errorf("%w: %s must be one of %s", ErrInvalidNetwork, key, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// checkConflicts compares a new network with the existing ones. Config-only
+// networks are skipped: they reserve nothing until a network uses them.
+func checkConflicts(existing []model.Network, driver string, options map[string]string, ipam *model.IPAM) error {
+	parent := ""
+	if _, ok := driverModes[driver]; ok {
+		parent = options["parent"]
+	}
+	wanted := subnets(ipam)
+
+	for _, n := range existing {
+		if n.ConfigOnly {
+			continue
+		}
+		if _, ok := driverModes[n.Driver]; ok && parent != "" && n.Options["parent"] == parent {
+			return fmt.Errorf("%w: network %s already uses %s", ErrParentInUse, n.Name, parent)
+		}
+		for _, have := range subnets(&n.IPAM) {
+			for _, want := range wanted {
+				if have.Overlaps(want) {
+					return fmt.Errorf("%w: %s overlaps %s of network %s", ErrSubnetOverlap, want, have, n.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// subnets returns the parseable subnets of ipam.
+func subnets(ipam *model.IPAM) []netip.Prefix {
+	if ipam == nil {
+		return nil
+	}
+	var result []netip.Prefix
+	for _, p := range ipam.Config {
+		if prefix, err := netip.ParsePrefix(p.Subnet); err == nil {
+			result = append(result, prefix.Masked())
+		}
+	}
+	return result
+}
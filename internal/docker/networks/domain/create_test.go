@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func pools(ps ...model.IPAMPool) *model.IPAM { return &model.IPAM{Config: ps} }
+
+func existingNetworks() []model.Network {
+	return []model.Network{
+		{ID: "n1", Name: "bridge", Driver: "bridge", IPAM: *pools(model.IPAMPool{Subnet: "172.17.0.0/16"})},
+		{ID: "n2", Name: "lan", Driver: "macvlan", Options: map[string]string{"parent": "eth0"}, IPAM: *pools(model.IPAMPool{Subnet: "192.168.1.0/24"})},
+		{ID: "n3", Name: "lan-config", Driver: "null", ConfigOnly: true, Options: map[string]string{"parent": "eth1"}, IPAM: *pools(model.IPAMPool{Subnet: "10.10.0.0/24"})},
+	}
+}
+
+func TestNetworkService_Create_Rich(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	opts := model.CreateNetworkOptions{
+		Name:       "dual",
+		EnableIPv6: true,
+		IPAM: &model.IPAM{
+			Driver:  "default",
+			Options: map[string]string{"com.example.opt": "1"},
+			Config: []model.IPAMPool{
+				{Subnet: "172.30.0.0/16", Gateway: "172.30.0.1", IPRange: "172.30.5.0/24", AuxAddresses: map[string]string{"router": "172.30.0.2"}},
+				{Subnet: "fd00:dead:beef::/64", Gateway: "fd00:dead:beef::1"},
+			},
+		},
+	}
+	a.On("List", ctx).Return(existingNetworks(), nil)
+	a.On("Create", ctx, opts).Return(&model.Network{ID: "n4", Name: "dual"}, nil)
+
+	n, err := svc.Create(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "n4", n.ID)
+}
+
+func TestNetworkService_Create_Invalid(t *testing.T) {
+	tests := map[string]model.CreateNetworkOptions{
+		"host bits set":        {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "172.30.0.1/16"})},
+		"gateway outside":      {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "172.30.0.0/16", Gateway: "172.31.0.1"})},
+		"range outside":        {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "172.30.0.0/16", IPRange: "172.30.0.0/15"})},
+		"aux outside":          {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "172.30.0.0/16", AuxAddresses: map[string]string{"r": "10.0.0.1"}})},
+		"gateway no subnet":    {Name: "x", IPAM: pools(model.IPAMPool{Gateway: "172.30.0.1"})},
+		"ipv6 not enabled":     {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "fd00::/64"})},
+		"own pools overlap":    {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "10.1.0.0/16"}, model.IPAMPool{Subnet: "10.1.2.0/24"})},
+		"bad parent":           {Name: "x", Driver: "macvlan", Options: map[string]string{"parent": "eth0; reboot"}},
+		"bad vlan":             {Name: "x", Driver: "ipvlan", Options: map[string]string{"parent": "eth0.5000"}},
+		"bad macvlan mode":     {Name: "x", Driver: "macvlan", Options: map[string]string{"parent": "eth0.10", "macvlan_mode": "l2"}},
+		"config from and ipam": {Name: "x", ConfigFrom: "lan-config", IPAM: pools(model.IPAMPool{Subnet: "10.2.0.0/16"})},
+		"config from and only": {Name: "x", ConfigFrom: "lan-config", ConfigOnly: true},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+
+			_, err := svc.Create(context.Background(), opts)
+			assert.ErrorIs(t, err, domain.ErrInvalidNetwork)
+			a.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNetworkService_Create_Conflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts model.CreateNetworkOptions
+		err  error
+	}{
+		{"subnet overlap", model.CreateNetworkOptions{Name: "x", IPAM: pools(model.IPAMPool{Subnet: "172.17.128.0/17"})}, domain.ErrSubnetOverlap},
+		{"parent in use", model.CreateNetworkOptions{Name: "x", Driver: "ipvlan", Options: map[string]string{"parent": "eth0"}}, domain.ErrParentInUse},
+		{"config from unknown", model.CreateNetworkOptions{Name: "x", Driver: "macvlan", ConfigFrom: "bridge"}, domain.ErrInvalidNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+			ctx := context.Background()
+			a.On("List", ctx).Return(existingNetworks(), nil)
+
+			_, err := svc.Create(ctx, tt.opts)
+			assert.ErrorIs(t, err, tt.err)
+			a.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNetworkService_Create_AllowedAlongside(t *testing.T) {
+	tests := map[string]model.CreateNetworkOptions{
+		// A VLAN sub-interface is a different parent.
+		"vlan of used parent": {Name: "x", Driver: "macvlan", Options: map[string]string{"parent": "eth0.10"}},
+		// Config-only networks reserve nothing.
+		"config-only subnet": {Name: "x", IPAM: pools(model.IPAMPool{Subnet: "10.10.0.0/24"})},
+		"from config":        {Name: "x", Driver: "macvlan", ConfigFrom: "lan-config"},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+			ctx := context.Background()
+			a.On("List", ctx).Return(existingNetworks(), nil)
+			a.On("Create", ctx, opts).Return(&model.Network{ID: "new"}, nil)
+
+			_, err := svc.Create(ctx, opts)
+			assert.NoError(t, err)
+		})
+	}
+}
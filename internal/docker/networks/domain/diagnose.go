@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivernova/orcahub/internal/docker/dns"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// Diagnose checks whether opts.Source can reach opts.Target: both must be
+// running and share a network, the target's name must resolve from inside
+// the source unless the host is an IP address, and the target must answer
+// ping and, given a port, accept a TCP connection. Every check runs even
+// after one fails, since the pattern of failures is what points at the cause.
+func (s *NetworkServiceImpl) Diagnose(ctx context.Context, opts model.DiagnoseOptions) (*model.Diagnosis, error) {
+	// The host ends up in the argv of the probes; a leading dash would be
+	// taken for an option.
+	_, err := netip.ParseAddr(opts.Host)
+	literal := opts.Host != "" && err == nil
+	if opts.Host != "" && !literal && !hostName.MatchString(opts.Host) {
+		return nil, fmt.Errorf("%w: invalid host %q", ErrInvalidNetwork, opts.Host)
+	}
+	src, err := s.adapter.InspectContainer(ctx, opts.Source)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := s.adapter.InspectContainer(ctx, opts.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &model.Diagnosis{Source: src.Name, Target: dst.Name, Steps: []model.DiagnoseStep{}}
+	d.Steps = append(d.Steps, containerStep("source", src), containerStep("target", dst))
+
+	step, shared, address := sharedNetworks(src, dst)
+	d.Steps = append(d.Steps, step)
+	d.Address = address
+
+	host := opts.Host
+	if host == "" {
+		host = dst.Name
+	}
+	probeAddr := address
+	if literal || probeAddr == "" {
+		probeAddr = host
+	}
+	// An IP address has nothing to resolve; a reverse lookup would only
+	// fail on addresses without a PTR record.
+	var cmds [][]string
+	if literal {
+		d.Steps = append(d.Steps, model.DiagnoseStep{
+			Name:   probeName("nslookup"),
+			Status: model.DiagnoseStepSkipped,
+			Detail: host + " is an IP address",
+		})
+	} else {
+		cmds = append(cmds, []string{"nslookup", host})
+	}
+	cmds = append(cmds, []string{"ping", "-c", "3", "-W", "2", probeAddr})
+	if opts.Port > 0 {
+		cmds = append(cmds, []string{"nc", "-z", "-w", "3", probeAddr, strconv.Itoa(opts.Port)})
+	}
+
+	if src.State != "running" {
+		// Without a running source there is no namespace to probe from.
+		for _, cmd := range cmds {
+			d.Steps = append(d.Steps, model.DiagnoseStep{
+				Name:    probeName(cmd[0]),
+				Status:  model.DiagnoseStepSkipped,
+				Detail:  "source is not running",
+				Command: strings.Join(cmd, " "),
+			})
+		}
+		return d, nil
+	}
+
+	results, err := s.adapter.Probe(ctx, src.ID, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if !literal {
+		d.Steps = append(d.Steps, dnsStep(cmds[0], results[0], host, dst, shared))
+		cmds, results = cmds[1:], results[1:]
+	}
+	icmp := icmpStep(cmds[0], results[0])
+	d.Steps = append(d.Steps, icmp)
+	d.Reachable = icmp.Status == model.DiagnoseStepOK
+	if opts.Port > 0 {
+		tcp := tcpStep(cmds[1], results[1], opts.Port)
+		d.Steps = append(d.Steps, tcp)
+		// ICMP is often filtered, so an open port is what counts.
+		d.Reachable = tcp.Status == model.DiagnoseStepOK
+	}
+	return d, nil
+}
+
+func containerStep(name string, c *model.ContainerNetworks) model.DiagnoseStep {
+	if c.State == "running" {
+		return model.DiagnoseStep{Name: name, Status: model.DiagnoseStepOK, Detail: c.Name + " is running"}
+	}
+	return model.DiagnoseStep{Name: name, Status: model.DiagnoseStepFailed, Detail: fmt.Sprintf("%s is %s", c.Name, c.State)}
+}
+
+// sharedNetworks reports the networks both containers are attached to and
+// the target's address on the first of them.
+func sharedNetworks(src, dst *model.ContainerNetworks) (model.DiagnoseStep, []string, string) {
+	step := model.DiagnoseStep{Name: "network"}
+	ids := make([]string, 0, len(src.Endpoints))
+	for id := range src.Endpoints {
+		if _, ok := dst.Endpoints[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return src.Endpoints[ids[i]].NetworkName < src.Endpoints[ids[j]].NetworkName })
+
+	var names []string
+	address := ""
+	for _, id := range ids {
+		names = append(names, src.Endpoints[id].NetworkName)
+		if address == "" {
+			address = bareAddr(dst.Endpoints[id].IPv4Address)
+		}
+		if address == "" {
+			address = bareAddr(dst.Endpoints[id].IPv6Address)
+		}
+	}
+
+	switch {
+	case len(ids) > 0:
+		step.Status = model.DiagnoseStepOK
+		step.Detail = "shared networks: " + strings.Join(names, ", ")
+		if address != "" {
+			step.Detail += "; target address " + address
+		}
+	case strings.HasPrefix(src.NetworkMode, "container:"):
+		step.Status = model.DiagnoseStepWarning
+		step.Detail = "source uses the network of " + strings.TrimPrefix(src.NetworkMode, "container:") + "; no network is shared directly"
+	default:
+		step.Status = model.DiagnoseStepFailed
+		step.Detail = "no shared network; connect both containers to the same user-defined network"
+	}
+	return step, names, address
+}
+
+func dnsStep(cmd []string, r model.ProbeResult, host string, dst *model.ContainerNetworks, shared []string) model.DiagnoseStep {
+	step := probeStep(cmd, r)
+	resolved := dns.ParseNslookup(r.Output)
+	if r.ExitCode != 0 || len(resolved) == 0 {
+		step.Status = model.DiagnoseStepFailed
+		step.Detail = "cannot resolve " + host
+		if len(shared) > 0 && !slices.ContainsFunc(shared, func(n string) bool { return n != "bridge" }) {
+			step.Detail += "; the default bridge network has no name resolution, use a user-defined network"
+		}
+		return step
+	}
+
+	step.Status = model.DiagnoseStepOK
+	step.Detail = fmt.Sprintf("%s resolves to %s", host, strings.Join(resolved, ", "))
+	var actual []string
+	for _, ep := range dst.Endpoints {
+		for _, raw := range []string{ep.IPv4Address, ep.IPv6Address} {
+			if addr := bareAddr(raw); addr != "" {
+				actual = append(actual, addr)
+			}
+		}
+	}
+	if len(actual) > 0 && !slices.ContainsFunc(resolved, func(a string) bool { return slices.Contains(actual, a) }) {
+		sort.Strings(actual)
+		step.Status = model.DiagnoseStepWarning
+		step.Detail += fmt.Sprintf(", but %s has %s; another container or host may hold the name", dst.Name, strings.Join(actual, ", "))
+	}
+	return step
+}
+
+func icmpStep(cmd []string, r model.ProbeResult) model.DiagnoseStep {
+	step := probeStep(cmd, r)
+	summary := ""
+	for _, line := range strings.Split(r.Output, "\n") {
+		if strings.Contains(line, "packet loss") {
+			summary = strings.TrimSpace(line)
+		}
+	}
+	if r.ExitCode == 0 {
+		step.Status, step.Detail = model.DiagnoseStepOK, summary
+		return step
+	}
+	step.Status = model.DiagnoseStepFailed
+	step.Detail = "no reply to ping; ICMP may be filtered, the TCP check is more reliable"
+	if summary != "" {
+		step.Detail = summary + "; " + step.Detail
+	}
+	return step
+}
+
+func tcpStep(cmd []string, r model.ProbeResult, port int) model.DiagnoseStep {
+	step := probeStep(cmd, r)
+	if r.ExitCode == 0 {
+		step.Status, step.Detail = model.DiagnoseStepOK, fmt.Sprintf("port %d is open", port)
+		return step
+	}
+	step.Status = model.DiagnoseStepFailed
+	step.Detail = fmt.Sprintf("port %d is closed or filtered; check the service listens on 0.0.0.0, not 127.0.0.1, inside the target", port)
+	return step
+}
+
+func probeStep(cmd []string, r model.ProbeResult) model.DiagnoseStep {
+	return model.DiagnoseStep{
+		Name:     probeName(cmd[0]),
+		Command:  strings.Join(cmd, " "),
+		Output:   strings.TrimSpace(r.Output),
+		Duration: r.Duration,
+	}
+}
+
+func probeName(tool string) string {
+	switch tool {
+	case "nslookup":
+		return "dns"
+	case "ping":
+		return "icmp"
+	default:
+		return "tcp"
+	}
+}
+
+// bareAddr strips the prefix length endpoints report addresses with.
+func bareAddr(raw string) string {
+	if p, err := netip.ParsePrefix(raw); err == nil {
+		return p.Addr().String()
+	}
+	if a, err := netip.ParseAddr(raw); err == nil {
+		return a.String()
+	}
+	return ""
+}
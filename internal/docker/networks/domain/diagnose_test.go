@@ -0,0 +1,202 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const nslookupWeb = `Server:		127.0.0.11
+Address:	127.0.0.11:53
+
+Non-authoritative answer:
+Name:	web
+Address: 172.20.0.2
+`
+
+func diagnoseContainers(a *mockNetworkAdapter, ctx context.Context, network string) {
+	a.On("InspectContainer", ctx, "api").Return(&model.ContainerNetworks{
+		ID: "c-api", Name: "api", State: "running",
+		Endpoints: map[string]model.EndpointInfo{"n1": {NetworkName: network, IPv4Address: "172.20.0.3/16"}},
+	}, nil)
+	a.On("InspectContainer", ctx, "web").Return(&model.ContainerNetworks{
+		ID: "c-web", Name: "web", State: "running",
+		Endpoints: map[string]model.EndpointInfo{"n1": {NetworkName: network, IPv4Address: "172.20.0.2/16"}},
+	}, nil)
+}
+
+func stepByName(t *testing.T, d *model.Diagnosis, name string) model.DiagnoseStep {
+	t.Helper()
+	for _, s := range d.Steps {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no %s step", name)
+	return model.DiagnoseStep{}
+}
+
+func TestNetworkService_Diagnose_Reachable(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+	diagnoseContainers(a, ctx, "app")
+
+	cmds := [][]string{
+		{"nslookup", "web"},
+		{"ping", "-c", "3", "-W", "2", "172.20.0.2"},
+		{"nc", "-z", "-w", "3", "172.20.0.2", "80"},
+	}
+	a.On("Probe", ctx, "c-api", cmds).Return([]model.ProbeResult{
+		{Output: nslookupWeb},
+		{Output: "3 packets transmitted, 3 packets received, 0% packet loss\n"},
+		{},
+	}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "web", Port: 80})
+	require.NoError(t, err)
+	assert.True(t, d.Reachable)
+	assert.Equal(t, "172.20.0.2", d.Address)
+	assert.Len(t, d.Steps, 6)
+	for _, s := range d.Steps {
+		assert.Equal(t, model.DiagnoseStepOK, s.Status, s.Name)
+	}
+	assert.Equal(t, "web resolves to 172.20.0.2", stepByName(t, d, "dns").Detail)
+	assert.Equal(t, "3 packets transmitted, 3 packets received, 0% packet loss", stepByName(t, d, "icmp").Detail)
+}
+
+func TestNetworkService_Diagnose_IPHost(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+	diagnoseContainers(a, ctx, "app")
+
+	cmds := [][]string{
+		{"ping", "-c", "3", "-W", "2", "10.0.0.7"},
+		{"nc", "-z", "-w", "3", "10.0.0.7", "5432"},
+	}
+	a.On("Probe", ctx, "c-api", cmds).Return([]model.ProbeResult{
+		{Output: "3 packets transmitted, 3 packets received, 0% packet loss\n"},
+		{},
+	}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "web", Host: "10.0.0.7", Port: 5432})
+	require.NoError(t, err)
+	assert.True(t, d.Reachable)
+	dns := stepByName(t, d, "dns")
+	assert.Equal(t, model.DiagnoseStepSkipped, dns.Status)
+	assert.Equal(t, "10.0.0.7 is an IP address", dns.Detail)
+	assert.Equal(t, model.DiagnoseStepOK, stepByName(t, d, "tcp").Status)
+	a.AssertExpectations(t)
+}
+
+func TestNetworkService_Diagnose_DefaultBridgeHasNoDNS(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+	diagnoseContainers(a, ctx, "bridge")
+
+	a.On("Probe", ctx, "c-api", mock.Anything).Return([]model.ProbeResult{
+		{ExitCode: 1, Output: "** server can't find web: NXDOMAIN\n"},
+		{ExitCode: 0},
+		{ExitCode: 1},
+	}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "web", Port: 8080})
+	require.NoError(t, err)
+	assert.False(t, d.Reachable)
+	dns := stepByName(t, d, "dns")
+	assert.Equal(t, model.DiagnoseStepFailed, dns.Status)
+	assert.Contains(t, dns.Detail, "default bridge")
+	assert.Equal(t, model.DiagnoseStepFailed, stepByName(t, d, "tcp").Status)
+	assert.Equal(t, "nc -z -w 3 172.20.0.2 8080", stepByName(t, d, "tcp").Command)
+}
+
+func TestNetworkService_Diagnose_ResolvesElsewhere(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+	diagnoseContainers(a, ctx, "app")
+
+	a.On("Probe", ctx, "c-api", mock.Anything).Return([]model.ProbeResult{
+		{Output: "Name:	web\nAddress: 10.0.0.9\n"},
+		{},
+	}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "web"})
+	require.NoError(t, err)
+	assert.True(t, d.Reachable)
+	assert.Equal(t, model.DiagnoseStepWarning, stepByName(t, d, "dns").Status)
+}
+
+func TestNetworkService_Diagnose_NoSharedNetwork(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("InspectContainer", ctx, "api").Return(&model.ContainerNetworks{
+		ID: "c-api", Name: "api", State: "running",
+		Endpoints: map[string]model.EndpointInfo{"n1": {NetworkName: "front"}},
+	}, nil)
+	a.On("InspectContainer", ctx, "db").Return(&model.ContainerNetworks{
+		ID: "c-db", Name: "db", State: "running",
+		Endpoints: map[string]model.EndpointInfo{"n2": {NetworkName: "back", IPv4Address: "172.21.0.2/16"}},
+	}, nil)
+	// With no shared network the probes fall back to the target's name.
+	a.On("Probe", ctx, "c-api", [][]string{
+		{"nslookup", "db"},
+		{"ping", "-c", "3", "-W", "2", "db"},
+	}).Return([]model.ProbeResult{{ExitCode: 1}, {ExitCode: 1}}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "db"})
+	require.NoError(t, err)
+	assert.False(t, d.Reachable)
+	assert.Empty(t, d.Address)
+	assert.Equal(t, model.DiagnoseStepFailed, stepByName(t, d, "network").Status)
+}
+
+func TestNetworkService_Diagnose_SourceStopped(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("InspectContainer", ctx, "api").Return(&model.ContainerNetworks{ID: "c-api", Name: "api", State: "exited"}, nil)
+	a.On("InspectContainer", ctx, "web").Return(&model.ContainerNetworks{ID: "c-web", Name: "web", State: "running"}, nil)
+
+	d, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "api", Target: "web", Port: 80})
+	require.NoError(t, err)
+	assert.False(t, d.Reachable)
+	assert.Equal(t, model.DiagnoseStepFailed, stepByName(t, d, "source").Status)
+	for _, name := range []string{"dns", "icmp", "tcp"} {
+		assert.Equal(t, model.DiagnoseStepSkipped, stepByName(t, d, name).Status)
+	}
+	a.AssertNotCalled(t, "Probe", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNetworkService_Diagnose_NotFound(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("InspectContainer", ctx, "ghost").Return(nil, cerrdefs.ErrNotFound)
+
+	_, err := svc.Diagnose(ctx, model.DiagnoseOptions{Source: "ghost", Target: "web"})
+	assert.True(t, cerrdefs.IsNotFound(err))
+}
+
+func TestNetworkService_Diagnose_InvalidHost(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+
+	for _, host := range []string{"-w100", "web; reboot", "a b"} {
+		_, err := svc.Diagnose(context.Background(), model.DiagnoseOptions{Source: "api", Target: "web", Host: host})
+		assert.ErrorIs(t, err, domain.ErrInvalidNetwork, host)
+	}
+	a.AssertNotCalled(t, "InspectContainer", mock.Anything, mock.Anything)
+}
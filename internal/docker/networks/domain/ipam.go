@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"math"
+	"net/netip"
+	"sort"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/networks/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// bridgeNameOption is the driver option naming a bridge network's interface.
+const bridgeNameOption = "com.docker.network.bridge.name"
+
+// UseHostRoutes makes the IPAM report check subnets against the host's
+// routes, e.g. those of a VPN.
+func (s *NetworkServiceImpl) UseHostRoutes(routes adapter.RouteReader) {
+	s.routes = routes
+}
+
+// IPAMReport details the address usage of every network and flags subnets
+// overlapping other networks or host routes. Config-only networks are
+// listed but never conflict, since they reserve nothing themselves.
+func (s *NetworkServiceImpl) IPAMReport(ctx context.Context) (*model.IPAMReport, error) {
+	networks, err := s.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+
+	report := &model.IPAMReport{Networks: []model.NetworkIPAM{}}
+	var routes []model.HostRoute
+	if s.routes != nil {
+		if routes, err = s.routes.HostRoutes(); err != nil {
+			log.Printf("ipam report: host routes unavailable: %v", err)
+		} else {
+			report.RoutesChecked = true
+		}
+	}
+
+	// Docker adds a route for each bridge subnet through the bridge's own
+	// interface; those are not conflicts. A route for the same subnet via
+	// any other interface, e.g. a VPN's, is one.
+	own := map[string]bool{}
+	for _, n := range networks {
+		if iface := bridgeInterface(n); iface != "" {
+			own[iface] = true
+		}
+	}
+
+	for _, n := range networks {
+		// List leaves out the endpoints, so inspect for the assignments.
+		full, err := s.adapter.Inspect(ctx, n.ID)
+		if cerrdefs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := model.NetworkIPAM{
+			ID:         full.ID,
+			Name:       full.Name,
+			Driver:     full.Driver,
+			ConfigOnly: full.ConfigOnly,
+			Subnets:    []model.SubnetUsage{},
+		}
+		for _, pool := range full.IPAM.Config {
+			usage := subnetUsage(pool, full.Containers)
+			if subnet, err := netip.ParsePrefix(pool.Subnet); err == nil && !full.ConfigOnly {
+				usage.Conflicts = conflicts(subnet.Masked(), full.ID, networks, routes, own)
+			}
+			entry.Subnets = append(entry.Subnets, usage)
+		}
+		report.Networks = append(report.Networks, entry)
+	}
+	return report, nil
+}
+
+// bridgeInterface names the host interface of a bridge network: the name
+// set in its options, docker0 for the default bridge, otherwise
+// br-<first 12 characters of the ID>.
+func bridgeInterface(n model.Network) string {
+	if n.Driver != "bridge" {
+		return ""
+	}
+	if name := n.Options[bridgeNameOption]; name != "" {
+		return name
+	}
+	if n.Name == "bridge" {
+		return "docker0"
+	}
+	id := n.ID
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return "br-" + id
+}
+
+func conflicts(subnet netip.Prefix, networkID string, networks []model.Network, routes []model.HostRoute, own map[string]bool) []model.IPAMConflict {
+	result := []model.IPAMConflict{}
+	for _, other := range networks {
+		if other.ID == networkID || other.ConfigOnly {
+			continue
+		}
+		for _, p := range subnets(&other.IPAM) {
+			if p.Overlaps(subnet) {
+				result = append(result, model.IPAMConflict{
+					Kind:   model.IPAMConflictNetwork,
+					With:   other.Name,
+					Subnet: p.String(),
+				})
+			}
+		}
+	}
+	for _, r := range routes {
+		p, err := netip.ParsePrefix(r.Destination)
+		if err != nil || own[r.Interface] || !p.Overlaps(subnet) {
+			continue
+		}
+		result = append(result, model.IPAMConflict{
+			Kind:    model.IPAMConflictRoute,
+			With:    r.Interface,
+			Subnet:  p.String(),
+			Gateway: r.Gateway,
+		})
+	}
+	return result
+}
+
+func subnetUsage(pool model.IPAMPool, endpoints map[string]model.ContainerEndpoint) model.SubnetUsage {
+	usage := model.SubnetUsage{
+		Subnet:      pool.Subnet,
+		Gateway:     pool.Gateway,
+		IPRange:     pool.IPRange,
+		Assignments: []model.IPAssignment{},
+		Conflicts:   []model.IPAMConflict{},
+	}
+	subnet, err := netip.ParsePrefix(pool.Subnet)
+	if err != nil {
+		return usage
+	}
+	subnet = subnet.Masked()
+	alloc := subnet
+	if r, err := netip.ParsePrefix(pool.IPRange); err == nil && subnet.Contains(r.Addr()) {
+		alloc = r.Masked()
+	}
+
+	reserved := map[netip.Addr]bool{subnet.Addr(): true}
+	if subnet.Addr().Is4() && subnet.Bits() <= 30 {
+		reserved[broadcast(subnet)] = true
+	}
+	if gw, err := netip.ParseAddr(pool.Gateway); err == nil && subnet.Contains(gw) {
+		reserved[gw] = true
+		usage.Assignments = append(usage.Assignments, model.IPAssignment{Address: gw.String(), Kind: "gateway"})
+	}
+	for name, raw := range pool.AuxAddresses {
+		if addr, err := netip.ParseAddr(raw); err == nil && subnet.Contains(addr) {
+			reserved[addr] = true
+			usage.Assignments = append(usage.Assignments, model.IPAssignment{Address: addr.String(), Kind: "aux", Label: name})
+		}
+	}
+
+	var usedInPool uint64
+	for id, ep := range endpoints {
+		for _, raw := range []string{ep.IPv4Address, ep.IPv6Address} {
+			addr, ok := endpointAddr(raw)
+			if !ok || !subnet.Contains(addr) {
+				continue
+			}
+			usage.Used++
+			if alloc.Contains(addr) {
+				usedInPool++
+			}
+			usage.Assignments = append(usage.Assignments, model.IPAssignment{
+				Address:       addr.String(),
+				ContainerID:   id,
+				ContainerName: ep.Name,
+				Kind:          "container",
+			})
+		}
+	}
+	sort.Slice(usage.Assignments, func(i, j int) bool {
+		a, _ := netip.ParseAddr(usage.Assignments[i].Address)
+		b, _ := netip.ParseAddr(usage.Assignments[j].Address)
+		return a.Less(b)
+	})
+
+	usage.Size = prefixSize(subnet)
+	usage.Allocatable = prefixSize(alloc)
+	for addr := range reserved {
+		if alloc.Contains(addr) && usage.Allocatable > 0 {
+			usage.Allocatable--
+		}
+	}
+	if usage.Allocatable > usedInPool {
+		usage.Free = usage.Allocatable - usedInPool
+	}
+	return usage
+}
+
+// endpointAddr parses an endpoint address, which the daemon reports in CIDR
+// form, e.g. "172.20.0.2/16".
+func endpointAddr(raw string) (netip.Addr, bool) {
+	if p, err := netip.ParsePrefix(raw); err == nil {
+		return p.Addr(), true
+	}
+	addr, err := netip.ParseAddr(raw)
+	return addr, err == nil
+}
+
+func prefixSize(p netip.Prefix) uint64 {
+	hostBits := p.Addr().BitLen() - p.Bits()
+	if hostBits >= 64 {
+		return math.MaxUint64
+	}
+	return 1 << hostBits
+}
+
+func broadcast(p netip.Prefix) netip.Addr {
+	b := p.Addr().As4()
+	v := binary.BigEndian.Uint32(b[:]) | (1<<(32-p.Bits()) - 1)
+	binary.BigEndian.PutUint32(b[:], v)
+	return netip.AddrFrom4(b)
+}
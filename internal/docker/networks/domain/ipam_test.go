@@ -0,0 +1,135 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRoutes struct {
+	routes []model.HostRoute
+	err    error
+}
+
+func (r staticRoutes) HostRoutes() ([]model.HostRoute, error) { return r.routes, r.err }
+
+func TestNetworkService_IPAMReport(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	svc.UseHostRoutes(staticRoutes{routes: []model.HostRoute{
+		{Destination: "172.16.0.0/12", Gateway: "10.8.0.1", Interface: "tun0"},
+		{Destination: "172.17.0.0/16", Interface: "docker0"},
+	}})
+	ctx := context.Background()
+
+	bridge := model.Network{
+		ID: "n1", Name: "bridge", Driver: "bridge",
+		IPAM: *pools(model.IPAMPool{Subnet: "172.17.0.0/16", Gateway: "172.17.0.1"}),
+		Containers: map[string]model.ContainerEndpoint{
+			"c-db":  {Name: "db", IPv4Address: "172.17.0.3/16"},
+			"c-web": {Name: "web", IPv4Address: "172.17.0.2/16"},
+		},
+	}
+	app := model.Network{
+		ID: "n2", Name: "app", Driver: "bridge",
+		IPAM: *pools(model.IPAMPool{
+			Subnet: "172.20.0.0/16", Gateway: "172.20.1.1", IPRange: "172.20.1.0/24",
+			AuxAddresses: map[string]string{"router": "172.20.0.2"},
+		}),
+		Containers: map[string]model.ContainerEndpoint{"c-api": {Name: "api", IPv4Address: "172.20.1.5/16"}},
+	}
+	dup := model.Network{ID: "n3", Name: "dup", Driver: "bridge", IPAM: *pools(model.IPAMPool{Subnet: "172.20.128.0/17"})}
+	cfg := model.Network{ID: "n4", Name: "cfg", Driver: "null", ConfigOnly: true, IPAM: *pools(model.IPAMPool{Subnet: "172.20.0.0/16"})}
+
+	a.On("List", ctx).Return([]model.Network{bridge, app, dup, cfg}, nil)
+	for _, n := range []model.Network{bridge, app, dup, cfg} {
+		a.On("Inspect", ctx, n.ID).Return(&n, nil)
+	}
+
+	report, err := svc.IPAMReport(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.RoutesChecked)
+	require.Len(t, report.Networks, 4)
+	assert.Equal(t, []string{"app", "bridge", "cfg", "dup"}, []string{
+		report.Networks[0].Name, report.Networks[1].Name, report.Networks[2].Name, report.Networks[3].Name,
+	})
+
+	appUsage := report.Networks[0].Subnets[0]
+	assert.Equal(t, uint64(65536), appUsage.Size)
+	assert.Equal(t, uint64(255), appUsage.Allocatable) // the range minus the gateway
+	assert.Equal(t, uint64(1), appUsage.Used)
+	assert.Equal(t, uint64(254), appUsage.Free)
+	assert.Equal(t, []model.IPAssignment{
+		{Address: "172.20.0.2", Kind: "aux", Label: "router"},
+		{Address: "172.20.1.1", Kind: "gateway"},
+		{Address: "172.20.1.5", ContainerID: "c-api", ContainerName: "api", Kind: "container"},
+	}, appUsage.Assignments)
+	assert.Equal(t, []model.IPAMConflict{
+		{Kind: model.IPAMConflictNetwork, With: "dup", Subnet: "172.20.128.0/17"},
+		{Kind: model.IPAMConflictRoute, With: "tun0", Subnet: "172.16.0.0/12", Gateway: "10.8.0.1"},
+	}, appUsage.Conflicts)
+
+	bridgeUsage := report.Networks[1].Subnets[0]
+	assert.Equal(t, uint64(65533), bridgeUsage.Allocatable)
+	assert.Equal(t, uint64(2), bridgeUsage.Used)
+	assert.Equal(t, uint64(65531), bridgeUsage.Free)
+	assert.Equal(t, "172.17.0.2", bridgeUsage.Assignments[1].Address)
+	// Docker's own docker0 route is not a conflict, the VPN's is.
+	assert.Equal(t, []model.IPAMConflict{
+		{Kind: model.IPAMConflictRoute, With: "tun0", Subnet: "172.16.0.0/12", Gateway: "10.8.0.1"},
+	}, bridgeUsage.Conflicts)
+
+	assert.Empty(t, report.Networks[2].Subnets[0].Conflicts)
+}
+
+func TestNetworkService_IPAMReport_RouteOwnedByInterface(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	// The VPN routes exactly the subnets the bridges use: only the routes
+	// through the bridges' own interfaces belong to Docker.
+	svc.UseHostRoutes(staticRoutes{routes: []model.HostRoute{
+		{Destination: "172.17.0.0/16", Interface: "docker0"},
+		{Destination: "172.17.0.0/16", Gateway: "10.8.0.1", Interface: "tun0"},
+		{Destination: "172.21.0.0/16", Interface: "br-0123456789ab"},
+		{Destination: "172.22.0.0/16", Interface: "lan-br"},
+	}})
+	ctx := context.Background()
+
+	networks := []model.Network{
+		{ID: "n1", Name: "bridge", Driver: "bridge", IPAM: *pools(model.IPAMPool{Subnet: "172.17.0.0/16"})},
+		{ID: "0123456789abcdef", Name: "app", Driver: "bridge", IPAM: *pools(model.IPAMPool{Subnet: "172.21.0.0/16"})},
+		{ID: "n3", Name: "lan", Driver: "bridge", Options: map[string]string{"com.docker.network.bridge.name": "lan-br"},
+			IPAM: *pools(model.IPAMPool{Subnet: "172.22.0.0/16"})},
+	}
+	a.On("List", ctx).Return(networks, nil)
+	for _, n := range networks {
+		a.On("Inspect", ctx, n.ID).Return(&n, nil)
+	}
+
+	report, err := svc.IPAMReport(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Networks, 3)
+	assert.Empty(t, report.Networks[0].Subnets[0].Conflicts) // app
+	assert.Equal(t, []model.IPAMConflict{
+		{Kind: model.IPAMConflictRoute, With: "tun0", Subnet: "172.17.0.0/16", Gateway: "10.8.0.1"},
+	}, report.Networks[1].Subnets[0].Conflicts) // bridge
+	assert.Empty(t, report.Networks[2].Subnets[0].Conflicts) // lan
+}
+
+func TestNetworkService_IPAMReport_NoRoutes(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	svc.UseHostRoutes(staticRoutes{err: assert.AnError})
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Network{}, nil)
+
+	report, err := svc.IPAMReport(ctx)
+	require.NoError(t, err)
+	assert.False(t, report.RoutesChecked)
+	assert.Empty(t, report.Networks)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rivernova/orcahub/internal/docker/dns"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+var hostName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,252}$`)
+
+// Lookup resolves opts.Name from a helper attached to networkID, and so
+// through the same resolver as the containers on it. Answers are matched to
+// the network's containers to show which one a name points at.
+func (s *NetworkServiceImpl) Lookup(ctx context.Context, networkID string, opts model.LookupOptions) (*model.LookupResult, error) {
+	if !hostName.MatchString(opts.Name) {
+		return nil, fmt.Errorf("%w: invalid name %q", ErrInvalidNetwork, opts.Name)
+	}
+	if opts.Type != "" && opts.Type != "A" && opts.Type != "AAAA" {
+		return nil, fmt.Errorf("%w: record type must be A or AAAA", ErrInvalidNetwork)
+	}
+	n, err := s.adapter.Inspect(ctx, networkID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Driver == "null" || n.ConfigOnly {
+		return nil, fmt.Errorf("%w: containers on network %s cannot resolve names", ErrInvalidNetwork, n.Name)
+	}
+
+	cmd := []string{"nslookup"}
+	if opts.Type != "" {
+		cmd = append(cmd, "-type="+opts.Type)
+	}
+	cmd = append(cmd, opts.Name)
+	results, err := s.adapter.ProbeNetwork(ctx, n.ID, [][]string{cmd})
+	if err != nil {
+		return nil, err
+	}
+
+	output := results[0].Output
+	result := &model.LookupResult{
+		Network: n.Name,
+		Name:    opts.Name,
+		Server:  dns.NslookupServer(output),
+		Answers: []model.LookupAnswer{},
+		Output:  strings.TrimSpace(output),
+	}
+	result.EmbeddedDNS = strings.HasPrefix(result.Server, dns.EmbeddedResolver)
+	for _, addr := range dns.ParseNslookup(output) {
+		answer := model.LookupAnswer{Address: addr}
+		for id, ep := range n.Containers {
+			if bareAddr(ep.IPv4Address) == addr || bareAddr(ep.IPv6Address) == addr {
+				answer.ContainerID, answer.ContainerName = id, ep.Name
+				break
+			}
+		}
+		result.Answers = append(result.Answers, answer)
+	}
+	return result, nil
+}
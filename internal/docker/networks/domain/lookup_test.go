@@ -0,0 +1,94 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func appNetwork() *model.Network {
+	return &model.Network{
+		ID: "n-app", Name: "app", Driver: "bridge",
+		Containers: map[string]model.ContainerEndpoint{
+			"c-web": {Name: "web", IPv4Address: "172.20.0.2/16"},
+			"c-api": {Name: "api", IPv4Address: "172.20.0.3/16"},
+		},
+	}
+}
+
+func TestNetworkService_Lookup(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "app").Return(appNetwork(), nil)
+	a.On("ProbeNetwork", ctx, "n-app", [][]string{{"nslookup", "-type=A", "web"}}).Return([]model.ProbeResult{{
+		Output: "Server:\t\t127.0.0.11\nAddress:\t127.0.0.11:53\n\nNon-authoritative answer:\nName:\tweb\nAddress: 172.20.0.2\n",
+	}}, nil)
+
+	result, err := svc.Lookup(ctx, "app", model.LookupOptions{Name: "web", Type: "A"})
+	require.NoError(t, err)
+	assert.Equal(t, "app", result.Network)
+	assert.Equal(t, "127.0.0.11", result.Server)
+	assert.True(t, result.EmbeddedDNS)
+	assert.Equal(t, []model.LookupAnswer{{Address: "172.20.0.2", ContainerID: "c-web", ContainerName: "web"}}, result.Answers)
+}
+
+func TestNetworkService_Lookup_NotFoundName(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "bridge").Return(&model.Network{ID: "n-bridge", Name: "bridge", Driver: "bridge"}, nil)
+	a.On("ProbeNetwork", ctx, "n-bridge", [][]string{{"nslookup", "web"}}).Return([]model.ProbeResult{{
+		ExitCode: 1,
+		Output:   "Server:\t\t192.168.1.1\nAddress:\t192.168.1.1:53\n\n** server can't find web: NXDOMAIN\n",
+	}}, nil)
+
+	result, err := svc.Lookup(ctx, "bridge", model.LookupOptions{Name: "web"})
+	require.NoError(t, err)
+	assert.False(t, result.EmbeddedDNS)
+	assert.Empty(t, result.Answers)
+	assert.Contains(t, result.Output, "NXDOMAIN")
+}
+
+func TestNetworkService_Lookup_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		network *model.Network
+		opts    model.LookupOptions
+	}{
+		"empty name":   {appNetwork(), model.LookupOptions{}},
+		"option name":  {appNetwork(), model.LookupOptions{Name: "-debug"}},
+		"spaces":       {appNetwork(), model.LookupOptions{Name: "web api"}},
+		"record type":  {appNetwork(), model.LookupOptions{Name: "web", Type: "MX"}},
+		"none network": {&model.Network{ID: "n-none", Name: "none", Driver: "null"}, model.LookupOptions{Name: "web"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := &mockNetworkAdapter{}
+			svc := domain.NewNetworkServiceImpl(a)
+			a.On("Inspect", mock.Anything, "net").Return(tc.network, nil).Maybe()
+
+			_, err := svc.Lookup(context.Background(), "net", tc.opts)
+			assert.ErrorIs(t, err, domain.ErrInvalidNetwork)
+			a.AssertNotCalled(t, "ProbeNetwork", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNetworkService_Lookup_NetworkNotFound(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "ghost").Return(nil, cerrdefs.ErrNotFound)
+
+	_, err := svc.Lookup(ctx, "ghost", model.LookupOptions{Name: "web"})
+	assert.True(t, cerrdefs.IsNotFound(err))
+}
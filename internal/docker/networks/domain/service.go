@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 
 	model "github.com/rivernova/orcahub/internal/docker/networks/model"
 )
@@ -11,7 +12,12 @@ type NetworkService interface {
 	Inspect(ctx context.Context, id string) (*model.Network, error)
 	Create(ctx context.Context, opts model.CreateNetworkOptions) (*model.Network, error)
 	Delete(ctx context.Context, id string) error
-	Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error
+	Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error)
 	Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error
 	Prune(ctx context.Context) (model.PruneResult, error)
+	Topology(ctx context.Context) (*model.Topology, error)
+	IPAMReport(ctx context.Context) (*model.IPAMReport, error)
+	Diagnose(ctx context.Context, opts model.DiagnoseOptions) (*model.Diagnosis, error)
+	Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error)
+	Lookup(ctx context.Context, networkID string, opts model.LookupOptions) (*model.LookupResult, error)
 }
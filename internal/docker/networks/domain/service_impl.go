@@ -9,6 +9,7 @@ import (
 
 type NetworkServiceImpl struct {
 	adapter adapter.NetworkAdapter
+	routes  adapter.RouteReader
 }
 
 func NewNetworkServiceImpl(adapter adapter.NetworkAdapter) *NetworkServiceImpl {
@@ -23,18 +24,10 @@ func (s *NetworkServiceImpl) Inspect(ctx context.Context, id string) (*model.Net
 	return s.adapter.Inspect(ctx, id)
 }
 
-func (s *NetworkServiceImpl) Create(ctx context.Context, opts model.CreateNetworkOptions) (*model.Network, error) {
-	return s.adapter.Create(ctx, opts)
-}
-
 func (s *NetworkServiceImpl) Delete(ctx context.Context, id string) error {
 	return s.adapter.Delete(ctx, id)
 }
 
-func (s *NetworkServiceImpl) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error {
-	return s.adapter.Connect(ctx, networkID, opts)
-}
-
 func (s *NetworkServiceImpl) Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error {
 	return s.adapter.Disconnect(ctx, networkID, opts)
 }
@@ -3,6 +3,7 @@ package domain_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/rivernova/orcahub/internal/docker/networks/domain"
@@ -34,8 +35,12 @@ func (m *mockNetworkAdapter) Create(ctx context.Context, opts model.CreateNetwor
 func (m *mockNetworkAdapter) Delete(ctx context.Context, id string) error {
 	return m.Called(ctx, id).Error(0)
 }
-func (m *mockNetworkAdapter) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) error {
-	return m.Called(ctx, networkID, opts).Error(0)
+func (m *mockNetworkAdapter) Connect(ctx context.Context, networkID string, opts model.ConnectOptions) (*model.EndpointInfo, error) {
+	args := m.Called(ctx, networkID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.EndpointInfo), args.Error(1)
 }
 func (m *mockNetworkAdapter) Disconnect(ctx context.Context, networkID string, opts model.DisconnectOptions) error {
 	return m.Called(ctx, networkID, opts).Error(0)
@@ -46,6 +51,46 @@ func (m *mockNetworkAdapter) Prune(ctx context.Context) (model.PruneResult, erro
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
 
+func (m *mockNetworkAdapter) ContainerNetworks(ctx context.Context) ([]model.ContainerNetworks, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ContainerNetworks), args.Error(1)
+}
+
+func (m *mockNetworkAdapter) Capture(ctx context.Context, opts model.CaptureOptions) (io.ReadCloser, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *mockNetworkAdapter) ProbeNetwork(ctx context.Context, networkID string, cmds [][]string) ([]model.ProbeResult, error) {
+	args := m.Called(ctx, networkID, cmds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProbeResult), args.Error(1)
+}
+
+func (m *mockNetworkAdapter) InspectContainer(ctx context.Context, id string) (*model.ContainerNetworks, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ContainerNetworks), args.Error(1)
+}
+
+func (m *mockNetworkAdapter) Probe(ctx context.Context, id string, cmds [][]string) ([]model.ProbeResult, error) {
+	args := m.Called(ctx, id, cmds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProbeResult), args.Error(1)
+}
+
 func TestNetworkService_List(t *testing.T) {
 	a := &mockNetworkAdapter{}
 	svc := domain.NewNetworkServiceImpl(a)
@@ -135,9 +180,15 @@ func TestNetworkService_Connect(t *testing.T) {
 	ctx := context.Background()
 
 	opts := model.ConnectOptions{ContainerID: "abc123", IPv4Address: "172.18.0.5"}
-	a.On("Connect", ctx, "net1", opts).Return(nil)
+	a.On("Inspect", ctx, "net1").Return(&model.Network{
+		ID: "net1", Name: "app",
+		IPAM: model.IPAM{Config: []model.IPAMPool{{Subnet: "172.18.0.0/16", Gateway: "172.18.0.1"}}},
+	}, nil)
+	a.On("Connect", ctx, "net1", opts).Return(&model.EndpointInfo{NetworkID: "net1", IPv4Address: "172.18.0.5"}, nil)
 
-	assert.NoError(t, svc.Connect(ctx, "net1", opts))
+	endpoint, err := svc.Connect(ctx, "net1", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "172.18.0.5", endpoint.IPv4Address)
 }
 
 func TestNetworkService_Disconnect(t *testing.T) {
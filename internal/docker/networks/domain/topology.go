@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	model "github.com/rivernova/orcahub/internal/docker/networks/model"
+)
+
+// Topology builds a graph of which containers share which networks, and
+// which containers the host reaches through published ports.
+func (s *NetworkServiceImpl) Topology(ctx context.Context) (*model.Topology, error) {
+	networks, err := s.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := s.adapter.ContainerNetworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &model.Topology{
+		Nodes: []model.TopologyNode{{ID: model.TopologyHostID, Type: model.TopologyNodeHost, Name: "host"}},
+		Edges: []model.TopologyEdge{},
+	}
+
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+	known := make(map[string]bool, len(networks))
+	for _, n := range networks {
+		// List leaves out IPAM, so inspect each network for its subnets.
+		full, err := s.adapter.Inspect(ctx, n.ID)
+		if cerrdefs.IsNotFound(err) {
+			continue // removed since the list
+		}
+		if err != nil {
+			return nil, err
+		}
+		subnets := make([]string, 0, len(full.IPAM.Config))
+		for _, p := range full.IPAM.Config {
+			subnets = append(subnets, p.Subnet)
+		}
+		known[full.ID] = true
+		topology.Nodes = append(topology.Nodes, model.TopologyNode{
+			ID:       networkNodeID(full.ID),
+			Type:     model.TopologyNodeNetwork,
+			Name:     full.Name,
+			Driver:   full.Driver,
+			Internal: full.Internal,
+			Subnets:  subnets,
+		})
+	}
+
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+	for _, c := range containers {
+		topology.Nodes = append(topology.Nodes, model.TopologyNode{
+			ID:    containerNodeID(c.ID),
+			Type:  model.TopologyNodeContainer,
+			Name:  c.Name,
+			State: c.State,
+		})
+
+		networkIDs := make([]string, 0, len(c.Endpoints))
+		for id := range c.Endpoints {
+			if known[id] {
+				networkIDs = append(networkIDs, id)
+			}
+		}
+		sort.Slice(networkIDs, func(i, j int) bool {
+			return c.Endpoints[networkIDs[i]].NetworkName < c.Endpoints[networkIDs[j]].NetworkName
+		})
+		for _, id := range networkIDs {
+			ep := c.Endpoints[id]
+			topology.Edges = append(topology.Edges, model.TopologyEdge{
+				Source:      containerNodeID(c.ID),
+				Target:      networkNodeID(id),
+				Type:        model.TopologyEdgeEndpoint,
+				IPv4Address: ep.IPv4Address,
+				IPv6Address: ep.IPv6Address,
+				MacAddress:  ep.MacAddress,
+				Aliases:     ep.Aliases,
+			})
+		}
+
+		if len(c.Ports) > 0 {
+			topology.Edges = append(topology.Edges, model.TopologyEdge{
+				Source: model.TopologyHostID,
+				Target: containerNodeID(c.ID),
+				Type:   model.TopologyEdgePort,
+				Ports:  c.Ports,
+			})
+		}
+
+		if ref, ok := strings.CutPrefix(c.NetworkMode, "container:"); ok {
+			if owner := findContainer(containers, ref); owner != "" {
+				topology.Edges = append(topology.Edges, model.TopologyEdge{
+					Source: containerNodeID(c.ID),
+					Target: containerNodeID(owner),
+					Type:   model.TopologyEdgeNetNS,
+				})
+			}
+		}
+	}
+	return topology, nil
+}
+
+func networkNodeID(id string) string   { return model.TopologyNodeNetwork + ":" + id }
+func containerNodeID(id string) string { return model.TopologyNodeContainer + ":" + id }
+
+// findContainer resolves a network mode reference, which may be a name or
+// an ID, to a full container ID.
+func findContainer(containers []model.ContainerNetworks, ref string) string {
+	for _, c := range containers {
+		if c.ID == ref || c.Name == ref {
+			return c.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/networks/domain"
+	"github.com/rivernova/orcahub/internal/docker/networks/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkService_Topology(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Network{
+		{ID: "n-front", Name: "frontend"},
+		{ID: "n-back", Name: "backend"},
+		{ID: "n-gone", Name: "gone"},
+	}, nil)
+	a.On("Inspect", ctx, "n-front").Return(&model.Network{
+		ID: "n-front", Name: "frontend", Driver: "bridge",
+		IPAM: model.IPAM{Config: []model.IPAMPool{{Subnet: "172.20.0.0/16"}}},
+	}, nil)
+	a.On("Inspect", ctx, "n-back").Return(&model.Network{
+		ID: "n-back", Name: "backend", Driver: "bridge", Internal: true,
+		IPAM: model.IPAM{Config: []model.IPAMPool{{Subnet: "172.21.0.0/16"}}},
+	}, nil)
+	a.On("Inspect", ctx, "n-gone").Return(nil, cerrdefs.ErrNotFound)
+	a.On("ContainerNetworks", ctx).Return([]model.ContainerNetworks{
+		{
+			ID: "c-web", Name: "web", State: "running",
+			Endpoints: map[string]model.EndpointInfo{
+				"n-front": {NetworkName: "frontend", IPv4Address: "172.20.0.2", Aliases: []string{"www"}},
+				"n-back":  {NetworkName: "backend", IPv4Address: "172.21.0.2"},
+			},
+			Ports: []model.PublishedPort{{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}},
+		},
+		{
+			ID: "c-db", Name: "db", State: "running",
+			Endpoints: map[string]model.EndpointInfo{
+				"n-back": {NetworkName: "backend", IPv4Address: "172.21.0.3", Aliases: []string{"postgres"}},
+			},
+		},
+		{ID: "c-sidecar", Name: "sidecar", State: "running", NetworkMode: "container:web"},
+	}, nil)
+
+	topology, err := svc.Topology(ctx)
+	require.NoError(t, err)
+
+	var nodes []string
+	for _, n := range topology.Nodes {
+		nodes = append(nodes, n.ID)
+	}
+	assert.Equal(t, []string{
+		"host",
+		"network:n-back", "network:n-front",
+		"container:c-db", "container:c-sidecar", "container:c-web",
+	}, nodes)
+	assert.Equal(t, []string{"172.21.0.0/16"}, topology.Nodes[1].Subnets)
+	assert.True(t, topology.Nodes[1].Internal)
+
+	assert.Equal(t, []model.TopologyEdge{
+		{Source: "container:c-db", Target: "network:n-back", Type: model.TopologyEdgeEndpoint, IPv4Address: "172.21.0.3", Aliases: []string{"postgres"}},
+		{Source: "container:c-sidecar", Target: "container:c-web", Type: model.TopologyEdgeNetNS},
+		{Source: "container:c-web", Target: "network:n-back", Type: model.TopologyEdgeEndpoint, IPv4Address: "172.21.0.2"},
+		{Source: "container:c-web", Target: "network:n-front", Type: model.TopologyEdgeEndpoint, IPv4Address: "172.20.0.2", Aliases: []string{"www"}},
+		{Source: "host", Target: "container:c-web", Type: model.TopologyEdgePort, Ports: []model.PublishedPort{{HostIP: "0.0.0.0", HostPort: "8080", ContainerPort: "80", Protocol: "tcp"}}},
+	}, topology.Edges)
+}
+
+func TestNetworkService_Topology_ContainersError(t *testing.T) {
+	a := &mockNetworkAdapter{}
+	svc := domain.NewNetworkServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Network{}, nil)
+	a.On("ContainerNetworks", ctx).Return(nil, assert.AnError)
+
+	_, err := svc.Topology(ctx)
+	assert.ErrorIs(t, err, assert.AnError)
+}
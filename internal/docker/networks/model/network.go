@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Network struct {
 	ID         string
 	Name       string
@@ -10,18 +12,26 @@ type Network struct {
 	Labels     map[string]string
 	Options    map[string]string
 	Created    string
+	EnableIPv6 bool
+	ConfigFrom string // config-only network this one takes its config from
+	ConfigOnly bool
 	IPAM       IPAM
 	Containers map[string]ContainerEndpoint
 }
 
 type IPAM struct {
-	Driver string
-	Config []IPAMPool
+	Driver  string
+	Options map[string]string
+	Config  []IPAMPool
 }
 
+// IPAMPool is a subnet. IPRange narrows the addresses handed to containers
+// and AuxAddresses reserves named addresses, such as a router, in it.
 type IPAMPool struct {
-	Subnet  string
-	Gateway string
+	Subnet       string
+	Gateway      string
+	IPRange      string
+	AuxAddresses map[string]string
 }
 
 type ContainerEndpoint struct {
@@ -29,6 +39,40 @@ type ContainerEndpoint struct {
 	EndpointID  string
 	MacAddress  string
 	IPv4Address string
+	IPv6Address string
+}
+
+// ContainerNetworks is a container's view of its networking, from
+// container inspect. Endpoints are keyed by network ID.
+type ContainerNetworks struct {
+	ID          string
+	Name        string
+	State       string
+	NetworkMode string
+	Endpoints   map[string]EndpointInfo
+	Ports       []PublishedPort
+}
+
+type EndpointInfo struct {
+	NetworkID   string
+	NetworkName string
+	EndpointID  string
+	IPv4Address string
+	IPv6Address string
+	Gateway     string
+	IPv6Gateway string
+	MacAddress  string
+	Aliases     []string
+	Links       []string
+	DriverOpts  map[string]string
+}
+
+// PublishedPort is a container port bound on the host.
+type PublishedPort struct {
+	HostIP        string
+	HostPort      string
+	ContainerPort string
+	Protocol      string
 }
 
 type CreateNetworkOptions struct {
@@ -36,15 +80,27 @@ type CreateNetworkOptions struct {
 	Driver     string
 	Internal   bool
 	Attachable bool
+	EnableIPv6 bool
+	// ConfigFrom names a config-only network to take IPAM and driver
+	// options from; they may not be set here as well.
+	ConfigFrom string
+	ConfigOnly bool
 	Labels     map[string]string
 	Options    map[string]string
 	IPAM       *IPAM
 }
 
+// ConnectOptions attaches a container to a network. Static addresses must
+// fall in a subnet configured on the network; Links take the form
+// "container" or "container:alias".
 type ConnectOptions struct {
 	ContainerID string
 	IPv4Address string
+	IPv6Address string
 	Aliases     []string
+	Links       []string
+	MacAddress  string
+	DriverOpts  map[string]string
 }
 
 type DisconnectOptions struct {
@@ -55,3 +111,191 @@ type PruneResult struct {
 	Deleted        []string `json:"deleted"`
 	SpaceReclaimed int64    `json:"space_reclaimed"`
 }
+
+const (
+	TopologyNodeNetwork   = "network"
+	TopologyNodeContainer = "container"
+	TopologyNodeHost      = "host"
+
+	// TopologyEdgeEndpoint joins a container to a network it is attached to.
+	TopologyEdgeEndpoint = "endpoint"
+	// TopologyEdgePort joins the host to a container publishing ports.
+	TopologyEdgePort = "port"
+	// TopologyEdgeNetNS joins a container to the one whose network
+	// namespace it shares.
+	TopologyEdgeNetNS = "netns"
+
+	// TopologyHostID is the ID of the single host node.
+	TopologyHostID = "host"
+)
+
+// Topology is a graph of networks, containers and the host. Node IDs are
+// prefixed with their type, e.g. "network:<id>", so they never collide.
+type Topology struct {
+	Nodes []TopologyNode
+	Edges []TopologyEdge
+}
+
+type TopologyNode struct {
+	ID       string
+	Type     string
+	Name     string
+	Driver   string   // networks
+	Internal bool     // networks
+	Subnets  []string // networks
+	State    string   // containers
+}
+
+type TopologyEdge struct {
+	Source      string
+	Target      string
+	Type        string
+	IPv4Address string
+	IPv6Address string
+	MacAddress  string
+	Aliases     []string
+	Ports       []PublishedPort
+}
+
+// HostRoute is a route in the host's main routing table.
+type HostRoute struct {
+	Destination string // CIDR
+	Gateway     string
+	Interface   string
+}
+
+const (
+	IPAMConflictNetwork = "network"
+	IPAMConflictRoute   = "route"
+)
+
+// IPAMReport covers the subnets of every network. Address counts only
+// include running containers, which are the only ones holding addresses.
+type IPAMReport struct {
+	Networks []NetworkIPAM
+	// RoutesChecked is false when host routes could not be read, so route
+	// conflicts are unknown rather than absent.
+	RoutesChecked bool
+}
+
+type NetworkIPAM struct {
+	ID         string
+	Name       string
+	Driver     string
+	ConfigOnly bool
+	Subnets    []SubnetUsage
+}
+
+// SubnetUsage counts the addresses of a subnet. Allocatable counts what
+// the IPAM driver can hand out, from IPRange when set, after the network,
+// broadcast, gateway and aux addresses. Counts saturate for large IPv6
+// subnets.
+type SubnetUsage struct {
+	Subnet      string
+	Gateway     string
+	IPRange     string
+	Size        uint64
+	Allocatable uint64
+	Used        uint64
+	Free        uint64
+	Assignments []IPAssignment
+	Conflicts   []IPAMConflict
+}
+
+type IPAssignment struct {
+	Address       string
+	ContainerID   string // empty for gateway and aux addresses
+	ContainerName string
+	Kind          string // container, gateway or aux
+	Label         string // aux address name
+}
+
+// IPAMConflict is an overlap of a subnet with another network's subnet or a
+// host route. With names the network or, for routes, the interface.
+type IPAMConflict struct {
+	Kind    string
+	With    string
+	Subnet  string
+	Gateway string // routes
+}
+
+// DiagnoseOptions asks whether Source can reach Target. Host overrides the
+// name resolved, e.g. to test an alias; Port adds a TCP check.
+type DiagnoseOptions struct {
+	Source string
+	Target string
+	Host   string
+	Port   int
+}
+
+const (
+	DiagnoseStepOK      = "ok"
+	DiagnoseStepWarning = "warning"
+	DiagnoseStepFailed  = "failed"
+	DiagnoseStepSkipped = "skipped"
+)
+
+type Diagnosis struct {
+	Source    string
+	Target    string
+	Address   string // target address probed, on a shared network when there is one
+	Reachable bool
+	Steps     []DiagnoseStep
+}
+
+// DiagnoseStep is one check. Command and Output are set for the checks run
+// inside the source's network namespace.
+type DiagnoseStep struct {
+	Name     string
+	Status   string
+	Detail   string
+	Command  string
+	Output   string
+	Duration time.Duration
+}
+
+// ProbeResult is the outcome of a command run next to a container.
+type ProbeResult struct {
+	ExitCode int
+	Output   string
+	Duration time.Duration
+}
+
+// CaptureOptions selects the traffic captured in a container's network
+// namespace. Filter is a BPF expression as taken by tcpdump; the capture
+// stops after Duration or MaxPackets packets, whichever comes first.
+type CaptureOptions struct {
+	ContainerID string
+	Interface   string
+	Filter      string
+	Duration    time.Duration
+	MaxPackets  int
+	SnapLen     int
+}
+
+// LookupOptions resolves Name from inside a network. Type is "A" or "AAAA";
+// empty asks for both.
+type LookupOptions struct {
+	Name string
+	Type string
+}
+
+// LookupResult is what a container on Network would get for Name. Server is
+// the resolver that answered; on user-defined networks it is the embedded
+// DNS.
+type LookupResult struct {
+	Network     string
+	Name        string
+	Server      string
+	EmbeddedDNS bool
+	Answers     []LookupAnswer
+	Output      string
+}
+
+// LookupAnswer is a resolved address and, when it belongs to a container on
+// the network, that container.
+type LookupAnswer struct {
+	Address       string
+	ContainerID   string
+	ContainerName string
+}
@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"context"
+	"io"
 
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
 )
@@ -11,5 +12,22 @@ type VolumeAdapter interface {
 	Inspect(ctx context.Context, name string) (*model.Volume, error)
 	Create(ctx context.Context, opts model.CreateVolumeOptions) (*model.Volume, error)
 	Delete(ctx context.Context, name string) error
-	Prune(ctx context.Context) (model.PruneResult, error)
+	Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error)
+	// Usage returns the disk usage of every volume, keyed by name. It walks
+	// the volumes on disk, so it is slow on hosts with large volumes.
+	Usage(ctx context.Context) (map[string]model.VolumeUsage, error)
+	Mounts(ctx context.Context) ([]model.VolumeMount, error)
+	// ListFiles lists the entries of dir, an absolute path from the volume
+	// root. The volume is mounted read-only into a helper container.
+	ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error)
+	// CopyFrom returns a tar archive of path inside the volume and its stat.
+	CopyFrom(ctx context.Context, name, path string) (io.ReadCloser, *model.VolumeFile, error)
+	// CopyTo extracts a tar archive into dir inside the volume.
+	CopyTo(ctx context.Context, name, dir string, archive io.Reader) error
+	// Replace swaps the volume's contents for those of a tar archive. The
+	// archive is extracted next to the current contents first, so they are
+	// only removed once it has been written in full.
+	Replace(ctx context.Context, name string, archive io.Reader) error
+	StopContainer(ctx context.Context, id string) error
+	StartContainer(ctx context.Context, id string) error
 }
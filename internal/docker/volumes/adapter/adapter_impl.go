@@ -3,15 +3,21 @@ package adapter
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
 )
 
 type VolumeAdapterImpl struct {
-	client *client.Client
+	client      *client.Client
+	helperImage string
 }
 
 func NewVolumeAdapterImpl() (*VolumeAdapterImpl, error) {
@@ -19,7 +25,7 @@ func NewVolumeAdapterImpl() (*VolumeAdapterImpl, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
 	}
-	return &VolumeAdapterImpl{client: cli}, nil
+	return &VolumeAdapterImpl{client: cli, helperImage: DefaultHelperImage}, nil
 }
 
 // Compile-time check: VolumeAdapterImpl must implement VolumeAdapter
@@ -98,8 +104,28 @@ func (a *VolumeAdapterImpl) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
-func (a *VolumeAdapterImpl) Prune(ctx context.Context) (model.PruneResult, error) {
-	report, err := a.client.VolumesPrune(ctx, filters.Args{})
+func (a *VolumeAdapterImpl) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := filters.NewArgs()
+	for _, l := range opts.Labels {
+		args.Add("label", l)
+	}
+	for _, l := range opts.ExcludeLabels {
+		args.Add("label!", l)
+	}
+	if opts.All {
+		// API 1.42 made prune skip named volumes unless asked; older
+		// daemons reject the filter and always include them.
+		a.client.NegotiateAPIVersion(ctx)
+		if versions.GreaterThanOrEqualTo(a.client.ClientVersion(), "1.42") {
+			args.Add("all", "true")
+		}
+	} else {
+		// Filter on the label rather than relying on the daemon default,
+		// which on older daemons removes named volumes too.
+		args.Add("label", model.AnonymousLabel)
+	}
+
+	report, err := a.client.VolumesPrune(ctx, args)
 	if err != nil {
 		return model.PruneResult{}, fmt.Errorf("failed to prune volumes: %w", err)
 	}
@@ -108,3 +134,62 @@ func (a *VolumeAdapterImpl) Prune(ctx context.Context) (model.PruneResult, error
 		SpaceReclaimed: int64(report.SpaceReclaimed),
 	}, nil
 }
+
+func (a *VolumeAdapterImpl) Usage(ctx context.Context) (map[string]model.VolumeUsage, error) {
+	du, err := a.client.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume disk usage: %w", err)
+	}
+	result := make(map[string]model.VolumeUsage, len(du.Volumes))
+	for _, v := range du.Volumes {
+		usage := model.VolumeUsage{Size: -1, RefCount: -1}
+		if v.UsageData != nil {
+			usage.Size = v.UsageData.Size
+			usage.RefCount = v.UsageData.RefCount
+		}
+		result[v.Name] = usage
+	}
+	return result, nil
+}
+
+func (a *VolumeAdapterImpl) Mounts(ctx context.Context) ([]model.VolumeMount, error) {
+	containers, err := a.client.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	var result []model.VolumeMount
+	for _, c := range containers {
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		for _, m := range c.Mounts {
+			if m.Type != mount.TypeVolume {
+				continue
+			}
+			result = append(result, model.VolumeMount{
+				VolumeName:    m.Name,
+				ContainerID:   c.ID,
+				ContainerName: name,
+				State:         c.State,
+				Destination:   m.Destination,
+				RW:            m.RW,
+			})
+		}
+	}
+	return result, nil
+}
+
+func (a *VolumeAdapterImpl) StopContainer(ctx context.Context, id string) error {
+	if err := a.client.ContainerStop(ctx, id, container.StopOptions{}); err != nil {
+		return fmt.Errorf("failed to stop container %s: %w", id, err)
+	}
+	return nil
+}
+
+func (a *VolumeAdapterImpl) StartContainer(ctx context.Context, id string) error {
+	if err := a.client.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start container %s: %w", id, err)
+	}
+	return nil
+}
@@ -67,6 +67,23 @@ func TestVolumeAdapter_Inspect_NotFound(t *testing.T) {
 func TestVolumeAdapter_Prune(t *testing.T) {
 	a, err := adapter.NewVolumeAdapterImpl()
 	require.NoError(t, err)
-	_, err = a.Prune(context.Background())
+	_, err = a.Prune(context.Background(), model.PruneOptions{})
+	assert.NoError(t, err)
+}
+
+func TestVolumeAdapter_Prune_KeepsNamedVolumes(t *testing.T) {
+	a, err := adapter.NewVolumeAdapterImpl()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = a.Create(ctx, model.CreateVolumeOptions{Name: "orcahub-test-prune-named"})
+	require.NoError(t, err)
+	t.Cleanup(func() { a.Delete(context.Background(), "orcahub-test-prune-named") })
+
+	result, err := a.Prune(ctx, model.PruneOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Deleted, "orcahub-test-prune-named")
+
+	_, err = a.Inspect(ctx, "orcahub-test-prune-named")
 	assert.NoError(t, err)
 }
@@ -0,0 +1,230 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+const (
+	backupTimeLayout = "20060102T150405.000Z"
+	// legacyBackupTimeLayout named backups before IDs had sub-second
+	// precision; such backups are still listed and restored.
+	legacyBackupTimeLayout = "20060102T150405Z"
+	backupExt              = ".tar.gz"
+	checksumExt            = ".sha256"
+)
+
+// BackupTarget stores volume backups. Backups are named
+// <volume>-<timestamp>.tar.gz, or <volume>-<timestamp>-<schedule>.tar.gz
+// when a schedule took them, and each has a sha256sum compatible checksum
+// file next to it.
+type BackupTarget interface {
+	// Save stores the archive produced by write as a new backup of volume,
+	// tagged with schedule unless it is empty.
+	Save(ctx context.Context, volume, schedule string, created time.Time, write func(io.Writer) (*model.Backup, error)) (*model.Backup, error)
+	// List returns the backups of volume, newest first.
+	List(ctx context.Context, volume string) ([]model.Backup, error)
+	// Open returns the backup archive and its metadata, including the
+	// recorded checksum, which is empty only when none was recorded.
+	Open(ctx context.Context, id string) (io.ReadCloser, *model.Backup, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// LocalTarget keeps backups in a local directory.
+type LocalTarget struct {
+	dir string
+}
+
+var _ BackupTarget = (*LocalTarget)(nil)
+
+func NewLocalTarget(dir string) (*LocalTarget, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %w", dir, err)
+	}
+	return &LocalTarget{dir: dir}, nil
+}
+
+// Save only gives the file its final name once it is complete, and never
+// replaces an existing backup of the same name.
+func (t *LocalTarget) Save(_ context.Context, volume, schedule string, created time.Time, write func(io.Writer) (*model.Backup, error)) (*model.Backup, error) {
+	id := BackupID(volume, schedule, created)
+	tmp, err := os.CreateTemp(t.dir, "."+id+".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup %s: %w", id, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	backup, err := write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	checksumFile := filepath.Join(t.dir, id+checksumExt)
+	if err := writeExclusive(checksumFile, []byte(checksumLine(backup.SHA256, id))); err != nil {
+		return nil, fmt.Errorf("failed to write checksum for backup %s: %w", id, err)
+	}
+	// Unlike a rename, a link fails when the name is already taken.
+	if err := os.Link(tmp.Name(), filepath.Join(t.dir, id)); err != nil {
+		os.Remove(checksumFile)
+		return nil, fmt.Errorf("failed to store backup %s: %w", id, err)
+	}
+	backup.ID = id
+	backup.ScheduleID = schedule
+	backup.CreatedAt = created.UTC().Truncate(time.Millisecond)
+	return backup, nil
+}
+
+func (t *LocalTarget) List(_ context.Context, volume string) ([]model.Backup, error) {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+	backups := []model.Backup{}
+	for _, e := range entries {
+		created, schedule, ok := ParseBackupID(e.Name(), volume)
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		sum, _ := t.checksum(e.Name())
+		backups = append(backups, model.Backup{
+			ID:         e.Name(),
+			Volume:     volume,
+			Size:       info.Size(),
+			SHA256:     sum,
+			ScheduleID: schedule,
+			CreatedAt:  created,
+		})
+	}
+	sortBackups(backups)
+	return backups, nil
+}
+
+func (t *LocalTarget) Open(_ context.Context, id string) (io.ReadCloser, *model.Backup, error) {
+	if !validBackupID(id) {
+		return nil, nil, fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+	}
+	f, err := os.Open(filepath.Join(t.dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to open backup %s: %w", id, err)
+	}
+	backup := &model.Backup{ID: id}
+	if info, err := f.Stat(); err == nil {
+		backup.Size = info.Size()
+	}
+	if backup.SHA256, err = t.checksum(id); err != nil && !errors.Is(err, os.ErrNotExist) {
+		f.Close()
+		return nil, nil, fmt.Errorf("failed to read checksum of backup %s: %w", id, err)
+	}
+	return f, backup, nil
+}
+
+func (t *LocalTarget) Delete(_ context.Context, id string) error {
+	if !validBackupID(id) {
+		return fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+	}
+	if err := os.Remove(filepath.Join(t.dir, id)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete backup %s: %w", id, err)
+	}
+	if err := os.Remove(filepath.Join(t.dir, id+checksumExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete checksum of backup %s: %w", id, err)
+	}
+	return nil
+}
+
+// writeExclusive creates file with data, failing if it already exists.
+func writeExclusive(file string, data []byte) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+	}
+	return err
+}
+
+func (t *LocalTarget) checksum(id string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(t.dir, id+checksumExt))
+	if err != nil {
+		return "", err
+	}
+	return parseChecksum(data), nil
+}
+
+// BackupID names the backup of volume taken at created by schedule, which
+// is empty for backups taken on request.
+func BackupID(volume, schedule string, created time.Time) string {
+	id := volume + "-" + created.UTC().Format(backupTimeLayout)
+	if schedule != "" {
+		id += "-" + schedule
+	}
+	return id + backupExt
+}
+
+// ParseBackupID reports whether id names a backup of volume, when it was
+// taken and by which schedule, if any. Volume names may share prefixes, so
+// the timestamp must parse exactly.
+func ParseBackupID(id, volume string) (created time.Time, schedule string, ok bool) {
+	rest, ok := strings.CutPrefix(id, volume+"-")
+	if !ok {
+		return time.Time{}, "", false
+	}
+	rest, ok = strings.CutSuffix(rest, backupExt)
+	if !ok {
+		return time.Time{}, "", false
+	}
+	stamp, schedule, tagged := strings.Cut(rest, "-")
+	if tagged && schedule == "" {
+		return time.Time{}, "", false
+	}
+	created, err := time.Parse(backupTimeLayout, stamp)
+	if err != nil {
+		created, err = time.Parse(legacyBackupTimeLayout, stamp)
+	}
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return created, schedule, true
+}
+
+func validBackupID(id string) bool {
+	return id == filepath.Base(id) && !strings.HasPrefix(id, ".") && strings.HasSuffix(id, backupExt)
+}
+
+func checksumLine(sum, id string) string {
+	return fmt.Sprintf("%s  %s\n", sum, id)
+}
+
+func parseChecksum(data []byte) string {
+	sum, _, _ := strings.Cut(strings.TrimSpace(string(data)), " ")
+	return sum
+}
+
+func sortBackups(backups []model.Backup) {
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+}
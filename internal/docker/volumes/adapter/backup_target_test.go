@@ -0,0 +1,128 @@
+package adapter_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
+	"github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saveBackup(t *testing.T, target adapter.BackupTarget, volume string, created time.Time) *model.Backup {
+	t.Helper()
+	backup, err := target.Save(context.Background(), volume, "", created, func(w io.Writer) (*model.Backup, error) {
+		w.Write([]byte("archive"))
+		return &model.Backup{Volume: volume, Size: 7, SHA256: "abc123"}, nil
+	})
+	require.NoError(t, err)
+	return backup
+}
+
+func TestLocalTarget(t *testing.T) {
+	dir := t.TempDir()
+	target, err := adapter.NewLocalTarget(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	older := saveBackup(t, target, "pg", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := saveBackup(t, target, "pg", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC))
+	// A volume whose name extends another's must not show up in its list.
+	saveBackup(t, target, "pg-data", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "pg-20260101T000000.000Z.tar.gz", older.ID)
+	backups, err := target.List(ctx, "pg")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, newer.ID, backups[0].ID)
+	assert.Equal(t, "abc123", backups[0].SHA256)
+	assert.Equal(t, int64(7), backups[0].Size)
+
+	r, meta, err := target.Open(ctx, older.ID)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "archive", string(data))
+	assert.Equal(t, "abc123", meta.SHA256)
+
+	require.NoError(t, target.Delete(ctx, older.ID))
+	_, err = os.Stat(filepath.Join(dir, older.ID+".sha256"))
+	assert.True(t, os.IsNotExist(err))
+	_, _, err = target.Open(ctx, older.ID)
+	assert.True(t, cerrdefs.IsNotFound(err))
+	_, _, err = target.Open(ctx, "../"+newer.ID)
+	assert.True(t, cerrdefs.IsNotFound(err))
+}
+
+func TestLocalTarget_SaveKeepsExistingBackup(t *testing.T) {
+	target, err := adapter.NewLocalTarget(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := saveBackup(t, target, "pg", created)
+
+	_, err = target.Save(ctx, "pg", "", created, func(w io.Writer) (*model.Backup, error) {
+		w.Write([]byte("other"))
+		return &model.Backup{Volume: "pg", SHA256: "def456"}, nil
+	})
+	assert.Error(t, err)
+
+	r, meta, err := target.Open(ctx, first.ID)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "archive", string(data))
+	assert.Equal(t, "abc123", meta.SHA256)
+}
+
+func TestParseBackupID(t *testing.T) {
+	created, schedule, ok := adapter.ParseBackupID("pg-20260101T000000.250Z.tar.gz", "pg")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 250e6, time.UTC), created)
+	assert.Empty(t, schedule)
+
+	created, _, ok = adapter.ParseBackupID("pg-20260101T000000Z.tar.gz", "pg")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), created)
+
+	created, schedule, ok = adapter.ParseBackupID("pg-20260101T000000.000Z-0a1b2c3d4e5f.tar.gz", "pg")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), created)
+	assert.Equal(t, "0a1b2c3d4e5f", schedule)
+
+	_, _, ok = adapter.ParseBackupID("pg-data-20260101T000000.000Z.tar.gz", "pg")
+	assert.False(t, ok)
+	_, _, ok = adapter.ParseBackupID("pg-20260101T000000.000Z-.tar.gz", "pg")
+	assert.False(t, ok)
+}
+
+func TestLocalTarget_ScheduledBackup(t *testing.T) {
+	target, err := adapter.NewLocalTarget(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	backup, err := target.Save(ctx, "pg", "0a1b2c3d4e5f", created, func(w io.Writer) (*model.Backup, error) {
+		w.Write([]byte("archive"))
+		return &model.Backup{Volume: "pg", SHA256: "abc123"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pg-20260101T000000.000Z-0a1b2c3d4e5f.tar.gz", backup.ID)
+	assert.Equal(t, "0a1b2c3d4e5f", backup.ScheduleID)
+
+	manual := saveBackup(t, target, "pg", created)
+	backups, err := target.List(ctx, "pg")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	byID := map[string]string{}
+	for _, b := range backups {
+		byID[b.ID] = b.ScheduleID
+	}
+	assert.Equal(t, map[string]string{backup.ID: "0a1b2c3d4e5f", manual.ID: ""}, byID)
+}
@@ -0,0 +1,325 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/stdcopy"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// DefaultHelperImage is the image used for the short-lived containers that
+// mount a volume to browse or write its files. It only needs a shell, find
+// and stat.
+const DefaultHelperImage = "busybox:1.36"
+
+const (
+	helperMountPath = "/volume"
+	helperLabel     = "io.orcahub.volume-helper"
+
+	// listScript prints one line per entry of $1: raw mode in hex, size,
+	// mtime, uid, gid and the name last, so names containing the separator
+	// still parse. Exit codes 3 and 4 flag a missing path and a non-directory.
+	// stagingDir is where Replace extracts an archive before swapping it in.
+	stagingDir = ".orcahub-restore"
+
+	// swapScript replaces everything in $1 but the staging directory $2 with
+	// the staging directory's contents.
+	swapScript = `cd "$1" || exit 1
+for f in .[!.]* ..?* *; do
+	[ "$f" = "$2" ] && continue
+	if [ -e "$f" ] || [ -L "$f" ]; then rm -rf "./$f" || exit 1; fi
+done
+for f in "$2"/.[!.]* "$2"/..?* "$2"/*; do
+	if [ -e "$f" ] || [ -L "$f" ]; then mv "$f" . || exit 1; fi
+done
+rmdir "$2"`
+
+	listScript = `[ -e "$1" ] || [ -L "$1" ] || exit 3; [ -d "$1" ] || exit 4; cd "$1" && find . -mindepth 1 -maxdepth 1 -exec stat -c '%f|%s|%Y|%u|%g|%n' {} +`
+)
+
+// UseHelperImage overrides DefaultHelperImage, e.g. to use a mirror on hosts
+// without access to Docker Hub.
+func (a *VolumeAdapterImpl) UseHelperImage(image string) {
+	a.helperImage = image
+}
+
+func (a *VolumeAdapterImpl) ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error) {
+	stdout, stderr, code, err := a.runHelper(ctx, name, true, []string{"sh", "-c", listScript, "sh", helperPath(dir)})
+	if err != nil {
+		return nil, err
+	}
+	switch code {
+	case 0:
+	case 3:
+		return nil, fmt.Errorf("%s not found in volume %s: %w", dir, name, cerrdefs.ErrNotFound)
+	case 4:
+		return nil, fmt.Errorf("%s in volume %s is not a directory: %w", dir, name, cerrdefs.ErrInvalidArgument)
+	default:
+		return nil, fmt.Errorf("failed to list %s in volume %s: %s", dir, name, strings.TrimSpace(string(stderr)))
+	}
+	return parseListing(stdout, dir), nil
+}
+
+func (a *VolumeAdapterImpl) CopyFrom(ctx context.Context, name, p string) (io.ReadCloser, *model.VolumeFile, error) {
+	id, err := a.createHelper(ctx, name, true, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, stat, err := a.client.CopyFromContainer(ctx, id, helperPath(p))
+	if err != nil {
+		a.removeHelper(id)
+		return nil, nil, fmt.Errorf("failed to read %s from volume %s: %w", p, name, err)
+	}
+	file := &model.VolumeFile{
+		Name:       stat.Name,
+		Path:       p,
+		Type:       fileType(stat.Mode),
+		Size:       stat.Size,
+		Mode:       stat.Mode,
+		ModTime:    stat.Mtime,
+		LinkTarget: strings.TrimPrefix(stat.LinkTarget, helperMountPath),
+	}
+	if p == "/" {
+		file.Name = name
+	}
+	return &helperReader{ReadCloser: reader, remove: func() { a.removeHelper(id) }}, file, nil
+}
+
+func (a *VolumeAdapterImpl) CopyTo(ctx context.Context, name, dir string, archive io.Reader) error {
+	id, err := a.createHelper(ctx, name, false, nil)
+	if err != nil {
+		return err
+	}
+	defer a.removeHelper(id)
+	if err := a.client.CopyToContainer(ctx, id, helperPath(dir), archive, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to write to %s in volume %s: %w", dir, name, err)
+	}
+	return nil
+}
+
+func (a *VolumeAdapterImpl) Replace(ctx context.Context, name string, archive io.Reader) error {
+	staging := helperPath(stagingDir)
+	if err := a.runCommand(ctx, name, "rm", "-rf", staging); err != nil {
+		return fmt.Errorf("failed to prepare restore of volume %s: %w", name, err)
+	}
+	if err := a.runCommand(ctx, name, "mkdir", staging); err != nil {
+		return fmt.Errorf("failed to prepare restore of volume %s: %w", name, err)
+	}
+	if err := a.CopyTo(ctx, name, stagingDir, archive); err != nil {
+		// The volume still holds its old contents; only drop the partial copy.
+		if cleanErr := a.runCommand(context.WithoutCancel(ctx), name, "rm", "-rf", staging); cleanErr != nil {
+			log.Printf("failed to remove staged restore of volume %s: %v", name, cleanErr)
+		}
+		return err
+	}
+	if err := a.runCommand(ctx, name, "sh", "-c", swapScript, "sh", helperMountPath, stagingDir); err != nil {
+		return fmt.Errorf("failed to replace contents of volume %s: %w", name, err)
+	}
+	return nil
+}
+
+// runCommand runs cmd in a writable helper and turns a non-zero exit code
+// into an error carrying its stderr.
+func (a *VolumeAdapterImpl) runCommand(ctx context.Context, name string, cmd ...string) error {
+	_, stderr, code, err := a.runHelper(ctx, name, false, cmd)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("%s exited with %d: %s", cmd[0], code, strings.TrimSpace(string(stderr)))
+	}
+	return nil
+}
+
+// runHelper runs cmd in a helper container with the volume mounted and
+// returns its output and exit code.
+func (a *VolumeAdapterImpl) runHelper(ctx context.Context, name string, readOnly bool, cmd []string) ([]byte, []byte, int64, error) {
+	id, err := a.createHelper(ctx, name, readOnly, cmd)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer a.removeHelper(id)
+
+	if err := a.client.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to start volume helper: %w", err)
+	}
+	var code int64
+	statusCh, errCh := a.client.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		code = status.StatusCode
+	case err := <-errCh:
+		return nil, nil, 0, fmt.Errorf("failed to wait for volume helper: %w", err)
+	}
+
+	logs, err := a.client.ContainerLogs(ctx, id, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to read volume helper output: %w", err)
+	}
+	defer logs.Close()
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, logs); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to read volume helper output: %w", err)
+	}
+	return stdout.Bytes(), stderr.Bytes(), code, nil
+}
+
+// createHelper creates, without starting it, a container mounting the volume
+// at helperMountPath. Copying files only needs a created container, so cmd is
+// nil except when the helper has to run something.
+func (a *VolumeAdapterImpl) createHelper(ctx context.Context, name string, readOnly bool, cmd []string) (string, error) {
+	// Mounting a missing named volume would silently create it.
+	if _, err := a.client.VolumeInspect(ctx, name); err != nil {
+		return "", fmt.Errorf("failed to inspect volume %s: %w", name, err)
+	}
+	if err := a.ensureHelperImage(ctx); err != nil {
+		return "", err
+	}
+	resp, err := a.client.ContainerCreate(ctx,
+		&container.Config{
+			Image:           a.helperImage,
+			Cmd:             cmd,
+			Entrypoint:      []string{},
+			Labels:          map[string]string{helperLabel: name},
+			NetworkDisabled: true,
+		},
+		&container.HostConfig{
+			NetworkMode: "none",
+			Mounts: []mount.Mount{{
+				Type:     mount.TypeVolume,
+				Source:   name,
+				Target:   helperMountPath,
+				ReadOnly: readOnly,
+			}},
+		},
+		nil, nil, "",
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create volume helper for %s: %w", name, err)
+	}
+	return resp.ID, nil
+}
+
+func (a *VolumeAdapterImpl) ensureHelperImage(ctx context.Context) error {
+	if _, err := a.client.ImageInspect(ctx, a.helperImage); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to inspect helper image %s: %w", a.helperImage, err)
+	}
+	reader, err := a.client.ImagePull(ctx, a.helperImage, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull helper image %s: %w", a.helperImage, err)
+	}
+	defer reader.Close()
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
+// removeHelper runs detached from the request context so helpers are cleaned
+// up even when the client disconnects.
+func (a *VolumeAdapterImpl) removeHelper(id string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_ = a.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true})
+}
+
+// helperReader removes the helper container once the archive is consumed.
+type helperReader struct {
+	io.ReadCloser
+	remove func()
+}
+
+func (r *helperReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.remove()
+	return err
+}
+
+func helperPath(p string) string {
+	return path.Join(helperMountPath, p)
+}
+
+func parseListing(out []byte, dir string) []model.VolumeFile {
+	files := []model.VolumeFile{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, "|", 6)
+		if len(fields) != 6 {
+			continue
+		}
+		raw, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[1], 10, 64)
+		mtime, _ := strconv.ParseInt(fields[2], 10, 64)
+		uid, _ := strconv.Atoi(fields[3])
+		gid, _ := strconv.Atoi(fields[4])
+		name := strings.TrimPrefix(fields[5], "./")
+		mode := unixMode(uint32(raw))
+		files = append(files, model.VolumeFile{
+			Name:    name,
+			Path:    path.Join(dir, name),
+			Type:    fileType(mode),
+			Size:    size,
+			Mode:    mode,
+			ModTime: time.Unix(mtime, 0).UTC(),
+			UID:     uid,
+			GID:     gid,
+		})
+	}
+	return files
+}
+
+// unixMode converts a raw st_mode, as printed by stat %f, to an os.FileMode.
+func unixMode(raw uint32) os.FileMode {
+	mode := os.FileMode(raw & 0o777)
+	switch raw & 0o170000 {
+	case 0o040000:
+		mode |= os.ModeDir
+	case 0o120000:
+		mode |= os.ModeSymlink
+	case 0o010000:
+		mode |= os.ModeNamedPipe
+	case 0o140000:
+		mode |= os.ModeSocket
+	case 0o020000:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case 0o060000:
+		mode |= os.ModeDevice
+	}
+	if raw&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if raw&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if raw&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+func fileType(mode os.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		return model.FileTypeFile
+	case mode.IsDir():
+		return model.FileTypeDir
+	case mode&os.ModeSymlink != 0:
+		return model.FileTypeSymlink
+	}
+	return model.FileTypeOther
+}
@@ -0,0 +1,42 @@
+package adapter
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListing(t *testing.T) {
+	out := []byte("41ed|4096|1700000000|999|999|./base\n" +
+		"8180|3|1700000001|999|999|./PG_VERSION\n" +
+		"a1ff|11|1700000002|0|0|./current|log\n" +
+		"not a stat line\n")
+
+	files := parseListing(out, "/pgdata")
+
+	require.Len(t, files, 3)
+	assert.Equal(t, model.VolumeFile{
+		Name:    "base",
+		Path:    "/pgdata/base",
+		Type:    model.FileTypeDir,
+		Size:    4096,
+		Mode:    os.ModeDir | 0o755,
+		ModTime: time.Unix(1700000000, 0).UTC(),
+		UID:     999,
+		GID:     999,
+	}, files[0])
+	assert.Equal(t, model.FileTypeFile, files[1].Type)
+	assert.Equal(t, os.FileMode(0o600), files[1].Mode)
+	assert.Equal(t, "current|log", files[2].Name)
+	assert.Equal(t, model.FileTypeSymlink, files[2].Type)
+}
+
+func TestUnixMode(t *testing.T) {
+	assert.Equal(t, os.ModeDir|os.ModeSticky|0o777, unixMode(0o41777))
+	assert.Equal(t, os.ModeSetuid|0o755, unixMode(0o104755))
+	assert.Equal(t, os.ModeDevice|os.ModeCharDevice|0o666, unixMode(0o20666))
+}
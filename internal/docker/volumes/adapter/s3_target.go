@@ -0,0 +1,180 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// S3Options configure an S3-compatible backup target such as AWS S3 or MinIO.
+type S3Options struct {
+	Endpoint  string // host[:port], without scheme
+	Bucket    string
+	Prefix    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool // plain HTTP, e.g. a local MinIO
+}
+
+// checksumMetadata is the user metadata key of checksum objects that holds
+// the checksum itself, so listings that include metadata need no extra
+// request per backup.
+const checksumMetadata = "Sha256"
+
+// S3Target keeps backups as objects in a bucket, under an optional prefix.
+type S3Target struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+var _ BackupTarget = (*S3Target)(nil)
+
+func NewS3Target(opts S3Options) (*S3Target, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("s3 backup target needs an endpoint and a bucket")
+	}
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: !opts.Insecure,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client for %s: %w", opts.Endpoint, err)
+	}
+	prefix := strings.Trim(opts.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Target{client: client, bucket: opts.Bucket, prefix: prefix}, nil
+}
+
+// Save streams the archive as a multipart upload, since its size is not
+// known up front, and writes the checksum object once the upload succeeded.
+func (t *S3Target) Save(ctx context.Context, volume, schedule string, created time.Time, write func(io.Writer) (*model.Backup, error)) (*model.Backup, error) {
+	id := BackupID(volume, schedule, created)
+	pr, pw := io.Pipe()
+	uploaded := make(chan error, 1)
+	go func() {
+		_, err := t.client.PutObject(ctx, t.bucket, t.prefix+id, pr, -1, minio.PutObjectOptions{ContentType: "application/gzip"})
+		pr.CloseWithError(err)
+		uploaded <- err
+	}()
+
+	backup, err := write(pw)
+	pw.CloseWithError(err)
+	if uploadErr := <-uploaded; err == nil && uploadErr != nil {
+		err = fmt.Errorf("failed to upload backup %s: %w", id, uploadErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := checksumLine(backup.SHA256, id)
+	if _, err := t.client.PutObject(ctx, t.bucket, t.prefix+id+checksumExt, strings.NewReader(checksum), int64(len(checksum)),
+		minio.PutObjectOptions{ContentType: "text/plain", UserMetadata: map[string]string{checksumMetadata: backup.SHA256}}); err != nil {
+		return nil, fmt.Errorf("failed to upload checksum for backup %s: %w", id, err)
+	}
+	backup.ID = id
+	backup.ScheduleID = schedule
+	backup.CreatedAt = created.UTC().Truncate(time.Millisecond)
+	return backup, nil
+}
+
+// List reads checksums from the metadata of the checksum objects, which
+// MinIO includes in listings. Other stores leave them out, and so does List
+// rather than fetching every checksum object; Open always has the checksum.
+func (t *S3Target) List(ctx context.Context, volume string) ([]model.Backup, error) {
+	backups := []model.Backup{}
+	sums := make(map[string]string)
+	opts := minio.ListObjectsOptions{Prefix: t.prefix + volume + "-", WithMetadata: true}
+	for obj := range t.client.ListObjects(ctx, t.bucket, opts) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("failed to list backups of %s: %w", volume, obj.Err)
+		}
+		id := path.Base(obj.Key)
+		if backupID, ok := strings.CutSuffix(id, checksumExt); ok {
+			sums[backupID] = obj.UserMetadataStripped[checksumMetadata]
+			continue
+		}
+		created, schedule, ok := ParseBackupID(id, volume)
+		if !ok {
+			continue
+		}
+		backups = append(backups, model.Backup{
+			ID:         id,
+			Volume:     volume,
+			Size:       obj.Size,
+			ScheduleID: schedule,
+			CreatedAt:  created,
+		})
+	}
+	for i := range backups {
+		backups[i].SHA256 = sums[backups[i].ID]
+	}
+	sortBackups(backups)
+	return backups, nil
+}
+
+func (t *S3Target) Open(ctx context.Context, id string) (io.ReadCloser, *model.Backup, error) {
+	if !validBackupID(id) {
+		return nil, nil, fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+	}
+	obj, err := t.client.GetObject(ctx, t.bucket, t.prefix+id, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open backup %s: %w", id, err)
+	}
+	// GetObject is lazy; Stat surfaces a missing object before any read.
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil, fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+		}
+		return nil, nil, fmt.Errorf("failed to open backup %s: %w", id, err)
+	}
+	backup := &model.Backup{ID: id, Size: info.Size}
+	if backup.SHA256, err = t.checksum(ctx, id); err != nil {
+		obj.Close()
+		return nil, nil, fmt.Errorf("failed to read checksum of backup %s: %w", id, err)
+	}
+	return obj, backup, nil
+}
+
+func (t *S3Target) Delete(ctx context.Context, id string) error {
+	if !validBackupID(id) {
+		return fmt.Errorf("backup %s: %w", id, cerrdefs.ErrNotFound)
+	}
+	for _, key := range []string{t.prefix + id, t.prefix + id + checksumExt} {
+		if err := t.client.RemoveObject(ctx, t.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+			return fmt.Errorf("failed to delete backup %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// checksum returns the recorded checksum of the backup, or "" if there is
+// none.
+func (t *S3Target) checksum(ctx context.Context, id string) (string, error) {
+	obj, err := t.client.GetObject(ctx, t.bucket, t.prefix+id+checksumExt, minio.GetObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(io.LimitReader(obj, 1024))
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return parseChecksum(data), nil
+}
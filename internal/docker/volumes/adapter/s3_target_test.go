@@ -0,0 +1,82 @@
+//go:build integration
+
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
+	"github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestS3Target runs against an S3-compatible endpoint such as a local MinIO:
+//
+//	docker run -d -p 9000:9000 minio/minio server /data
+//	ORCAHUB_TEST_S3_ENDPOINT=localhost:9000 go test -tags integration ./internal/docker/volumes/adapter/...
+func TestS3Target(t *testing.T) {
+	endpoint := os.Getenv("ORCAHUB_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("ORCAHUB_TEST_S3_ENDPOINT is not set")
+	}
+	opts := adapter.S3Options{
+		Endpoint:  endpoint,
+		Bucket:    "orcahub-test-backups",
+		Prefix:    "volumes",
+		AccessKey: envOr("ORCAHUB_TEST_S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("ORCAHUB_TEST_S3_SECRET_KEY", "minioadmin"),
+		Insecure:  true,
+	}
+	ctx := context.Background()
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{Creds: credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, "")})
+	require.NoError(t, err)
+	if exists, _ := client.BucketExists(ctx, opts.Bucket); !exists {
+		require.NoError(t, client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{}))
+	}
+
+	target, err := adapter.NewS3Target(opts)
+	require.NoError(t, err)
+
+	created := time.Now()
+	backup, err := target.Save(ctx, "orcahub-test-volume", "", created, func(w io.Writer) (*model.Backup, error) {
+		w.Write([]byte("archive"))
+		return &model.Backup{Volume: "orcahub-test-volume", Size: 7, SHA256: "abc123"}, nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { target.Delete(context.Background(), backup.ID) })
+
+	backups, err := target.List(ctx, "orcahub-test-volume")
+	require.NoError(t, err)
+	require.NotEmpty(t, backups)
+	assert.Equal(t, backup.ID, backups[0].ID)
+	assert.Equal(t, "abc123", backups[0].SHA256)
+
+	r, meta, err := target.Open(ctx, backup.ID)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	r.Close()
+	assert.Equal(t, "archive", buf.String())
+	assert.Equal(t, "abc123", meta.SHA256)
+
+	require.NoError(t, target.Delete(ctx, backup.ID))
+	_, _, err = target.Open(ctx, backup.ID)
+	assert.True(t, cerrdefs.IsNotFound(err))
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,13 +1,20 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	mappers "github.com/rivernova/orcahub/internal/docker/volumes/api/mappers"
 	requests "github.com/rivernova/orcahub/internal/docker/volumes/api/requests"
+	responses "github.com/rivernova/orcahub/internal/docker/volumes/api/responses"
 	domain "github.com/rivernova/orcahub/internal/docker/volumes/domain"
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/rivernova/orcahub/internal/httputil"
 )
 
 type Handler struct {
@@ -48,9 +55,14 @@ func (h *Handler) Create(c *gin.Context) {
 		Driver:     req.Driver,
 		DriverOpts: req.DriverOpts,
 		Labels:     req.Labels,
+		Preset:     mappers.ToMountPreset(req.Preset),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidVolume) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, mappers.ToVolumeResponse(*result))
@@ -66,10 +78,406 @@ func (h *Handler) Delete(c *gin.Context) {
 }
 
 func (h *Handler) Prune(c *gin.Context) {
-	result, err := h.service.Prune(c.Request.Context())
+	var query requests.PruneVolumesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.service.Prune(c.Request.Context(), model.PruneOptions{
+		Labels:        query.Label,
+		ExcludeLabels: query.ExcludeLabel,
+		All:           query.All,
+		DryRun:        query.DryRun,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, result)
 }
+
+func (h *Handler) ListFiles(c *gin.Context) {
+	var query requests.VolumeFilesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	files, err := h.service.ListFiles(c.Request.Context(), name, query.Path)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToVolumeFilesResponse(name, path.Clean("/"+query.Path), files))
+}
+
+// Download streams a regular file as is and anything else, such as a
+// directory, as a tar archive.
+func (h *Handler) Download(c *gin.Context) {
+	var query requests.VolumeFilesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, reader, err := h.service.Download(c.Request.Context(), c.Param("name"), query.Path)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer reader.Close()
+	if file.Type == model.FileTypeFile {
+		c.DataFromReader(http.StatusOK, file.Size, "application/octet-stream", reader, map[string]string{
+			"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, file.Name),
+		})
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "application/x-tar", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s.tar"`, file.Name),
+	})
+}
+
+// Upload writes the "file" fields of a multipart form into the directory
+// given by the path query parameter.
+func (h *Handler) Upload(c *gin.Context) {
+	var query requests.VolumeFilesRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var files []model.UploadFile
+	for _, header := range form.File["file"] {
+		f, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		files = append(files, model.UploadFile{Name: header.Filename, Size: header.Size, Content: f})
+	}
+	uploaded, err := h.service.Upload(c.Request.Context(), c.Param("name"), query.Path, files)
+	if err != nil {
+		c.JSON(fileErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.UploadFilesResponse{Uploaded: uploaded})
+}
+
+func fileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPath), cerrdefs.IsInvalidArgument(err):
+		return http.StatusBadRequest
+	case cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// checksumTrailer carries the checksum of a streamed backup, which is only
+// known once the whole archive has been sent.
+const checksumTrailer = "X-Checksum-Sha256"
+
+// Backup streams a gzip-compressed tar archive of the volume, or stores it in
+// a backup target when store is set.
+func (h *Handler) Backup(c *gin.Context) {
+	var query requests.BackupVolumeRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	opts := model.BackupOptions{Target: query.Target, StopContainers: query.StopContainers}
+	if query.Store {
+		backup, err := h.service.StoreBackup(c.Request.Context(), name, opts)
+		if err != nil {
+			c.JSON(backupErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, mappers.ToBackupResponse(backup))
+		return
+	}
+
+	w := &lazyWriter{c: c, filename: name + ".tar.gz"}
+	backup, err := h.service.Backup(c.Request.Context(), name, opts, w)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.JSON(backupErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		// Too late for an error status: the archive is left truncated and
+		// without a checksum trailer.
+		c.Error(err)
+		return
+	}
+	w.start()
+	c.Writer.Header().Set(checksumTrailer, backup.SHA256)
+}
+
+func (h *Handler) ListBackups(c *gin.Context) {
+	var query requests.ListBackupsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	backups, err := h.service.ListBackups(c.Request.Context(), c.Param("name"), query.Target)
+	if err != nil {
+		c.JSON(backupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToBackupResponseList(backups))
+}
+
+// Restore extracts an archive into the volume: a stored backup when the
+// backup query parameter is set, otherwise the raw request body or the
+// "file" field of a multipart form.
+func (h *Handler) Restore(c *gin.Context) {
+	var query requests.RestoreVolumeRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	opts := model.RestoreOptions{
+		Target:         query.Target,
+		StopContainers: query.StopContainers,
+		Clean:          query.Clean,
+		SHA256:         query.SHA256,
+		SkipVerify:     query.SkipVerify,
+	}
+	var err error
+	if query.Backup != "" {
+		err = h.service.RestoreBackup(c.Request.Context(), name, query.Backup, opts)
+	} else {
+		var input io.Reader
+		if input, err = httputil.UploadReader(c); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		err = h.service.Restore(c.Request.Context(), name, input, opts)
+	}
+	if err != nil {
+		c.JSON(backupErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// lazyWriter defers the response headers until the first archive bytes, so
+// errors raised before anything is read from the volume still get a JSON
+// error response.
+type lazyWriter struct {
+	c        *gin.Context
+	filename string
+	started  bool
+}
+
+func (w *lazyWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	header := w.c.Writer.Header()
+	header.Set("Content-Type", "application/gzip")
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+	header.Set("Trailer", checksumTrailer)
+	w.c.Status(http.StatusOK)
+}
+
+func (w *lazyWriter) Write(p []byte) (int, error) {
+	w.start()
+	return w.c.Writer.Write(p)
+}
+
+func backupErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBackupsNotConfigured):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, domain.ErrBackupNotFound), cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrChecksumMismatch), errors.Is(err, domain.ErrInvalidArchive):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrChecksumMissing):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) ListSchedules(c *gin.Context) {
+	schedules, err := h.service.ListSchedules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToBackupScheduleResponseList(schedules))
+}
+
+func (h *Handler) CreateSchedule(c *gin.Context) {
+	var req requests.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	schedule, err := h.service.CreateSchedule(c.Request.Context(), model.BackupSchedule{
+		Cron:     req.Cron,
+		Volume:   req.Volume,
+		Selector: req.Selector,
+		Target:   req.Target,
+		Retention: model.Retention{
+			KeepLast:   req.Retention.KeepLast,
+			KeepDaily:  req.Retention.KeepDaily,
+			KeepWeekly: req.Retention.KeepWeekly,
+		},
+		StopContainers: req.StopContainers,
+	})
+	if err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, mappers.ToBackupScheduleResponse(schedule))
+}
+
+func (h *Handler) GetSchedule(c *gin.Context) {
+	schedule, err := h.service.GetSchedule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToBackupScheduleResponse(schedule))
+}
+
+func (h *Handler) DeleteSchedule(c *gin.Context) {
+	if err := h.service.DeleteSchedule(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RunSchedule starts the schedule's backups now; follow them in the run
+// history.
+func (h *Handler) RunSchedule(c *gin.Context) {
+	if err := h.service.RunSchedule(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(scheduleErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (h *Handler) ListBackupRuns(c *gin.Context) {
+	var query requests.BackupRunsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	runs, err := h.service.ListBackupRuns(c.Request.Context(), model.BackupRunFilter{
+		ScheduleID: query.Schedule,
+		Volume:     query.Volume,
+		Status:     query.Status,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mappers.ToBackupRunResponseList(runs))
+}
+
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrScheduleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrScheduleRunning):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// Clone copies the volume into a new one. With stream=true progress is sent
+// as server-sent "progress" events, ending in a "done" or "error" event.
+func (h *Handler) Clone(c *gin.Context) {
+	var query requests.CloneVolumeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req requests.CloneVolumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Param("name")
+	opts := model.CloneOptions{
+		Name:           req.Name,
+		Driver:         req.Driver,
+		DriverOpts:     req.DriverOpts,
+		Labels:         req.Labels,
+		StopContainers: req.StopContainers,
+	}
+	if !query.Stream {
+		result, err := h.service.Clone(c.Request.Context(), name, opts, nil)
+		if err != nil {
+			c.JSON(cloneErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, mappers.ToCloneVolumeResponse(result))
+		return
+	}
+
+	progress := make(chan model.CloneProgress, 16)
+	type outcome struct {
+		result *model.CloneResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := h.service.Clone(c.Request.Context(), name, opts, func(p model.CloneProgress) {
+			// A slow client only misses intermediate updates.
+			select {
+			case progress <- p:
+			default:
+			}
+		})
+		done <- outcome{result, err}
+	}()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		// Flush queued progress before reporting the outcome.
+		select {
+		case p := <-progress:
+			c.SSEvent("progress", mappers.ToCloneProgressResponse(p))
+			return true
+		default:
+		}
+		select {
+		case p := <-progress:
+			c.SSEvent("progress", mappers.ToCloneProgressResponse(p))
+			return true
+		case o := <-done:
+			if o.err != nil {
+				c.SSEvent("error", gin.H{"error": o.err.Error()})
+				return false
+			}
+			c.SSEvent("done", mappers.ToCloneVolumeResponse(o.result))
+			return false
+		}
+	})
+}
+
+func cloneErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidClone):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrVolumeExists):
+		return http.StatusConflict
+	case cerrdefs.IsNotFound(err):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
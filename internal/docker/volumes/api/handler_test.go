@@ -5,15 +5,24 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/gin-gonic/gin"
 	volumeapi "github.com/rivernova/orcahub/internal/docker/volumes/api"
+	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
 	"github.com/rivernova/orcahub/internal/docker/volumes/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func init() { gin.SetMode(gin.TestMode) }
@@ -42,11 +51,113 @@ func (m *mockVolumeService) Delete(ctx context.Context, name string) error {
 	return m.Called(ctx, name).Error(0)
 }
 
-func (m *mockVolumeService) Prune(ctx context.Context) (model.PruneResult, error) {
-	args := m.Called(ctx)
+func (m *mockVolumeService) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := m.Called(ctx, opts)
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
 
+func (m *mockVolumeService) ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error) {
+	args := m.Called(ctx, name, dir)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VolumeFile), args.Error(1)
+}
+
+func (m *mockVolumeService) Download(ctx context.Context, name, path string) (*model.VolumeFile, io.ReadCloser, error) {
+	args := m.Called(ctx, name, path)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*model.VolumeFile), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *mockVolumeService) Upload(ctx context.Context, name, dir string, files []model.UploadFile) ([]string, error) {
+	args := m.Called(ctx, name, dir, files)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockVolumeService) Backup(ctx context.Context, name string, opts model.BackupOptions, w io.Writer) (*model.Backup, error) {
+	args := m.Called(ctx, name, opts, w)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	w.Write([]byte("archive"))
+	return args.Get(0).(*model.Backup), args.Error(1)
+}
+
+func (m *mockVolumeService) StoreBackup(ctx context.Context, name string, opts model.BackupOptions) (*model.Backup, error) {
+	args := m.Called(ctx, name, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Backup), args.Error(1)
+}
+
+func (m *mockVolumeService) ListBackups(ctx context.Context, name, target string) ([]model.Backup, error) {
+	args := m.Called(ctx, name, target)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Backup), args.Error(1)
+}
+
+func (m *mockVolumeService) Restore(ctx context.Context, name string, archive io.Reader, opts model.RestoreOptions) error {
+	return m.Called(ctx, name, archive, opts).Error(0)
+}
+
+func (m *mockVolumeService) RestoreBackup(ctx context.Context, name, id string, opts model.RestoreOptions) error {
+	return m.Called(ctx, name, id, opts).Error(0)
+}
+
+func (m *mockVolumeService) CreateSchedule(ctx context.Context, schedule model.BackupSchedule) (*model.BackupSchedule, error) {
+	args := m.Called(ctx, schedule)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BackupSchedule), args.Error(1)
+}
+
+func (m *mockVolumeService) ListSchedules(ctx context.Context) ([]model.BackupSchedule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.BackupSchedule), args.Error(1)
+}
+
+func (m *mockVolumeService) GetSchedule(ctx context.Context, id string) (*model.BackupSchedule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BackupSchedule), args.Error(1)
+}
+
+func (m *mockVolumeService) DeleteSchedule(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockVolumeService) RunSchedule(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockVolumeService) ListBackupRuns(ctx context.Context, filter model.BackupRunFilter) ([]model.BackupRun, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]model.BackupRun), args.Error(1)
+}
+
+func (m *mockVolumeService) Clone(ctx context.Context, name string, opts model.CloneOptions, progress func(model.CloneProgress)) (*model.CloneResult, error) {
+	args := m.Called(ctx, name, opts)
+	if progress != nil {
+		progress(model.CloneProgress{Phase: model.ClonePhaseCopying, FilesCopied: 1, BytesCopied: 512, BytesTotal: 2048})
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.CloneResult), args.Error(1)
+}
+
 func setupVolumeRouter(svc *mockVolumeService) *gin.Engine {
 	r := gin.New()
 	h := volumeapi.NewHandler(svc)
@@ -55,6 +166,19 @@ func setupVolumeRouter(svc *mockVolumeService) *gin.Engine {
 	r.POST("/volumes", h.Create)
 	r.DELETE("/volumes/:name", h.Delete)
 	r.POST("/volumes/prune", h.Prune)
+	r.GET("/volumes/:name/files", h.ListFiles)
+	r.GET("/volumes/:name/files/download", h.Download)
+	r.POST("/volumes/:name/files", h.Upload)
+	r.POST("/volumes/:name/backup", h.Backup)
+	r.GET("/volumes/:name/backups", h.ListBackups)
+	r.POST("/volumes/:name/restore", h.Restore)
+	r.GET("/volumes/schedules", h.ListSchedules)
+	r.POST("/volumes/schedules", h.CreateSchedule)
+	r.GET("/volumes/schedules/:id", h.GetSchedule)
+	r.DELETE("/volumes/schedules/:id", h.DeleteSchedule)
+	r.POST("/volumes/schedules/:id/run", h.RunSchedule)
+	r.GET("/volumes/backup-runs", h.ListBackupRuns)
+	r.POST("/volumes/:name/clone", h.Clone)
 	return r
 }
 
@@ -105,6 +229,29 @@ func TestVolumeHandler_Inspect_OK(t *testing.T) {
 	assert.Equal(t, "postgres-data", resp["name"])
 }
 
+func TestVolumeHandler_Inspect_Mount(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Inspect", mock.Anything, "pg-nfs").Return(&model.Volume{
+		Name:    "pg-nfs",
+		Driver:  "local",
+		Options: map[string]string{"type": "nfs", "device": ":/exports/pg", "o": "addr=10.0.0.5,nfsvers=4.1"},
+		Mount:   &model.MountInfo{Type: model.MountTypeNFS, Server: "10.0.0.5", Path: "/exports/pg", Options: []string{"nfsvers=4.1"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/pg-nfs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	mount := resp["mount"].(map[string]interface{})
+	assert.Equal(t, "nfs", mount["type"])
+	assert.Equal(t, "10.0.0.5", mount["server"])
+	assert.Equal(t, "/exports/pg", mount["path"])
+}
+
 func TestVolumeHandler_Inspect_NotFound(t *testing.T) {
 	svc := &mockVolumeService{}
 	r := setupVolumeRouter(svc)
@@ -148,6 +295,47 @@ func TestVolumeHandler_Create_BadRequest(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestVolumeHandler_Create_Preset(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Create", mock.Anything, model.CreateVolumeOptions{
+		Name:   "cache",
+		Preset: &model.MountPreset{Type: model.MountTypeTmpfs, Size: "64m"},
+	}).Return(&model.Volume{Name: "cache", Driver: "local"}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes", strings.NewReader(`{"name":"cache","preset":{"type":"tmpfs","size":"64m"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestVolumeHandler_Create_InvalidPreset(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Create", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: nfs needs a server", domain.ErrInvalidVolume))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes", strings.NewReader(`{"name":"pg","preset":{"type":"nfs","path":"/exports"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Unknown preset types are rejected before reaching the service.
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/volumes", strings.NewReader(`{"name":"pg","preset":{"type":"ceph"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNumberOfCalls(t, "Create", 1)
+}
+
 func TestVolumeHandler_Delete_OK(t *testing.T) {
 	svc := &mockVolumeService{}
 	r := setupVolumeRouter(svc)
@@ -177,7 +365,7 @@ func TestVolumeHandler_Prune_OK(t *testing.T) {
 	r := setupVolumeRouter(svc)
 
 	expected := model.PruneResult{Deleted: []string{"v1"}, SpaceReclaimed: 2048}
-	svc.On("Prune", mock.Anything).Return(expected, nil)
+	svc.On("Prune", mock.Anything, model.PruneOptions{}).Return(expected, nil)
 
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/prune", nil))
@@ -187,3 +375,448 @@ func TestVolumeHandler_Prune_OK(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, float64(2048), resp["space_reclaimed"])
 }
+
+func TestVolumeHandler_Prune_DryRun(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Prune", mock.Anything, model.PruneOptions{
+		Labels:        []string{"env=dev", "team"},
+		ExcludeLabels: []string{"keep"},
+		All:           true,
+		DryRun:        true,
+	}).Return(model.PruneResult{
+		Deleted:        []string{"pg-data"},
+		SpaceReclaimed: 5000,
+		DryRun:         true,
+		Volumes:        []model.PrunedVolume{{Name: "pg-data", Driver: "local", Size: 5000}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/prune?label=env%3Ddev&label=team&exclude_label=keep&all=true&dry_run=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, true, resp["dry_run"])
+	assert.Equal(t, float64(5000), resp["volumes"].([]interface{})[0].(map[string]interface{})["size"])
+}
+
+func TestVolumeHandler_ListFiles_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("ListFiles", mock.Anything, "postgres-data", "/pgdata").Return([]model.VolumeFile{
+		{Name: "base", Path: "/pgdata/base", Type: model.FileTypeDir, Size: 4096, Mode: 0o700 | os.ModeDir, ModTime: time.Unix(0, 0)},
+		{Name: "PG_VERSION", Path: "/pgdata/PG_VERSION", Type: model.FileTypeFile, Size: 3, Mode: 0o600, UID: 999},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/postgres-data/files?path=/pgdata", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Path  string                   `json:"path"`
+		Files []map[string]interface{} `json:"files"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "/pgdata", resp.Path)
+	assert.Len(t, resp.Files, 2)
+	assert.Equal(t, "dir", resp.Files[0]["type"])
+	assert.Equal(t, "0700", resp.Files[0]["mode"])
+	assert.Equal(t, "drwx------", resp.Files[0]["permissions"])
+	assert.Equal(t, float64(3), resp.Files[1]["size"])
+}
+
+func TestVolumeHandler_ListFiles_NotFound(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("ListFiles", mock.Anything, "postgres-data", "/missing").
+		Return(nil, fmt.Errorf("/missing not found in volume postgres-data: %w", cerrdefs.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/postgres-data/files?path=/missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVolumeHandler_Download_File(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Download", mock.Anything, "app-config", "/app.conf").Return(
+		&model.VolumeFile{Name: "app.conf", Type: model.FileTypeFile, Size: 9},
+		io.NopCloser(strings.NewReader("debug=off")), nil,
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/app-config/files/download?path=/app.conf", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="app.conf"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "debug=off", w.Body.String())
+}
+
+func TestVolumeHandler_Download_Directory(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Download", mock.Anything, "app-config", "/").Return(
+		&model.VolumeFile{Name: "app-config", Type: model.FileTypeDir},
+		io.NopCloser(strings.NewReader("tar")), nil,
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/app-config/files/download?path=/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-tar", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="app-config.tar"`, w.Header().Get("Content-Disposition"))
+}
+
+func TestVolumeHandler_Upload_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Upload", mock.Anything, "app-config", "/conf.d", mock.MatchedBy(func(files []model.UploadFile) bool {
+		return len(files) == 1 && files[0].Name == "site.conf" && files[0].Size == 6
+	})).Return([]string{"/conf.d/site.conf"}, nil)
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "site.conf")
+	part.Write([]byte("listen"))
+	mw.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/app-config/files?path=/conf.d", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string][]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, []string{"/conf.d/site.conf"}, resp["uploaded"])
+}
+
+func TestVolumeHandler_Upload_InvalidName(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Upload", mock.Anything, "app-config", "", mock.Anything).
+		Return(nil, fmt.Errorf("%w: file name %q", domain.ErrInvalidPath, ".."))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "..")
+	part.Write([]byte("x"))
+	mw.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/app-config/files", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVolumeHandler_Backup_Stream(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Backup", mock.Anything, "pg-data", model.BackupOptions{StopContainers: true}, mock.Anything).
+		Return(&model.Backup{Volume: "pg-data", SHA256: "abc123"}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/pg-data/backup?stop_containers=true", nil))
+
+	res := w.Result()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/gzip", res.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="pg-data.tar.gz"`, res.Header.Get("Content-Disposition"))
+	assert.Equal(t, "archive", w.Body.String())
+	assert.Equal(t, "abc123", res.Trailer.Get("X-Checksum-Sha256"))
+}
+
+func TestVolumeHandler_Backup_NotFound(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Backup", mock.Anything, "nope", model.BackupOptions{}, mock.Anything).
+		Return(nil, fmt.Errorf("failed to inspect volume nope: %w", cerrdefs.ErrNotFound))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/nope/backup", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVolumeHandler_Backup_Store(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("StoreBackup", mock.Anything, "pg-data", model.BackupOptions{}).Return(&model.Backup{
+		ID:        "pg-data-20260101T000000Z.tar.gz",
+		Volume:    "pg-data",
+		Size:      512,
+		SHA256:    "abc123",
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/pg-data/backup?store=true", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "pg-data-20260101T000000Z.tar.gz", resp["id"])
+	assert.Equal(t, "abc123", resp["sha256"])
+	assert.Equal(t, "2026-01-01T00:00:00Z", resp["created_at"])
+}
+
+func TestVolumeHandler_Backup_StoreNotConfigured(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("StoreBackup", mock.Anything, "pg-data", model.BackupOptions{}).Return(nil, domain.ErrBackupsNotConfigured)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/pg-data/backup?store=true", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestVolumeHandler_ListBackups_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("ListBackups", mock.Anything, "pg-data", "s3").Return([]model.Backup{
+		{ID: "pg-data-20260102T000000Z.tar.gz", Volume: "pg-data"},
+		{ID: "pg-data-20260101T000000Z.tar.gz", Volume: "pg-data"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/pg-data/backups?target=s3", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Len(t, resp, 2)
+}
+
+func TestVolumeHandler_Restore_Upload(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	opts := model.RestoreOptions{Clean: true, SHA256: "abc123"}
+	svc.On("Restore", mock.Anything, "pg-data", mock.Anything, opts).Return(nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/pg-data/restore?clean=true&sha256=abc123", strings.NewReader("archive"))
+	req.Header.Set("Content-Type", "application/gzip")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestVolumeHandler_Restore_StoredBackup(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("RestoreBackup", mock.Anything, "pg-data", "pg-data-20260101T000000Z.tar.gz", model.RestoreOptions{StopContainers: true}).Return(nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/pg-data/restore?backup=pg-data-20260101T000000Z.tar.gz&stop_containers=true", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestVolumeHandler_Restore_ChecksumMismatch(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Restore", mock.Anything, "pg-data", mock.Anything, model.RestoreOptions{SHA256: "abc123"}).
+		Return(fmt.Errorf("%w: archive has sha256 def456, expected abc123", domain.ErrChecksumMismatch))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/pg-data/restore?sha256=abc123", strings.NewReader("archive")))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVolumeHandler_CreateSchedule_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	expected := model.BackupSchedule{
+		Cron:      "0 3 * * *",
+		Selector:  map[string]string{"backup": "nightly"},
+		Target:    "s3",
+		Retention: model.Retention{KeepLast: 3, KeepDaily: 7, KeepWeekly: 4},
+	}
+	created := expected
+	created.ID = "a1b2c3"
+	created.CreatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	created.NextRun = time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	svc.On("CreateSchedule", mock.Anything, expected).Return(&created, nil)
+
+	body := `{"cron":"0 3 * * *","selector":{"backup":"nightly"},"target":"s3","retention":{"keep_last":3,"keep_daily":7,"keep_weekly":4}}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/schedules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "a1b2c3", resp["id"])
+	assert.Equal(t, "2026-01-01T03:00:00Z", resp["next_run"])
+	assert.Equal(t, float64(7), resp["retention"].(map[string]interface{})["keep_daily"])
+}
+
+func TestVolumeHandler_CreateSchedule_Invalid(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("CreateSchedule", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: exactly one of volume and selector is required", domain.ErrInvalidSchedule))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/schedules", strings.NewReader(`{"cron":"@daily"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVolumeHandler_RunSchedule_NotFound(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("RunSchedule", mock.Anything, "nope").Return(fmt.Errorf("%w: nope", domain.ErrScheduleNotFound))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/schedules/nope/run", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVolumeHandler_RunSchedule_AlreadyRunning(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("RunSchedule", mock.Anything, "nightly").Return(fmt.Errorf("%w: nightly", domain.ErrScheduleRunning))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/volumes/schedules/nightly/run", nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestVolumeHandler_ListBackupRuns_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	started := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	svc.On("ListBackupRuns", mock.Anything, model.BackupRunFilter{ScheduleID: "a1b2c3", Status: "succeeded"}).Return([]model.BackupRun{{
+		ID:         "r1",
+		ScheduleID: "a1b2c3",
+		Volume:     "pg-data",
+		Target:     "local",
+		Status:     model.BackupRunSucceeded,
+		BackupID:   "pg-data-20260101T030000Z.tar.gz",
+		Size:       2048,
+		StartedAt:  started,
+		FinishedAt: started.Add(1500 * time.Millisecond),
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/backup-runs?schedule=a1b2c3&status=succeeded", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	require.Len(t, resp, 1)
+	assert.Equal(t, 1.5, resp[0]["duration_seconds"])
+	assert.Equal(t, float64(2048), resp[0]["size"])
+}
+
+func TestVolumeHandler_ListBackupRuns_InvalidStatus(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/volumes/backup-runs?status=exploded", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVolumeHandler_Clone_OK(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Clone", mock.Anything, "pg-data", model.CloneOptions{Name: "pg-data-nfs", DriverOpts: map[string]string{"type": "nfs"}}).
+		Return(&model.CloneResult{
+			Source:      "pg-data",
+			Volume:      &model.Volume{Name: "pg-data-nfs", Driver: "local"},
+			FilesCopied: 3,
+			BytesCopied: 2048,
+			Duration:    2 * time.Second,
+		}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/pg-data/clone", strings.NewReader(`{"name":"pg-data-nfs","driver_opts":{"type":"nfs"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "pg-data", resp["source"])
+	assert.Equal(t, "pg-data-nfs", resp["volume"].(map[string]interface{})["name"])
+	assert.Equal(t, float64(2), resp["duration_seconds"])
+}
+
+func TestVolumeHandler_Clone_Exists(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Clone", mock.Anything, "pg-data", model.CloneOptions{Name: "pg-backup"}).
+		Return(nil, fmt.Errorf("%w: pg-backup", domain.ErrVolumeExists))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/volumes/pg-data/clone", strings.NewReader(`{"name":"pg-backup"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// streamRecorder lets gin's Context.Stream run against httptest.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (streamRecorder) CloseNotify() <-chan bool { return make(chan bool) }
+
+func TestVolumeHandler_Clone_Stream(t *testing.T) {
+	svc := &mockVolumeService{}
+	r := setupVolumeRouter(svc)
+
+	svc.On("Clone", mock.Anything, "pg-data", model.CloneOptions{Name: "pg-copy"}).
+		Return(&model.CloneResult{Source: "pg-data", Volume: &model.Volume{Name: "pg-copy"}}, nil)
+
+	w := streamRecorder{httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodPost, "/volumes/pg-data/clone?stream=true", strings.NewReader(`{"name":"pg-copy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+	body := w.Body.String()
+	assert.Contains(t, body, "event:progress")
+	assert.Contains(t, body, `"percent":25`)
+	assert.Contains(t, body, "event:done")
+}
@@ -1,6 +1,11 @@
 package mappers
 
 import (
+	"fmt"
+	"os"
+	"time"
+
+	requests "github.com/rivernova/orcahub/internal/docker/volumes/api/requests"
 	responses "github.com/rivernova/orcahub/internal/docker/volumes/api/responses"
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
 )
@@ -21,13 +26,205 @@ func ToVolumeResponse(v model.Volume) responses.VolumeResponse {
 		Labels:     v.Labels,
 		Scope:      v.Scope,
 		CreatedAt:  v.CreatedAt,
+		Size:       v.Size,
+		RefCount:   v.RefCount,
+		InUse:      v.InUse,
+		Containers: toVolumeMountResponses(v.Containers),
 	}
 }
 
+func toVolumeMountResponses(ms []model.VolumeMount) []responses.VolumeMountResponse {
+	result := make([]responses.VolumeMountResponse, 0, len(ms))
+	for _, m := range ms {
+		result = append(result, responses.VolumeMountResponse{
+			ContainerID:   m.ContainerID,
+			ContainerName: m.ContainerName,
+			State:         m.State,
+			MountPath:     m.Destination,
+			RW:            m.RW,
+		})
+	}
+	return result
+}
+
 func ToVolumeInspectResponse(v *model.Volume) *responses.VolumeInspectResponse {
 	return &responses.VolumeInspectResponse{
 		VolumeResponse: ToVolumeResponse(*v),
-		Options:        v.Options,
+		Options:        v.RedactedOptions(),
 		Status:         v.Status,
+		Mount:          toMountInfoResponse(v.Mount),
+	}
+}
+
+func toMountInfoResponse(m *model.MountInfo) *responses.MountInfoResponse {
+	if m == nil {
+		return nil
+	}
+	options := m.Options
+	if options == nil {
+		options = []string{}
+	}
+	return &responses.MountInfoResponse{
+		Type:     m.Type,
+		Server:   m.Server,
+		Path:     m.Path,
+		Username: m.Username,
+		ReadOnly: m.ReadOnly,
+		Options:  options,
+	}
+}
+
+func ToMountPreset(r *requests.VolumePresetRequest) *model.MountPreset {
+	if r == nil {
+		return nil
+	}
+	return &model.MountPreset{
+		Type:     r.Type,
+		Server:   r.Server,
+		Path:     r.Path,
+		Version:  r.Version,
+		Username: r.Username,
+		Password: r.Password,
+		Domain:   r.Domain,
+		Size:     r.Size,
+		Mode:     r.Mode,
+		UID:      r.UID,
+		GID:      r.GID,
+		ReadOnly: r.ReadOnly,
+		Options:  r.Options,
+	}
+}
+
+func ToVolumeFilesResponse(volume, dir string, files []model.VolumeFile) responses.VolumeFilesResponse {
+	result := make([]responses.VolumeFileResponse, 0, len(files))
+	for _, f := range files {
+		result = append(result, responses.VolumeFileResponse{
+			Name:        f.Name,
+			Path:        f.Path,
+			Type:        f.Type,
+			Size:        f.Size,
+			Mode:        octalMode(f.Mode),
+			Permissions: f.Mode.String(),
+			ModifiedAt:  f.ModTime.Format(time.RFC3339),
+			UID:         f.UID,
+			GID:         f.GID,
+		})
+	}
+	return responses.VolumeFilesResponse{Volume: volume, Path: dir, Files: result}
+}
+
+// octalMode formats the permission bits the way chmod takes them, e.g. "0755".
+func octalMode(mode os.FileMode) string {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
+
+func ToBackupResponse(b *model.Backup) responses.BackupResponse {
+	return responses.BackupResponse{
+		ID:         b.ID,
+		Volume:     b.Volume,
+		Target:     b.Target,
+		Size:       b.Size,
+		SHA256:     b.SHA256,
+		ScheduleID: b.ScheduleID,
+		CreatedAt:  b.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func ToBackupResponseList(bs []model.Backup) []responses.BackupResponse {
+	result := make([]responses.BackupResponse, 0, len(bs))
+	for i := range bs {
+		result = append(result, ToBackupResponse(&bs[i]))
+	}
+	return result
+}
+
+func ToBackupScheduleResponse(s *model.BackupSchedule) responses.BackupScheduleResponse {
+	return responses.BackupScheduleResponse{
+		ID:       s.ID,
+		Cron:     s.Cron,
+		Volume:   s.Volume,
+		Selector: s.Selector,
+		Target:   s.Target,
+		Retention: responses.RetentionResponse{
+			KeepLast:   s.Retention.KeepLast,
+			KeepDaily:  s.Retention.KeepDaily,
+			KeepWeekly: s.Retention.KeepWeekly,
+		},
+		StopContainers: s.StopContainers,
+		CreatedAt:      s.CreatedAt.UTC().Format(time.RFC3339),
+		NextRun:        formatTime(s.NextRun),
+	}
+}
+
+func ToBackupScheduleResponseList(ss []model.BackupSchedule) []responses.BackupScheduleResponse {
+	result := make([]responses.BackupScheduleResponse, 0, len(ss))
+	for i := range ss {
+		result = append(result, ToBackupScheduleResponse(&ss[i]))
+	}
+	return result
+}
+
+func ToBackupRunResponseList(runs []model.BackupRun) []responses.BackupRunResponse {
+	result := make([]responses.BackupRunResponse, 0, len(runs))
+	for _, r := range runs {
+		result = append(result, responses.BackupRunResponse{
+			ID:              r.ID,
+			ScheduleID:      r.ScheduleID,
+			Volume:          r.Volume,
+			Target:          r.Target,
+			Status:          r.Status,
+			BackupID:        r.BackupID,
+			Size:            r.Size,
+			SHA256:          r.SHA256,
+			StartedAt:       r.StartedAt.UTC().Format(time.RFC3339),
+			FinishedAt:      formatTime(r.FinishedAt),
+			DurationSeconds: r.Duration().Seconds(),
+			Error:           r.Error,
+			Pruned:          r.Pruned,
+		})
+	}
+	return result
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func ToCloneProgressResponse(p model.CloneProgress) responses.CloneProgressResponse {
+	resp := responses.CloneProgressResponse{
+		Phase:       p.Phase,
+		FilesCopied: p.FilesCopied,
+		BytesCopied: p.BytesCopied,
+		BytesTotal:  p.BytesTotal,
+	}
+	if p.BytesTotal > 0 {
+		resp.Percent = min(100, float64(p.BytesCopied)*100/float64(p.BytesTotal))
+	}
+	if p.Phase == model.ClonePhaseDone {
+		resp.Percent = 100
+	}
+	return resp
+}
+
+func ToCloneVolumeResponse(r *model.CloneResult) responses.CloneVolumeResponse {
+	return responses.CloneVolumeResponse{
+		Source:          r.Source,
+		Volume:          ToVolumeResponse(*r.Volume),
+		FilesCopied:     r.FilesCopied,
+		BytesCopied:     r.BytesCopied,
+		DurationSeconds: r.Duration.Seconds(),
 	}
 }
@@ -26,6 +26,28 @@ func TestToVolumeResponse(t *testing.T) {
 	assert.Equal(t, "local", resp.Scope)
 }
 
+func TestToVolumeResponse_Usage(t *testing.T) {
+	v := model.Volume{
+		Name:     "postgres-data",
+		Size:     4096,
+		RefCount: 1,
+		InUse:    true,
+		Containers: []model.VolumeMount{
+			{VolumeName: "postgres-data", ContainerID: "c1", ContainerName: "pg", State: "running", Destination: "/var/lib/postgresql/data", RW: true},
+		},
+	}
+
+	resp := mappers.ToVolumeResponse(v)
+
+	assert.Equal(t, int64(4096), resp.Size)
+	assert.Equal(t, int64(1), resp.RefCount)
+	assert.True(t, resp.InUse)
+	assert.Len(t, resp.Containers, 1)
+	assert.Equal(t, "pg", resp.Containers[0].ContainerName)
+	assert.Equal(t, "/var/lib/postgresql/data", resp.Containers[0].MountPath)
+	assert.True(t, resp.Containers[0].RW)
+}
+
 func TestToVolumeInspectResponse(t *testing.T) {
 	v := &model.Volume{
 		Name:    "my-vol",
@@ -41,6 +63,19 @@ func TestToVolumeInspectResponse(t *testing.T) {
 	assert.Equal(t, "2024-01-01", resp.Status["MountedAt"])
 }
 
+func TestToVolumeInspectResponse_RedactsPassword(t *testing.T) {
+	v := &model.Volume{
+		Name:    "media",
+		Driver:  "local",
+		Options: map[string]string{"type": "cifs", "o": "addr=nas,password=hunter2"},
+	}
+
+	resp := mappers.ToVolumeInspectResponse(v)
+
+	assert.Equal(t, "addr=nas,password=********", resp.Options["o"])
+	assert.Equal(t, "addr=nas,password=hunter2", v.Options["o"])
+}
+
 func TestToVolumeResponseList(t *testing.T) {
 	volumes := []model.Volume{
 		{Name: "vol1", Driver: "local"},
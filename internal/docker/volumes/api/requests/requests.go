@@ -1,8 +1,102 @@
 package requests
 
 type CreateVolumeRequest struct {
-	Name       string            `json:"name" binding:"required"`
-	Driver     string            `json:"driver"` // default "local"
-	DriverOpts map[string]string `json:"driver_opts"`
-	Labels     map[string]string `json:"labels"`
+	Name       string               `json:"name" binding:"required"`
+	Driver     string               `json:"driver"` // default "local"
+	DriverOpts map[string]string    `json:"driver_opts"`
+	Labels     map[string]string    `json:"labels"`
+	Preset     *VolumePresetRequest `json:"preset"`
+}
+
+// VolumePresetRequest mounts an NFS export, a CIFS share, a host directory
+// or a tmpfs with the local driver instead of raw driver_opts. The password
+// is kept in the volume's driver options, readable through the Docker API;
+// use a "credentials=<file>" option instead to keep it on the host.
+type VolumePresetRequest struct {
+	Type     string   `json:"type" binding:"required,oneof=nfs cifs bind tmpfs"`
+	Server   string   `json:"server"`
+	Path     string   `json:"path"`
+	Version  string   `json:"version"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Domain   string   `json:"domain"`
+	Size     string   `json:"size"`
+	Mode     string   `json:"mode"`
+	UID      *int     `json:"uid"`
+	GID      *int     `json:"gid"`
+	ReadOnly bool     `json:"read_only"`
+	Options  []string `json:"options"`
+}
+
+// PruneVolumesRequest filters take "key" or "key=value" and may repeat.
+type PruneVolumesRequest struct {
+	Label        []string `form:"label"`
+	ExcludeLabel []string `form:"exclude_label"`
+	// All includes named volumes; by default only anonymous ones go.
+	All    bool `form:"all"`
+	DryRun bool `form:"dry_run"`
+}
+
+type VolumeFilesRequest struct {
+	Path string `form:"path"` // default "/"
+}
+
+type BackupVolumeRequest struct {
+	// Store saves the backup in the backup directory instead of streaming it.
+	Store bool `form:"store"`
+	// Target names where a stored backup goes; empty means "local".
+	Target         string `form:"target"`
+	StopContainers bool   `form:"stop_containers"`
+}
+
+type ListBackupsRequest struct {
+	Target string `form:"target"`
+}
+
+type RestoreVolumeRequest struct {
+	// Backup restores a stored backup by ID instead of the request body.
+	Backup         string `form:"backup"`
+	Target         string `form:"target"`
+	SHA256         string `form:"sha256"`
+	Clean          bool   `form:"clean"`
+	StopContainers bool   `form:"stop_containers"`
+	// SkipVerify allows restoring a stored backup without a checksum.
+	SkipVerify bool `form:"skip_verify"`
+}
+
+type RetentionRequest struct {
+	KeepLast   int `json:"keep_last"`
+	KeepDaily  int `json:"keep_daily"`
+	KeepWeekly int `json:"keep_weekly"`
+}
+
+// CreateScheduleRequest takes either a volume name or a label selector;
+// selector values may be empty to match on the key alone.
+type CreateScheduleRequest struct {
+	Cron           string            `json:"cron" binding:"required"`
+	Volume         string            `json:"volume"`
+	Selector       map[string]string `json:"selector"`
+	Target         string            `json:"target"` // default "local"
+	Retention      RetentionRequest  `json:"retention"`
+	StopContainers bool              `json:"stop_containers"`
+}
+
+type BackupRunsRequest struct {
+	Schedule string `form:"schedule"`
+	Volume   string `form:"volume"`
+	Status   string `form:"status" binding:"omitempty,oneof=running succeeded failed"`
+}
+
+type CloneVolumeRequest struct {
+	Name           string            `json:"name" binding:"required"`
+	Driver         string            `json:"driver"` // default: the source's driver
+	DriverOpts     map[string]string `json:"driver_opts"`
+	Labels         map[string]string `json:"labels"`
+	StopContainers bool              `json:"stop_containers"`
+}
+
+type CloneVolumeQuery struct {
+	// Stream reports progress as server-sent events instead of answering
+	// once the clone is done.
+	Stream bool `form:"stream"`
 }
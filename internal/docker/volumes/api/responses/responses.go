@@ -1,16 +1,120 @@
 package responses
 
 type VolumeResponse struct {
-	Name       string            `json:"name"`
-	Driver     string            `json:"driver"`
-	Mountpoint string            `json:"mountpoint"`
-	Labels     map[string]string `json:"labels"`
-	Scope      string            `json:"scope"`
-	CreatedAt  string            `json:"created_at"`
+	Name       string                `json:"name"`
+	Driver     string                `json:"driver"`
+	Mountpoint string                `json:"mountpoint"`
+	Labels     map[string]string     `json:"labels"`
+	Scope      string                `json:"scope"`
+	CreatedAt  string                `json:"created_at"`
+	Size       int64                 `json:"size"`
+	RefCount   int64                 `json:"ref_count"`
+	InUse      bool                  `json:"in_use"`
+	Containers []VolumeMountResponse `json:"containers"`
+}
+
+type VolumeMountResponse struct {
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	State         string `json:"state"`
+	MountPath     string `json:"mount_path"`
+	RW            bool   `json:"rw"`
 }
 
 type VolumeInspectResponse struct {
 	VolumeResponse
 	Options map[string]string      `json:"options"`
 	Status  map[string]interface{} `json:"status"`
+	Mount   *MountInfoResponse     `json:"mount,omitempty"`
+}
+
+type MountInfoResponse struct {
+	Type     string   `json:"type"`
+	Server   string   `json:"server,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Username string   `json:"username,omitempty"`
+	ReadOnly bool     `json:"read_only"`
+	Options  []string `json:"options"`
+}
+
+type VolumeFileResponse struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	Size        int64  `json:"size"`
+	Mode        string `json:"mode"`
+	Permissions string `json:"permissions"`
+	ModifiedAt  string `json:"modified_at"`
+	UID         int    `json:"uid"`
+	GID         int    `json:"gid"`
+}
+
+type VolumeFilesResponse struct {
+	Volume string               `json:"volume"`
+	Path   string               `json:"path"`
+	Files  []VolumeFileResponse `json:"files"`
+}
+
+type UploadFilesResponse struct {
+	Uploaded []string `json:"uploaded"`
+}
+
+type BackupResponse struct {
+	ID         string `json:"id,omitempty"`
+	Volume     string `json:"volume"`
+	Target     string `json:"target,omitempty"`
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	ScheduleID string `json:"schedule_id,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type RetentionResponse struct {
+	KeepLast   int `json:"keep_last"`
+	KeepDaily  int `json:"keep_daily"`
+	KeepWeekly int `json:"keep_weekly"`
+}
+
+type BackupScheduleResponse struct {
+	ID             string            `json:"id"`
+	Cron           string            `json:"cron"`
+	Volume         string            `json:"volume,omitempty"`
+	Selector       map[string]string `json:"selector,omitempty"`
+	Target         string            `json:"target"`
+	Retention      RetentionResponse `json:"retention"`
+	StopContainers bool              `json:"stop_containers"`
+	CreatedAt      string            `json:"created_at"`
+	NextRun        string            `json:"next_run,omitempty"`
+}
+
+type BackupRunResponse struct {
+	ID              string   `json:"id"`
+	ScheduleID      string   `json:"schedule_id"`
+	Volume          string   `json:"volume"`
+	Target          string   `json:"target"`
+	Status          string   `json:"status"`
+	BackupID        string   `json:"backup_id,omitempty"`
+	Size            int64    `json:"size"`
+	SHA256          string   `json:"sha256,omitempty"`
+	StartedAt       string   `json:"started_at"`
+	FinishedAt      string   `json:"finished_at,omitempty"`
+	DurationSeconds float64  `json:"duration_seconds"`
+	Error           string   `json:"error,omitempty"`
+	Pruned          []string `json:"pruned,omitempty"`
+}
+
+type CloneProgressResponse struct {
+	Phase       string  `json:"phase"`
+	FilesCopied int     `json:"files_copied"`
+	BytesCopied int64   `json:"bytes_copied"`
+	BytesTotal  int64   `json:"bytes_total"`
+	Percent     float64 `json:"percent,omitempty"`
+}
+
+type CloneVolumeResponse struct {
+	Source          string         `json:"source"`
+	Volume          VolumeResponse `json:"volume"`
+	FilesCopied     int            `json:"files_copied"`
+	BytesCopied     int64          `json:"bytes_copied"`
+	DurationSeconds float64        `json:"duration_seconds"`
 }
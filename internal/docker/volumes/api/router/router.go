@@ -13,6 +13,22 @@ func Register(rg *gin.RouterGroup, handler *api.Handler) {
 		volumes.POST("", handler.Create)
 		volumes.DELETE("/:name", handler.Delete)
 
+		volumes.GET("/:name/files", handler.ListFiles)
+		volumes.GET("/:name/files/download", handler.Download)
+		volumes.POST("/:name/files", handler.Upload)
+
+		volumes.POST("/:name/backup", handler.Backup)
+		volumes.GET("/:name/backups", handler.ListBackups)
+		volumes.POST("/:name/restore", handler.Restore)
+		volumes.POST("/:name/clone", handler.Clone)
+
+		volumes.GET("/schedules", handler.ListSchedules)
+		volumes.POST("/schedules", handler.CreateSchedule)
+		volumes.GET("/schedules/:id", handler.GetSchedule)
+		volumes.DELETE("/schedules/:id", handler.DeleteSchedule)
+		volumes.POST("/schedules/:id/run", handler.RunSchedule)
+		volumes.GET("/backup-runs", handler.ListBackupRuns)
+
 		volumes.POST("/prune", handler.Prune)
 	}
 }
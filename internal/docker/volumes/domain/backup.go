@@ -0,0 +1,346 @@
+package domain
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+var (
+	ErrBackupsNotConfigured = errors.New("backup target is not configured")
+	ErrBackupNotFound       = errors.New("backup not found")
+	ErrChecksumMismatch     = errors.New("checksum mismatch")
+	ErrInvalidArchive       = errors.New("invalid backup archive")
+	ErrChecksumMissing      = errors.New("backup has no recorded checksum")
+)
+
+// DefaultBackupTarget is the target backups are stored in when none is named.
+const DefaultBackupTarget = "local"
+
+// UseBackupTarget makes target available under name for stored backups.
+func (s *VolumeServiceImpl) UseBackupTarget(name string, target adapter.BackupTarget) {
+	if s.targets == nil {
+		s.targets = make(map[string]adapter.BackupTarget)
+	}
+	s.targets[name] = target
+}
+
+// UseBackupDir stores backups in dir as the default target.
+func (s *VolumeServiceImpl) UseBackupDir(dir string) error {
+	target, err := adapter.NewLocalTarget(dir)
+	if err != nil {
+		return err
+	}
+	s.UseBackupTarget(DefaultBackupTarget, target)
+	return nil
+}
+
+func (s *VolumeServiceImpl) target(name string) (adapter.BackupTarget, string, error) {
+	if name == "" {
+		name = DefaultBackupTarget
+	}
+	target, ok := s.targets[name]
+	if !ok {
+		return nil, name, fmt.Errorf("%w: no backup target %q", ErrBackupsNotConfigured, name)
+	}
+	return target, name, nil
+}
+
+// Backup writes a backup of the volume to w as it is read.
+func (s *VolumeServiceImpl) Backup(ctx context.Context, name string, opts model.BackupOptions, w io.Writer) (*model.Backup, error) {
+	resume, err := s.stopUsers(ctx, name, opts.StopContainers)
+	if err != nil {
+		return nil, err
+	}
+	defer resume()
+	return s.writeBackup(ctx, name, w)
+}
+
+// StoreBackup saves a backup of the volume in opts.Target.
+func (s *VolumeServiceImpl) StoreBackup(ctx context.Context, name string, opts model.BackupOptions) (*model.Backup, error) {
+	return s.storeBackup(ctx, name, "", opts)
+}
+
+// storeBackup saves a backup of the volume tagged with the schedule taking
+// it, if any.
+func (s *VolumeServiceImpl) storeBackup(ctx context.Context, name, schedule string, opts model.BackupOptions) (*model.Backup, error) {
+	target, targetName, err := s.target(opts.Target)
+	if err != nil {
+		return nil, err
+	}
+	resume, err := s.stopUsers(ctx, name, opts.StopContainers)
+	if err != nil {
+		return nil, err
+	}
+	defer resume()
+	backup, err := target.Save(ctx, name, schedule, time.Now(), func(w io.Writer) (*model.Backup, error) {
+		return s.writeBackup(ctx, name, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	backup.Target = targetName
+	return backup, nil
+}
+
+func (s *VolumeServiceImpl) ListBackups(ctx context.Context, name, target string) ([]model.Backup, error) {
+	t, targetName, err := s.target(target)
+	if err != nil {
+		return nil, err
+	}
+	backups, err := t.List(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		backups[i].Target = targetName
+	}
+	return backups, nil
+}
+
+// Restore extracts a backup archive, gzip-compressed or not, into the
+// volume. The archive is spooled to disk and read through once before the
+// volume is touched, so nothing is extracted from an archive that fails its
+// checksum or turns out to be truncated or corrupted.
+func (s *VolumeServiceImpl) Restore(ctx context.Context, name string, archive io.Reader, opts model.RestoreOptions) error {
+	spooled, err := spoolArchive(archive, opts.SHA256)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		spooled.Close()
+		os.Remove(spooled.Name())
+	}()
+	tarball, err := decompress(spooled)
+	if err != nil {
+		return err
+	}
+	resume, err := s.stopUsers(ctx, name, opts.StopContainers)
+	if err != nil {
+		return err
+	}
+	defer resume()
+	defer s.usage.Invalidate()
+	if opts.Clean {
+		return s.adapter.Replace(ctx, name, tarball)
+	}
+	return s.adapter.CopyTo(ctx, name, "/", tarball)
+}
+
+// RestoreBackup restores a stored backup from opts.Target. The archive is
+// checked against its recorded checksum before anything is extracted; a
+// backup without one is only restored with opts.SkipVerify.
+func (s *VolumeServiceImpl) RestoreBackup(ctx context.Context, name, id string, opts model.RestoreOptions) error {
+	target, _, err := s.target(opts.Target)
+	if err != nil {
+		return err
+	}
+	archive, backup, err := target.Open(ctx, id)
+	if cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+	} else if err != nil {
+		return err
+	}
+	defer archive.Close()
+	if backup.SHA256 == "" && !opts.SkipVerify {
+		return fmt.Errorf("%w: %s", ErrChecksumMissing, id)
+	}
+	opts.SHA256 = backup.SHA256
+	return s.Restore(ctx, name, archive, opts)
+}
+
+// writeBackup writes a compressed archive of the volume's contents to w.
+func (s *VolumeServiceImpl) writeBackup(ctx context.Context, name string, w io.Writer) (*model.Backup, error) {
+	archive, _, err := s.adapter.CopyFrom(ctx, name, "/")
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	sum := sha256.New()
+	counter := &countingWriter{}
+	gz := gzip.NewWriter(io.MultiWriter(w, sum, counter))
+	tw := tar.NewWriter(gz)
+	if err := rerootArchive(tw, archive, nil); err != nil {
+		return nil, fmt.Errorf("failed to back up volume %s: %w", name, err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup of %s: %w", name, err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup of %s: %w", name, err)
+	}
+	return &model.Backup{
+		Volume:    name,
+		Size:      counter.n,
+		SHA256:    hex.EncodeToString(sum.Sum(nil)),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// rerootArchive copies the daemon's archive of a volume to tw with entries
+// moved from under the helper's mount point to the volume root. onEntry, if
+// set, is called after each entry with the size of its contents.
+func rerootArchive(tw *tar.Writer, archive io.Reader, onEntry func(size int64)) error {
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		_, rel, _ := strings.Cut(hdr.Name, "/")
+		if rel == "" {
+			continue
+		}
+		hdr.Name = rel
+		if hdr.Typeflag == tar.TypeLink {
+			_, hdr.Linkname, _ = strings.Cut(hdr.Linkname, "/")
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		n, err := io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+		if onEntry != nil {
+			onEntry(n)
+		}
+	}
+}
+
+// stopUsers stops the running containers that mount the volume when stop is
+// set. The returned function starts them again and must always be called.
+func (s *VolumeServiceImpl) stopUsers(ctx context.Context, name string, stop bool) (func(), error) {
+	var stopped []string
+	resume := func() {
+		// The request may be cancelled by now; containers must come back
+		// up regardless.
+		ctx := context.WithoutCancel(ctx)
+		for _, id := range stopped {
+			if err := s.adapter.StartContainer(ctx, id); err != nil {
+				log.Printf("failed to restart container %s after using volume %s: %v", id, name, err)
+			}
+		}
+	}
+	if !stop {
+		return resume, nil
+	}
+	mounts, err := s.adapter.Mounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, m := range mounts {
+		if m.VolumeName != name || m.State != "running" || seen[m.ContainerID] {
+			continue
+		}
+		seen[m.ContainerID] = true
+		if err := s.adapter.StopContainer(ctx, m.ContainerID); err != nil {
+			resume()
+			return nil, err
+		}
+		stopped = append(stopped, m.ContainerID)
+	}
+	return resume, nil
+}
+
+// spoolArchive copies archive to a temporary file, checks it against want
+// when set and makes sure it can be read to the end as a tar stream. The
+// returned file is positioned at its start.
+func spoolArchive(archive io.Reader, want string) (*os.File, error) {
+	f, err := os.CreateTemp("", "orcahub-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool archive: %w", err)
+	}
+	sum := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, sum), archive)
+	if err == nil && want != "" {
+		if got := hex.EncodeToString(sum.Sum(nil)); !strings.EqualFold(got, want) {
+			err = fmt.Errorf("%w: archive has sha256 %s, expected %s", ErrChecksumMismatch, got, want)
+		}
+	}
+	if err == nil {
+		err = validateArchive(f)
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return f, nil
+}
+
+// validateArchive reads the spooled archive to the end, entry by entry and
+// then through any padding, so the gzip trailer of compressed archives is
+// checked too.
+func validateArchive(f *os.File) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	tarball, err := decompress(f)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	tr := tar.NewReader(tarball)
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			_, err = io.Copy(io.Discard, tarball)
+			if err == nil {
+				return nil
+			}
+		} else if err == nil {
+			_, err = io.Copy(io.Discard, tr)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+	}
+}
+
+// decompress returns archive as a plain tar stream, gunzipping it when it
+// starts with the gzip magic number.
+func decompress(archive io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(archive)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return br, nil
+	}
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return gz, nil
+}
+
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
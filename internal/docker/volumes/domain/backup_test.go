@@ -0,0 +1,246 @@
+package domain_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
+	"github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// volumeArchive builds an archive the way the daemon returns the helper's
+// mount point: every entry under "volume/".
+func volumeArchive(files map[string]string) io.ReadCloser {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "volume/", Typeflag: tar.TypeDir, Mode: 0o755})
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: "volume/" + name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	return io.NopCloser(&buf)
+}
+
+func readBackup(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+	}
+}
+
+func TestVolumeService_Backup(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("CopyFrom", ctx, "pg-data", "/").
+		Return(volumeArchive(map[string]string{"PG_VERSION": "16"}), &model.VolumeFile{Name: "pg-data", Type: model.FileTypeDir}, nil)
+
+	var out bytes.Buffer
+	backup, err := svc.Backup(ctx, "pg-data", model.BackupOptions{}, &out)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(out.Bytes())
+	assert.Equal(t, hex.EncodeToString(sum[:]), backup.SHA256)
+	assert.Equal(t, int64(out.Len()), backup.Size)
+	assert.Equal(t, map[string]string{"PG_VERSION": "16"}, readBackup(t, &out))
+}
+
+func TestVolumeService_Backup_StopsContainers(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Mounts", ctx).Return([]model.VolumeMount{
+		{VolumeName: "pg-data", ContainerID: "c1", State: "running"},
+		{VolumeName: "pg-data", ContainerID: "c2", State: "exited"},
+		{VolumeName: "other", ContainerID: "c3", State: "running"},
+	}, nil)
+	a.On("StopContainer", ctx, "c1").Return(nil).Once()
+	a.On("StartContainer", mock.Anything, "c1").Return(nil).Once()
+	a.On("CopyFrom", ctx, "pg-data", "/").
+		Return(volumeArchive(nil), &model.VolumeFile{Type: model.FileTypeDir}, nil)
+
+	_, err := svc.Backup(ctx, "pg-data", model.BackupOptions{StopContainers: true}, io.Discard)
+	require.NoError(t, err)
+	a.AssertExpectations(t)
+	a.AssertNotCalled(t, "StopContainer", ctx, "c2")
+	a.AssertNotCalled(t, "StopContainer", ctx, "c3")
+}
+
+func TestVolumeService_StoreBackup_NotConfigured(t *testing.T) {
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+
+	_, err := svc.StoreBackup(context.Background(), "pg-data", model.BackupOptions{})
+	assert.ErrorIs(t, err, domain.ErrBackupsNotConfigured)
+}
+
+func TestVolumeService_StoreAndRestoreBackup(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, svc.UseBackupDir(dir))
+
+	a.On("CopyFrom", ctx, "pg-data", "/").
+		Return(volumeArchive(map[string]string{"PG_VERSION": "16"}), &model.VolumeFile{Type: model.FileTypeDir}, nil)
+
+	stored, err := svc.StoreBackup(ctx, "pg-data", model.BackupOptions{})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored.ID, "pg-data-"))
+	checksum, err := os.ReadFile(filepath.Join(dir, stored.ID+".sha256"))
+	require.NoError(t, err)
+	assert.Equal(t, stored.SHA256+"  "+stored.ID+"\n", string(checksum))
+
+	backups, err := svc.ListBackups(ctx, "pg-data", "")
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, stored.ID, backups[0].ID)
+	assert.Equal(t, stored.SHA256, backups[0].SHA256)
+
+	// Restoring into another volume extracts the re-rooted archive.
+	var restored map[string]string
+	a.On("Replace", ctx, "pg-copy").Return(func(archive io.Reader) error {
+		tr := tar.NewReader(archive)
+		restored = map[string]string{}
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			data, _ := io.ReadAll(tr)
+			restored[hdr.Name] = string(data)
+		}
+	})
+	require.NoError(t, svc.RestoreBackup(ctx, "pg-copy", stored.ID, model.RestoreOptions{Clean: true}))
+	assert.Equal(t, map[string]string{"PG_VERSION": "16"}, restored)
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_RestoreBackup_Corrupted(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, svc.UseBackupDir(dir))
+
+	id := "pg-data-20260101T000000Z.tar.gz"
+	os.WriteFile(filepath.Join(dir, id), []byte("tampered"), 0o640)
+	os.WriteFile(filepath.Join(dir, id+".sha256"), []byte(strings.Repeat("0", 64)+"  "+id+"\n"), 0o640)
+
+	err := svc.RestoreBackup(ctx, "pg-data", id, model.RestoreOptions{})
+	assert.ErrorIs(t, err, domain.ErrChecksumMismatch)
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_RestoreBackup_MissingChecksum(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, svc.UseBackupDir(dir))
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tar.NewWriter(gz).Close()
+	gz.Close()
+	id := "pg-data-20260101T000000.000Z.tar.gz"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, id), buf.Bytes(), 0o640))
+
+	err := svc.RestoreBackup(ctx, "pg-data", id, model.RestoreOptions{})
+	assert.ErrorIs(t, err, domain.ErrChecksumMissing)
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+
+	a.On("CopyTo", ctx, "pg-data", "/").Return(nil)
+	require.NoError(t, svc.RestoreBackup(ctx, "pg-data", id, model.RestoreOptions{SkipVerify: true}))
+	a.AssertCalled(t, "CopyTo", ctx, "pg-data", "/")
+}
+
+func TestVolumeService_RestoreBackup_NotFound(t *testing.T) {
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+
+	for _, id := range []string{"missing.tar.gz", "../etc/passwd"} {
+		err := svc.RestoreBackup(context.Background(), "pg-data", id, model.RestoreOptions{})
+		assert.ErrorIs(t, err, domain.ErrBackupNotFound)
+	}
+}
+
+func TestVolumeService_Restore_ChecksumMismatch(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+
+	err := svc.Restore(context.Background(), "pg-data", strings.NewReader("archive"), model.RestoreOptions{SHA256: strings.Repeat("0", 64)})
+	assert.ErrorIs(t, err, domain.ErrChecksumMismatch)
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Restore_TruncatedArchiveLeavesVolume(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "PG_VERSION", Mode: 0o644, Size: 2})
+	tw.Write([]byte("16"))
+	tw.Close()
+	gz.Close()
+	truncated := buf.Bytes()[:buf.Len()-8]
+
+	err := svc.Restore(context.Background(), "pg-data", bytes.NewReader(truncated), model.RestoreOptions{Clean: true, StopContainers: true})
+	assert.ErrorIs(t, err, domain.ErrInvalidArchive)
+	a.AssertNotCalled(t, "Mounts", mock.Anything)
+	a.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything)
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Restore_Uncompressed(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "a.txt", Mode: 0o644, Size: 1})
+	tw.Write([]byte("a"))
+	tw.Close()
+	raw := buf.Bytes()
+	sum := sha256.Sum256(raw)
+
+	var got []byte
+	a.On("CopyTo", ctx, "pg-data", "/").Return(func(archive io.Reader) error {
+		got, _ = io.ReadAll(archive)
+		return nil
+	})
+
+	err := svc.Restore(ctx, "pg-data", bytes.NewReader(raw), model.RestoreOptions{SHA256: hex.EncodeToString(sum[:])})
+	require.NoError(t, err)
+	assert.Equal(t, raw, got)
+}
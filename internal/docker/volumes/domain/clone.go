@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// cloneProgressInterval rate-limits progress reports while copying.
+const cloneProgressInterval = 250 * time.Millisecond
+
+// clonedFromLabel records the source on every cloned volume.
+const clonedFromLabel = "io.orcahub.cloned-from"
+
+var (
+	ErrInvalidClone = errors.New("invalid clone")
+	ErrVolumeExists = errors.New("volume already exists")
+)
+
+// Clone copies the contents of volume name into a new volume. The new
+// volume is removed again if the copy fails. progress, if set, is called as
+// the clone advances; calls may come from another goroutine but never
+// overlap.
+func (s *VolumeServiceImpl) Clone(ctx context.Context, name string, opts model.CloneOptions, progress func(model.CloneProgress)) (*model.CloneResult, error) {
+	if progress == nil {
+		progress = func(model.CloneProgress) {}
+	}
+	if opts.Name == "" || opts.Name == name {
+		return nil, fmt.Errorf("%w: the clone needs a name different from %s", ErrInvalidClone, name)
+	}
+	source, err := s.adapter.Inspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	driver := opts.Driver
+	if driver == "" {
+		driver = source.Driver
+	}
+	// Without its options the clone would silently get different storage
+	// than the source, and copying them would point it at the source's.
+	if driver == source.Driver && len(source.Options) > 0 && len(opts.DriverOpts) == 0 {
+		return nil, fmt.Errorf("%w: %s was created with driver options, which are not copied; set driver_opts for the clone", ErrInvalidClone, name)
+	}
+	if _, err := s.adapter.Inspect(ctx, opts.Name); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVolumeExists, opts.Name)
+	} else if !cerrdefs.IsNotFound(err) {
+		return nil, err
+	}
+
+	started := time.Now()
+	state := model.CloneProgress{Phase: model.ClonePhaseCreating, BytesTotal: -1}
+	if usage, err := s.usage.Usage(ctx); err == nil {
+		if u, ok := usage[name]; ok {
+			state.BytesTotal = u.Size
+		}
+	}
+	progress(state)
+
+	labels := maps.Clone(source.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	maps.Copy(labels, opts.Labels)
+	labels[clonedFromLabel] = name
+	clone, err := s.adapter.Create(ctx, model.CreateVolumeOptions{
+		Name:       opts.Name,
+		Driver:     driver,
+		DriverOpts: opts.DriverOpts,
+		Labels:     labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resume, err := s.stopUsers(ctx, name, opts.StopContainers)
+	if err != nil {
+		s.discardClone(opts.Name)
+		return nil, err
+	}
+	defer resume()
+
+	state.Phase = model.ClonePhaseCopying
+	progress(state)
+	if err := s.copyVolume(ctx, name, opts.Name, &state, progress); err != nil {
+		s.discardClone(opts.Name)
+		return nil, fmt.Errorf("failed to copy volume %s to %s: %w", name, opts.Name, err)
+	}
+	s.usage.Invalidate()
+
+	state.Phase = model.ClonePhaseDone
+	progress(state)
+	return &model.CloneResult{
+		Source:      name,
+		Volume:      clone,
+		FilesCopied: state.FilesCopied,
+		BytesCopied: state.BytesCopied,
+		Duration:    time.Since(started),
+	}, nil
+}
+
+// copyVolume streams the source's archive straight into the destination,
+// without staging it on disk.
+func (s *VolumeServiceImpl) copyVolume(ctx context.Context, src, dst string, state *model.CloneProgress, progress func(model.CloneProgress)) error {
+	archive, _, err := s.adapter.CopyFrom(ctx, src, "/")
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	pr, pw := io.Pipe()
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		tw := tar.NewWriter(pw)
+		lastReport := time.Now()
+		err := rerootArchive(tw, archive, func(size int64) {
+			state.FilesCopied++
+			state.BytesCopied += size
+			if time.Since(lastReport) >= cloneProgressInterval {
+				lastReport = time.Now()
+				progress(*state)
+			}
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	err = s.adapter.CopyTo(ctx, dst, "/", pr)
+	pr.CloseWithError(err)
+	<-copied
+	return err
+}
+
+func (s *VolumeServiceImpl) discardClone(name string) {
+	if err := s.adapter.Delete(context.Background(), name); err != nil {
+		log.Printf("failed to remove incomplete clone %s: %v", name, err)
+	}
+}
@@ -0,0 +1,130 @@
+package domain_test
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
+	"github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func notFound(name string) error {
+	return fmt.Errorf("failed to inspect volume %s: %w", name, cerrdefs.ErrNotFound)
+}
+
+func TestVolumeService_Clone(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "pg-data").Return(&model.Volume{Name: "pg-data", Driver: "local", Labels: map[string]string{"app": "pg"}}, nil)
+	a.On("Inspect", ctx, "pg-data-nfs").Return(nil, notFound("pg-data-nfs"))
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{"pg-data": {Size: 6}}, nil)
+	nfsOpts := map[string]string{"type": "nfs", "o": "addr=10.0.0.5", "device": ":/exports/pg"}
+	a.On("Create", ctx, model.CreateVolumeOptions{
+		Name:       "pg-data-nfs",
+		Driver:     "local",
+		DriverOpts: nfsOpts,
+		Labels:     map[string]string{"app": "pg", "tier": "nfs", "io.orcahub.cloned-from": "pg-data"},
+	}).Return(&model.Volume{Name: "pg-data-nfs", Driver: "local"}, nil)
+	a.On("CopyFrom", ctx, "pg-data", "/").
+		Return(volumeArchive(map[string]string{"PG_VERSION": "16", "base/1": "data"}), &model.VolumeFile{Type: model.FileTypeDir}, nil)
+
+	copied := map[string]string{}
+	a.On("CopyTo", ctx, "pg-data-nfs", "/").Return(func(archive io.Reader) error {
+		tr := tar.NewReader(archive)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			data, _ := io.ReadAll(tr)
+			copied[hdr.Name] = string(data)
+		}
+	})
+
+	var phases []string
+	result, err := svc.Clone(ctx, "pg-data", model.CloneOptions{
+		Name:       "pg-data-nfs",
+		DriverOpts: nfsOpts,
+		Labels:     map[string]string{"tier": "nfs"},
+	}, func(p model.CloneProgress) {
+		if len(phases) == 0 || phases[len(phases)-1] != p.Phase {
+			phases = append(phases, p.Phase)
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"PG_VERSION": "16", "base/1": "data"}, copied)
+	assert.Equal(t, []string{model.ClonePhaseCreating, model.ClonePhaseCopying, model.ClonePhaseDone}, phases)
+	assert.Equal(t, "pg-data-nfs", result.Volume.Name)
+	assert.Equal(t, 2, result.FilesCopied)
+	assert.Equal(t, int64(6), result.BytesCopied)
+}
+
+func TestVolumeService_Clone_Exists(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "pg-data").Return(&model.Volume{Name: "pg-data"}, nil)
+	a.On("Inspect", ctx, "pg-backup").Return(&model.Volume{Name: "pg-backup"}, nil)
+
+	_, err := svc.Clone(ctx, "pg-data", model.CloneOptions{Name: "pg-backup"}, nil)
+	assert.ErrorIs(t, err, domain.ErrVolumeExists)
+	a.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Clone_SourceDriverOpts(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "pg-data").Return(&model.Volume{
+		Name:    "pg-data",
+		Driver:  "local",
+		Options: map[string]string{"type": "nfs", "o": "addr=10.0.0.5", "device": ":/exports/pg"},
+	}, nil)
+
+	_, err := svc.Clone(ctx, "pg-data", model.CloneOptions{Name: "pg-data-copy"}, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidClone)
+	assert.Contains(t, err.Error(), "driver_opts")
+	a.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Clone_InvalidName(t *testing.T) {
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+
+	for _, name := range []string{"", "pg-data"} {
+		_, err := svc.Clone(context.Background(), "pg-data", model.CloneOptions{Name: name}, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidClone)
+	}
+}
+
+func TestVolumeService_Clone_CopyFails(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "pg-data").Return(&model.Volume{Name: "pg-data", Driver: "local"}, nil)
+	a.On("Inspect", ctx, "pg-copy").Return(nil, notFound("pg-copy"))
+	a.On("Usage", ctx).Return(nil, assert.AnError)
+	a.On("Create", ctx, mock.Anything).Return(&model.Volume{Name: "pg-copy"}, nil)
+	a.On("CopyFrom", ctx, "pg-data", "/").
+		Return(volumeArchive(map[string]string{"PG_VERSION": "16"}), &model.VolumeFile{Type: model.FileTypeDir}, nil)
+	a.On("CopyTo", ctx, "pg-copy", "/").Return(assert.AnError)
+	a.On("Delete", mock.Anything, "pg-copy").Return(nil)
+
+	_, err := svc.Clone(ctx, "pg-data", model.CloneOptions{Name: "pg-copy"}, nil)
+	assert.ErrorIs(t, err, assert.AnError)
+	a.AssertCalled(t, "Delete", mock.Anything, "pg-copy")
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+var ErrInvalidPath = errors.New("invalid path")
+
+// volumePath cleans p into an absolute path from the volume root. Cleaning
+// against the root resolves any ".." so the result cannot escape the volume.
+func volumePath(p string) (string, error) {
+	if strings.ContainsRune(p, 0) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPath, p)
+	}
+	return path.Clean("/" + p), nil
+}
+
+func (s *VolumeServiceImpl) ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error) {
+	dir, err := volumePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.adapter.ListFiles(ctx, name, dir)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(files, func(a, b int) bool {
+		if aDir, bDir := files[a].Type == model.FileTypeDir, files[b].Type == model.FileTypeDir; aDir != bDir {
+			return aDir
+		}
+		return files[a].Name < files[b].Name
+	})
+	return files, nil
+}
+
+// Download returns the contents of a regular file, or a tar archive of
+// anything else, such as a directory.
+func (s *VolumeServiceImpl) Download(ctx context.Context, name, p string) (*model.VolumeFile, io.ReadCloser, error) {
+	p, err := volumePath(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	archive, file, err := s.adapter.CopyFrom(ctx, name, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file.Type != model.FileTypeFile {
+		return file, archive, nil
+	}
+	tr := tar.NewReader(archive)
+	if _, err := tr.Next(); err != nil {
+		archive.Close()
+		return nil, nil, fmt.Errorf("failed to read %s from volume %s: %w", p, name, err)
+	}
+	return file, &tarEntryReader{Reader: tr, closer: archive}, nil
+}
+
+// Upload writes files into dir, replacing existing files of the same name,
+// and returns their paths in the volume.
+func (s *VolumeServiceImpl) Upload(ctx context.Context, name, dir string, files []model.UploadFile) ([]string, error) {
+	dir, err := volumePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no files to upload", ErrInvalidPath)
+	}
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Name == "" || f.Name == "." || f.Name == ".." || strings.ContainsAny(f.Name, "/\x00") {
+			return nil, fmt.Errorf("%w: file name %q", ErrInvalidPath, f.Name)
+		}
+		paths = append(paths, path.Join(dir, f.Name))
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeUploadArchive(pw, files))
+	}()
+	err = s.adapter.CopyTo(ctx, name, dir, pr)
+	pr.CloseWithError(err)
+	if err != nil {
+		return nil, err
+	}
+	s.usage.Invalidate()
+	return paths, nil
+}
+
+func writeUploadArchive(w io.Writer, files []model.UploadFile) error {
+	tw := tar.NewWriter(w)
+	now := time.Now()
+	for _, f := range files {
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.Name,
+			Size:     f.Size,
+			Mode:     mode,
+			ModTime:  now,
+		}); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(tw, f.Content, f.Size); err != nil {
+			return fmt.Errorf("failed to read upload %s: %w", f.Name, err)
+		}
+	}
+	return tw.Close()
+}
+
+// tarEntryReader reads the single file of an archive and closes the archive.
+type tarEntryReader struct {
+	io.Reader
+	closer io.Closer
+}
+
+func (r *tarEntryReader) Close() error {
+	return r.closer.Close()
+}
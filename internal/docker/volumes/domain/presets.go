@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+var ErrInvalidVolume = errors.New("invalid volume options")
+
+var (
+	tmpfsSizePattern = regexp.MustCompile(`^[0-9]+[kmgKMG%]?$`)
+	tmpfsModePattern = regexp.MustCompile(`^[0-7]{3,4}$`)
+)
+
+// presetKeys are the mount options a preset sets from its own fields, so
+// extra options may not repeat them.
+var presetKeys = map[string]bool{
+	"addr": true, "username": true, "user": true, "password": true, "pass": true,
+	"domain": true, "nfsvers": true, "vers": true, "size": true, "mode": true,
+	"uid": true, "gid": true, "bind": true, "ro": true, "rw": true,
+}
+
+// presetDriverOpts turns a preset into local driver options: type, device
+// and o, the comma separated mount options.
+func presetDriverOpts(p *model.MountPreset) (map[string]string, error) {
+	if err := checkPresetFields(p); err != nil {
+		return nil, err
+	}
+	var o []string
+	opts := map[string]string{}
+	switch p.Type {
+	case model.MountTypeNFS:
+		if !path.IsAbs(p.Path) {
+			return nil, fmt.Errorf("%w: nfs path must be absolute", ErrInvalidVolume)
+		}
+		opts["type"], opts["device"] = "nfs", ":"+path.Clean(p.Path)
+		o = append(o, "addr="+p.Server)
+		if p.Version != "" {
+			o = append(o, "nfsvers="+p.Version)
+		}
+	case model.MountTypeCIFS:
+		share := strings.Trim(p.Path, "/")
+		if share == "" {
+			return nil, fmt.Errorf("%w: cifs path must name a share", ErrInvalidVolume)
+		}
+		opts["type"], opts["device"] = "cifs", "//"+p.Server+"/"+share
+		o = append(o, "addr="+p.Server)
+		if p.Username != "" {
+			o = append(o, "username="+p.Username)
+		}
+		if p.Password != "" {
+			o = append(o, "password="+p.Password)
+		}
+		if p.Domain != "" {
+			o = append(o, "domain="+p.Domain)
+		}
+		if p.Version != "" {
+			o = append(o, "vers="+p.Version)
+		}
+	case model.MountTypeBind:
+		if !path.IsAbs(p.Path) {
+			return nil, fmt.Errorf("%w: bind path must be absolute", ErrInvalidVolume)
+		}
+		opts["type"], opts["device"] = "none", path.Clean(p.Path)
+		o = append(o, "bind")
+	case model.MountTypeTmpfs:
+		opts["type"], opts["device"] = "tmpfs", "tmpfs"
+		if p.Size != "" {
+			if !tmpfsSizePattern.MatchString(p.Size) {
+				return nil, fmt.Errorf("%w: invalid tmpfs size %q", ErrInvalidVolume, p.Size)
+			}
+			o = append(o, "size="+p.Size)
+		}
+		if p.Mode != "" {
+			if !tmpfsModePattern.MatchString(p.Mode) {
+				return nil, fmt.Errorf("%w: invalid tmpfs mode %q", ErrInvalidVolume, p.Mode)
+			}
+			o = append(o, "mode="+p.Mode)
+		}
+		if p.UID != nil {
+			o = append(o, "uid="+strconv.Itoa(*p.UID))
+		}
+		if p.GID != nil {
+			o = append(o, "gid="+strconv.Itoa(*p.GID))
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown preset type %q", ErrInvalidVolume, p.Type)
+	}
+	if p.ReadOnly {
+		o = append(o, "ro")
+	}
+	for _, opt := range p.Options {
+		key, _, _ := strings.Cut(opt, "=")
+		if opt == "" || presetKeys[key] {
+			return nil, fmt.Errorf("%w: option %q cannot be set directly", ErrInvalidVolume, opt)
+		}
+		o = append(o, opt)
+	}
+	for _, opt := range o {
+		// The options are joined with commas, so one would split a value.
+		// The value is left out of the error: it may be the password.
+		if strings.ContainsAny(opt, ",\n") {
+			key, _, _ := strings.Cut(opt, "=")
+			return nil, fmt.Errorf("%w: %s contains a comma", ErrInvalidVolume, key)
+		}
+	}
+	if len(o) > 0 {
+		opts["o"] = strings.Join(o, ",")
+	}
+	return opts, nil
+}
+
+// checkPresetFields rejects fields that do not apply to the preset type, so
+// a typo such as a password on an NFS volume is not silently dropped.
+func checkPresetFields(p *model.MountPreset) error {
+	remote := p.Type == model.MountTypeNFS || p.Type == model.MountTypeCIFS
+	switch {
+	case remote && p.Server == "":
+		return fmt.Errorf("%w: %s needs a server", ErrInvalidVolume, p.Type)
+	case remote && strings.ContainsAny(p.Server, "/\\"):
+		return fmt.Errorf("%w: invalid server %q", ErrInvalidVolume, p.Server)
+	case !remote && p.Server != "":
+		return fmt.Errorf("%w: %s takes no server", ErrInvalidVolume, p.Type)
+	case !remote && p.Version != "":
+		return fmt.Errorf("%w: %s takes no version", ErrInvalidVolume, p.Type)
+	case p.Type != model.MountTypeCIFS && (p.Username != "" || p.Password != "" || p.Domain != ""):
+		return fmt.Errorf("%w: credentials only apply to cifs", ErrInvalidVolume)
+	case p.Type != model.MountTypeTmpfs && (p.Size != "" || p.Mode != "" || p.UID != nil || p.GID != nil):
+		return fmt.Errorf("%w: size, mode, uid and gid only apply to tmpfs", ErrInvalidVolume)
+	case p.Type == model.MountTypeTmpfs && p.Path != "":
+		return fmt.Errorf("%w: tmpfs takes no path", ErrInvalidVolume)
+	}
+	return nil
+}
+
+// describeMount decodes the mount behind a local driver volume and hides
+// credentials from its options.
+func describeMount(v *model.Volume) {
+	if v.Driver != "local" || v.Options["type"] == "" {
+		return
+	}
+	info := &model.MountInfo{Type: v.Options["type"], Path: v.Options["device"]}
+	var kept []string
+	redacted := false
+	for _, opt := range strings.Split(v.Options["o"], ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "":
+		case "addr":
+			info.Server = value
+		case "username", "user":
+			info.Username = value
+		case "password", "pass":
+			redacted = true
+		case "ro":
+			info.ReadOnly = true
+		case "bind":
+			info.Type = model.MountTypeBind
+		default:
+			kept = append(kept, opt)
+		}
+	}
+	info.Options = kept
+
+	switch info.Type {
+	case "nfs", "nfs4":
+		info.Type = model.MountTypeNFS
+		// The device is ":/export" with addr set, or "server:/export".
+		if server, export, ok := strings.Cut(info.Path, ":/"); ok {
+			if info.Server == "" {
+				info.Server = server
+			}
+			info.Path = "/" + export
+		}
+	case "cifs", "smb3":
+		info.Type = model.MountTypeCIFS
+		if rest, ok := strings.CutPrefix(info.Path, "//"); ok {
+			server, share, _ := strings.Cut(rest, "/")
+			if info.Server == "" {
+				info.Server = server
+			}
+			info.Path = share
+		}
+	case model.MountTypeTmpfs:
+		info.Path = ""
+	}
+	v.Mount = info
+
+	if redacted {
+		v.Options = v.RedactedOptions()
+	}
+}
@@ -0,0 +1,135 @@
+package domain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestVolumeService_Create_Presets(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset model.MountPreset
+		want   map[string]string
+	}{
+		{
+			name:   "nfs",
+			preset: model.MountPreset{Type: model.MountTypeNFS, Server: "10.0.0.5", Path: "/exports/pg/", Version: "4.1", Options: []string{"soft"}},
+			want:   map[string]string{"type": "nfs", "device": ":/exports/pg", "o": "addr=10.0.0.5,nfsvers=4.1,soft"},
+		},
+		{
+			name:   "cifs",
+			preset: model.MountPreset{Type: model.MountTypeCIFS, Server: "nas.lan", Path: "/media", Username: "svc", Password: "s3cret pw", Version: "3.0", ReadOnly: true},
+			want:   map[string]string{"type": "cifs", "device": "//nas.lan/media", "o": "addr=nas.lan,username=svc,password=s3cret pw,vers=3.0,ro"},
+		},
+		{
+			name:   "bind",
+			preset: model.MountPreset{Type: model.MountTypeBind, Path: "/srv/data"},
+			want:   map[string]string{"type": "none", "device": "/srv/data", "o": "bind"},
+		},
+		{
+			name:   "tmpfs",
+			preset: model.MountPreset{Type: model.MountTypeTmpfs, Size: "64m", Mode: "1777", UID: intPtr(1000), GID: intPtr(0)},
+			want:   map[string]string{"type": "tmpfs", "device": "tmpfs", "o": "size=64m,mode=1777,uid=1000,gid=0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &mockVolumeAdapter{}
+			svc := domain.NewVolumeServiceImpl(a)
+			ctx := context.Background()
+
+			preset := tt.preset
+			a.On("Create", ctx, model.CreateVolumeOptions{Name: "vol", Driver: "local", DriverOpts: tt.want}).
+				Return(&model.Volume{Name: "vol", Driver: "local", Options: tt.want}, nil)
+
+			v, err := svc.Create(ctx, model.CreateVolumeOptions{Name: "vol", Preset: &preset})
+			require.NoError(t, err)
+			require.NotNil(t, v.Mount)
+			assert.Equal(t, tt.name, v.Mount.Type)
+			assert.NotContains(t, v.Options["o"], "s3cret")
+		})
+	}
+}
+
+func TestVolumeService_Create_InvalidPresets(t *testing.T) {
+	tests := map[string]model.CreateVolumeOptions{
+		"nfs without server":  {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeNFS, Path: "/exports"}},
+		"nfs relative path":   {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeNFS, Server: "nas", Path: "exports"}},
+		"cifs without share":  {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeCIFS, Server: "nas"}},
+		"comma in password":   {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeCIFS, Server: "nas", Path: "s", Password: "a,uid=0"}},
+		"credentials on nfs":  {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeNFS, Server: "nas", Path: "/e", Password: "x"}},
+		"bind with server":    {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeBind, Server: "nas", Path: "/srv"}},
+		"bad tmpfs size":      {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeTmpfs, Size: "lots"}},
+		"option overrides":    {Name: "v", Preset: &model.MountPreset{Type: model.MountTypeNFS, Server: "nas", Path: "/e", Options: []string{"addr=evil"}}},
+		"unknown type":        {Name: "v", Preset: &model.MountPreset{Type: "ceph"}},
+		"preset and raw opts": {Name: "v", DriverOpts: map[string]string{"type": "nfs"}, Preset: &model.MountPreset{Type: model.MountTypeTmpfs}},
+		"preset and driver":   {Name: "v", Driver: "rexray", Preset: &model.MountPreset{Type: model.MountTypeTmpfs}},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := &mockVolumeAdapter{}
+			svc := domain.NewVolumeServiceImpl(a)
+
+			_, err := svc.Create(context.Background(), opts)
+			assert.ErrorIs(t, err, domain.ErrInvalidVolume)
+			a.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestVolumeService_Inspect_DecodesMount(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("Inspect", ctx, "media").Return(&model.Volume{
+		Name:   "media",
+		Driver: "local",
+		Options: map[string]string{
+			"type":   "cifs",
+			"device": "//nas.lan/media",
+			"o":      "username=svc,password=hunter2,file_mode=0644",
+		},
+	}, nil)
+	a.On("Usage", ctx).Return(nil, assert.AnError)
+	a.On("Mounts", ctx).Return(nil, assert.AnError)
+
+	v, err := svc.Inspect(ctx, "media")
+	require.NoError(t, err)
+	assert.Equal(t, &model.MountInfo{
+		Type:     model.MountTypeCIFS,
+		Server:   "nas.lan",
+		Path:     "media",
+		Username: "svc",
+		Options:  []string{"file_mode=0644"},
+	}, v.Mount)
+	assert.Equal(t, "username=svc,password=********,file_mode=0644", v.Options["o"])
+}
+
+func TestVolumeService_List_RedactsPassword(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{
+		{Name: "media", Driver: "local", Options: map[string]string{"type": "cifs", "device": "//nas/media", "o": "username=svc,password=hunter2"}},
+		{Name: "cache", Driver: "local"},
+	}, nil)
+	a.On("Usage", ctx).Return(nil, assert.AnError)
+	a.On("Mounts", ctx).Return(nil, assert.AnError)
+
+	vs, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, vs, 2)
+	assert.Equal(t, "username=svc,password=********", vs[0].Options["o"])
+	assert.Equal(t, "svc", vs[0].Mount.Username)
+	assert.Nil(t, vs[1].Mount)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// Prune removes unused volumes matching opts. A dry run deletes nothing and
+// reports what would be removed, with sizes where the daemon knows them.
+func (s *VolumeServiceImpl) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	if opts.DryRun {
+		return s.prunePreview(ctx, opts)
+	}
+	result, err := s.adapter.Prune(ctx, opts)
+	if err == nil {
+		s.usage.Invalidate()
+	}
+	return result, err
+}
+
+// prunePreview applies the daemon's prune rules to the current volumes: a
+// volume is a candidate when no container, running or not, references it.
+func (s *VolumeServiceImpl) prunePreview(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	vs, err := s.adapter.List(ctx)
+	if err != nil {
+		return model.PruneResult{}, err
+	}
+	// Unlike Annotate this is not best effort: without the mounts every
+	// volume would look unused.
+	mounts, err := s.adapter.Mounts(ctx)
+	if err != nil {
+		return model.PruneResult{}, err
+	}
+	used := make(map[string]bool, len(mounts))
+	for _, m := range mounts {
+		used[m.VolumeName] = true
+	}
+	usage, _ := s.usage.Usage(ctx)
+
+	result := model.PruneResult{Deleted: []string{}, DryRun: true}
+	for _, v := range vs {
+		if used[v.Name] || !pruneMatch(v.Labels, opts) {
+			continue
+		}
+		size := int64(-1)
+		if u, ok := usage[v.Name]; ok {
+			size = u.Size
+		}
+		if size > 0 {
+			result.SpaceReclaimed += size
+		}
+		result.Volumes = append(result.Volumes, model.PrunedVolume{
+			Name:   v.Name,
+			Driver: v.Driver,
+			Size:   size,
+			Labels: v.Labels,
+		})
+	}
+	sort.Slice(result.Volumes, func(i, j int) bool {
+		return result.Volumes[i].Name < result.Volumes[j].Name
+	})
+	for _, v := range result.Volumes {
+		result.Deleted = append(result.Deleted, v.Name)
+	}
+	return result, nil
+}
+
+func pruneMatch(labels map[string]string, opts model.PruneOptions) bool {
+	if _, ok := labels[model.AnonymousLabel]; !ok && !opts.All {
+		return false
+	}
+	for _, f := range opts.Labels {
+		if !labelMatch(labels, f) {
+			return false
+		}
+	}
+	for _, f := range opts.ExcludeLabels {
+		if labelMatch(labels, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// labelMatch reports whether labels satisfy a Docker label filter, either
+// "key" or "key=value".
+func labelMatch(labels map[string]string, filter string) bool {
+	key, value, hasValue := strings.Cut(filter, "=")
+	got, ok := labels[key]
+	return ok && (!hasValue || got == value)
+}
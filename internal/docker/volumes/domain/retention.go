@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// expiredBackups returns the backups no retention rule keeps. backups must be
+// sorted newest first, as targets list them.
+func expiredBackups(backups []model.Backup, r model.Retention) []model.Backup {
+	if r.KeepLast == 0 && r.KeepDaily == 0 && r.KeepWeekly == 0 {
+		return nil
+	}
+	keep := make(map[string]bool)
+	for i := 0; i < len(backups) && i < r.KeepLast; i++ {
+		keep[backups[i].ID] = true
+	}
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, b := range backups {
+		created := b.CreatedAt.UTC()
+		if day := created.Format("2006-01-02"); !days[day] && len(days) < r.KeepDaily {
+			days[day] = true
+			keep[b.ID] = true
+		}
+		year, week := created.ISOWeek()
+		if key := fmt.Sprintf("%d-W%02d", year, week); !weeks[key] && len(weeks) < r.KeepWeekly {
+			weeks[key] = true
+			keep[b.ID] = true
+		}
+	}
+	var expired []model.Backup
+	for _, b := range backups {
+		if !keep[b.ID] {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+// applyRetention deletes the backups of volume in target that schedule took
+// and r does not keep, and returns the IDs it deleted. Backups taken on
+// request or by other schedules are left alone.
+func (s *VolumeServiceImpl) applyRetention(ctx context.Context, targetName, volume, schedule string, r model.Retention) ([]string, error) {
+	target, _, err := s.target(targetName)
+	if err != nil {
+		return nil, err
+	}
+	backups, err := target.List(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+	var own []model.Backup
+	for _, b := range backups {
+		if b.ScheduleID == schedule {
+			own = append(own, b)
+		}
+	}
+	var deleted []string
+	var errs []error
+	for _, b := range expiredBackups(own, r) {
+		if err := target.Delete(ctx, b.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted = append(deleted, b.ID)
+	}
+	return deleted, errors.Join(errs...)
+}
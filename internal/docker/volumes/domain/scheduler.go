@@ -0,0 +1,474 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/robfig/cron/v3"
+)
+
+// maxBackupRuns bounds the run history, which is saved with the schedules.
+const maxBackupRuns = 1000
+
+// errRunInterrupted is recorded for runs that were still going when the
+// process stopped.
+const errRunInterrupted = "interrupted: the server stopped during the run"
+
+var (
+	ErrInvalidSchedule  = errors.New("invalid backup schedule")
+	ErrScheduleNotFound = errors.New("backup schedule not found")
+	ErrScheduleRunning  = errors.New("backup schedule is already running")
+)
+
+// BackupScheduler takes stored backups on cron schedules and applies their
+// retention policies afterwards. Schedules and their run history are saved to
+// a file when one is configured.
+type BackupScheduler struct {
+	service *VolumeServiceImpl
+	cron    *cron.Cron
+
+	// saving orders saves, so an older snapshot never overwrites a newer one.
+	saving    sync.Mutex
+	mu        sync.Mutex
+	file      string
+	schedules map[string]*scheduleEntry
+	running   map[string]bool
+	runs      []model.BackupRun
+}
+
+type scheduleEntry struct {
+	schedule model.BackupSchedule
+	entryID  cron.EntryID
+}
+
+// scheduleFile is the layout of the schedules file. Files written before the
+// run history was saved hold only the array of schedules.
+type scheduleFile struct {
+	Schedules []model.BackupSchedule `json:"schedules"`
+	Runs      []model.BackupRun      `json:"runs"`
+}
+
+func NewBackupScheduler(service *VolumeServiceImpl) *BackupScheduler {
+	return &BackupScheduler{
+		service:   service,
+		cron:      cron.New(),
+		schedules: make(map[string]*scheduleEntry),
+		running:   make(map[string]bool),
+	}
+}
+
+// Start loads the schedules saved in file, if any, and runs them until ctx
+// is done. An empty file keeps schedules in memory only.
+func (b *BackupScheduler) Start(ctx context.Context, file string) error {
+	b.mu.Lock()
+	b.file = file
+	b.mu.Unlock()
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read backup schedules: %w", err)
+		}
+		saved, err := parseScheduleFile(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse backup schedules in %s: %w", file, err)
+		}
+		for _, s := range saved.Schedules {
+			if err := b.add(s); err != nil {
+				return err
+			}
+		}
+		for i := range saved.Runs {
+			if r := &saved.Runs[i]; r.Status == model.BackupRunRunning {
+				r.Status = model.BackupRunFailed
+				r.Error = errRunInterrupted
+			}
+		}
+		b.mu.Lock()
+		b.runs = saved.Runs
+		b.mu.Unlock()
+	}
+	b.cron.Start()
+	go func() {
+		<-ctx.Done()
+		<-b.cron.Stop().Done()
+	}()
+	return nil
+}
+
+func (b *BackupScheduler) Create(s model.BackupSchedule) (*model.BackupSchedule, error) {
+	s.ID = newID()
+	s.CreatedAt = time.Now().UTC()
+	if s.Target == "" {
+		s.Target = DefaultBackupTarget
+	}
+	if err := b.add(s); err != nil {
+		return nil, err
+	}
+	if err := b.save(); err != nil {
+		// A schedule that is not saved must not keep running either.
+		b.remove(s.ID)
+		return nil, err
+	}
+	return b.Get(s.ID)
+}
+
+func (b *BackupScheduler) add(s model.BackupSchedule) error {
+	spec, err := b.validate(s)
+	if err != nil {
+		return err
+	}
+	id := s.ID
+	job := cron.FuncJob(func() {
+		schedule, done, err := b.begin(id)
+		if err != nil {
+			// Still busy with the previous or a triggered run.
+			return
+		}
+		defer done()
+		b.run(context.Background(), schedule)
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.schedules[id] = &scheduleEntry{schedule: s, entryID: b.cron.Schedule(spec, job)}
+	return nil
+}
+
+func (b *BackupScheduler) validate(s model.BackupSchedule) (cron.Schedule, error) {
+	spec, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cron %q: %v", ErrInvalidSchedule, s.Cron, err)
+	}
+	if (s.Volume == "") == (len(s.Selector) == 0) {
+		return nil, fmt.Errorf("%w: exactly one of volume and selector is required", ErrInvalidSchedule)
+	}
+	if s.Retention.KeepLast < 0 || s.Retention.KeepDaily < 0 || s.Retention.KeepWeekly < 0 {
+		return nil, fmt.Errorf("%w: retention counts cannot be negative", ErrInvalidSchedule)
+	}
+	if _, _, err := b.service.target(s.Target); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	return spec, nil
+}
+
+func (b *BackupScheduler) List() []model.BackupSchedule {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := make([]model.BackupSchedule, 0, len(b.schedules))
+	for _, e := range b.schedules {
+		result = append(result, b.withNextRun(e))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+func (b *BackupScheduler) Get(id string) (*model.BackupSchedule, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.schedules[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+	}
+	s := b.withNextRun(e)
+	return &s, nil
+}
+
+func (b *BackupScheduler) Delete(id string) error {
+	if !b.remove(id) {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+	}
+	return b.save()
+}
+
+// remove unregisters the schedule and reports whether it existed.
+func (b *BackupScheduler) remove(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.schedules[id]
+	if ok {
+		b.cron.Remove(e.entryID)
+		delete(b.schedules, id)
+	}
+	return ok
+}
+
+// Trigger runs the schedule now, in the background. It fails with
+// ErrScheduleRunning while a run of the schedule is in progress.
+func (b *BackupScheduler) Trigger(id string) error {
+	s, done, err := b.begin(id)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer done()
+		b.run(context.Background(), s)
+	}()
+	return nil
+}
+
+// begin marks the schedule as running, so scheduled and triggered runs never
+// overlap, and returns it with the function that ends the run.
+func (b *BackupScheduler) begin(id string) (*model.BackupSchedule, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.schedules[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, id)
+	}
+	if b.running[id] {
+		return nil, nil, fmt.Errorf("%w: %s", ErrScheduleRunning, id)
+	}
+	b.running[id] = true
+	s := e.schedule
+	return &s, func() {
+		b.mu.Lock()
+		delete(b.running, id)
+		b.mu.Unlock()
+	}, nil
+}
+
+// Runs returns the recorded runs matching filter, newest first.
+func (b *BackupScheduler) Runs(filter model.BackupRunFilter) []model.BackupRun {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := []model.BackupRun{}
+	for i := len(b.runs) - 1; i >= 0; i-- {
+		r := b.runs[i]
+		if (filter.ScheduleID == "" || r.ScheduleID == filter.ScheduleID) &&
+			(filter.Volume == "" || r.Volume == filter.Volume) &&
+			(filter.Status == "" || r.Status == filter.Status) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// run backs up every volume the schedule selects, one after the other.
+func (b *BackupScheduler) run(ctx context.Context, s *model.BackupSchedule) {
+	volumes := []string{s.Volume}
+	if s.Volume == "" {
+		var err error
+		if volumes, err = b.selectVolumes(ctx, s.Selector); err != nil {
+			log.Printf("backup schedule %s: failed to select volumes: %v", s.ID, err)
+			return
+		}
+	}
+	for _, volume := range volumes {
+		b.backup(ctx, s, volume)
+	}
+}
+
+func (b *BackupScheduler) selectVolumes(ctx context.Context, selector map[string]string) ([]string, error) {
+	vs, err := b.service.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, v := range vs {
+		if matchSelector(v.Labels, selector) {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (b *BackupScheduler) backup(ctx context.Context, s *model.BackupSchedule, volume string) {
+	runID := newID()
+	b.record(model.BackupRun{
+		ID:         runID,
+		ScheduleID: s.ID,
+		Volume:     volume,
+		Target:     s.Target,
+		Status:     model.BackupRunRunning,
+		StartedAt:  time.Now().UTC(),
+	})
+
+	backup, err := b.service.storeBackup(ctx, volume, s.ID, model.BackupOptions{Target: s.Target, StopContainers: s.StopContainers})
+	var pruned []string
+	var pruneErr error
+	if err == nil {
+		pruned, pruneErr = b.service.applyRetention(ctx, s.Target, volume, s.ID, s.Retention)
+	}
+
+	b.update(runID, func(r *model.BackupRun) {
+		r.FinishedAt = time.Now().UTC()
+		if err != nil {
+			r.Status = model.BackupRunFailed
+			r.Error = err.Error()
+			return
+		}
+		r.Status = model.BackupRunSucceeded
+		r.Target = backup.Target
+		r.BackupID = backup.ID
+		r.Size = backup.Size
+		r.SHA256 = backup.SHA256
+		r.Pruned = pruned
+		if pruneErr != nil {
+			r.Error = "retention: " + pruneErr.Error()
+		}
+	})
+	if err != nil {
+		log.Printf("backup schedule %s: backup of volume %s failed: %v", s.ID, volume, err)
+	}
+}
+
+func (b *BackupScheduler) record(run model.BackupRun) {
+	b.mu.Lock()
+	b.runs = append(b.runs, run)
+	if len(b.runs) > maxBackupRuns {
+		b.runs = append([]model.BackupRun(nil), b.runs[len(b.runs)-maxBackupRuns:]...)
+	}
+	b.mu.Unlock()
+	b.saveRuns()
+}
+
+// update applies fn to the recorded run with the given ID, unless it has
+// been dropped from the history meanwhile.
+func (b *BackupScheduler) update(id string, fn func(*model.BackupRun)) {
+	b.mu.Lock()
+	found := false
+	for i := len(b.runs) - 1; i >= 0; i-- {
+		if b.runs[i].ID == id {
+			fn(&b.runs[i])
+			found = true
+			break
+		}
+	}
+	b.mu.Unlock()
+	if found {
+		b.saveRuns()
+	}
+}
+
+// saveRuns saves the run history. A run is not failed over it, since the
+// backup itself is done; the history is saved again with the next run.
+func (b *BackupScheduler) saveRuns() {
+	if err := b.save(); err != nil {
+		log.Printf("failed to save backup run history: %v", err)
+	}
+}
+
+func (b *BackupScheduler) withNextRun(e *scheduleEntry) model.BackupSchedule {
+	s := e.schedule
+	s.NextRun = b.cron.Entry(e.entryID).Next
+	return s
+}
+
+// save writes the schedules and the run history to the configured file,
+// atomically so a crash never leaves it half written.
+func (b *BackupScheduler) save() error {
+	b.saving.Lock()
+	defer b.saving.Unlock()
+	b.mu.Lock()
+	file := b.file
+	saved := scheduleFile{
+		Schedules: make([]model.BackupSchedule, 0, len(b.schedules)),
+		Runs:      append([]model.BackupRun{}, b.runs...),
+	}
+	for _, e := range b.schedules {
+		s := e.schedule
+		s.NextRun = time.Time{}
+		saved.Schedules = append(saved.Schedules, s)
+	}
+	b.mu.Unlock()
+	if file == "" {
+		return nil
+	}
+	sort.Slice(saved.Schedules, func(i, j int) bool { return saved.Schedules[i].CreatedAt.Before(saved.Schedules[j].CreatedAt) })
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save backup schedules: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save backup schedules: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save backup schedules: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to save backup schedules: %w", err)
+	}
+	return nil
+}
+
+// parseScheduleFile reads a schedules file in either layout.
+func parseScheduleFile(data []byte) (scheduleFile, error) {
+	var saved scheduleFile
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+	case data[0] == '[':
+		err := json.Unmarshal(data, &saved.Schedules)
+		return saved, err
+	default:
+		err := json.Unmarshal(data, &saved)
+		return saved, err
+	}
+	return saved, nil
+}
+
+func matchSelector(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if got, ok := labels[k]; !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
+
+func newID() string {
+	buf := make([]byte, 6)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// StartScheduler runs backup schedules until ctx is done, loading and saving
+// them in file when it is set.
+func (s *VolumeServiceImpl) StartScheduler(ctx context.Context, file string) error {
+	return s.scheduler.Start(ctx, file)
+}
+
+func (s *VolumeServiceImpl) CreateSchedule(_ context.Context, schedule model.BackupSchedule) (*model.BackupSchedule, error) {
+	return s.scheduler.Create(schedule)
+}
+
+func (s *VolumeServiceImpl) ListSchedules(_ context.Context) ([]model.BackupSchedule, error) {
+	return s.scheduler.List(), nil
+}
+
+func (s *VolumeServiceImpl) GetSchedule(_ context.Context, id string) (*model.BackupSchedule, error) {
+	return s.scheduler.Get(id)
+}
+
+func (s *VolumeServiceImpl) DeleteSchedule(_ context.Context, id string) error {
+	return s.scheduler.Delete(id)
+}
+
+// RunSchedule triggers the schedule immediately; its runs show up in the
+// history as they progress.
+func (s *VolumeServiceImpl) RunSchedule(_ context.Context, id string) error {
+	return s.scheduler.Trigger(id)
+}
+
+func (s *VolumeServiceImpl) ListBackupRuns(_ context.Context, filter model.BackupRunFilter) ([]model.BackupRun, error) {
+	return s.scheduler.Runs(filter), nil
+}
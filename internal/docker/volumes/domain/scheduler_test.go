@@ -0,0 +1,239 @@
+package domain_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
+	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
+	"github.com/rivernova/orcahub/internal/docker/volumes/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForRuns(t *testing.T, svc *domain.VolumeServiceImpl, scheduleID string, n int) []model.BackupRun {
+	t.Helper()
+	var runs []model.BackupRun
+	require.Eventually(t, func() bool {
+		runs, _ = svc.ListBackupRuns(context.Background(), model.BackupRunFilter{ScheduleID: scheduleID})
+		done := 0
+		for _, r := range runs {
+			if r.Status != model.BackupRunRunning {
+				done++
+			}
+		}
+		return done == n
+	}, 5*time.Second, 10*time.Millisecond)
+	return runs
+}
+
+func TestVolumeService_CreateSchedule_Invalid(t *testing.T) {
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	ctx := context.Background()
+
+	for name, s := range map[string]model.BackupSchedule{
+		"bad cron":           {Cron: "every day", Volume: "pg-data"},
+		"no volume":          {Cron: "@daily"},
+		"volume and labels":  {Cron: "@daily", Volume: "pg-data", Selector: map[string]string{"backup": "true"}},
+		"negative retention": {Cron: "@daily", Volume: "pg-data", Retention: model.Retention{KeepLast: -1}},
+		"unknown target":     {Cron: "@daily", Volume: "pg-data", Target: "s3"},
+	} {
+		_, err := svc.CreateSchedule(ctx, s)
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule, name)
+	}
+}
+
+func TestVolumeService_Schedule_Persisted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "schedules.json")
+
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	require.NoError(t, svc.StartScheduler(ctx, file))
+	created, err := svc.CreateSchedule(ctx, model.BackupSchedule{Cron: "0 3 * * *", Volume: "pg-data", Retention: model.Retention{KeepDaily: 7}})
+	require.NoError(t, err)
+	assert.Equal(t, "local", created.Target)
+	assert.False(t, created.NextRun.IsZero())
+
+	restarted := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, restarted.UseBackupDir(t.TempDir()))
+	require.NoError(t, restarted.StartScheduler(ctx, file))
+	loaded, err := restarted.GetSchedule(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, loaded.Retention.KeepDaily)
+
+	require.NoError(t, restarted.DeleteSchedule(ctx, created.ID))
+	_, err = restarted.GetSchedule(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrScheduleNotFound)
+	data, _ := os.ReadFile(file)
+	assert.JSONEq(t, `{"schedules": [], "runs": []}`, string(data))
+}
+
+func TestVolumeService_Schedule_RunsPersisted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "schedules.json")
+
+	a := &mockVolumeAdapter{}
+	a.On("CopyFrom", mock.Anything, "pg-data", "/").Return(nil, nil, assert.AnError)
+	svc := domain.NewVolumeServiceImpl(a)
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	require.NoError(t, svc.StartScheduler(ctx, file))
+	schedule, err := svc.CreateSchedule(ctx, model.BackupSchedule{Cron: "@daily", Volume: "pg-data"})
+	require.NoError(t, err)
+	require.NoError(t, svc.RunSchedule(ctx, schedule.ID))
+	run := waitForRuns(t, svc, schedule.ID, 1)[0]
+
+	restarted := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, restarted.UseBackupDir(t.TempDir()))
+	require.NoError(t, restarted.StartScheduler(ctx, file))
+	runs, err := restarted.ListBackupRuns(ctx, model.BackupRunFilter{ScheduleID: schedule.ID})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.ID, runs[0].ID)
+	assert.Equal(t, model.BackupRunFailed, runs[0].Status)
+	assert.Equal(t, run.Error, runs[0].Error)
+	assert.NotEmpty(t, runs[0].Error)
+}
+
+func TestVolumeService_Schedule_LoadsInterruptedRuns(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+		"schedules": [{"ID": "0a1b2c3d4e5f", "Cron": "@daily", "Volume": "pg-data", "Target": "local"}],
+		"runs": [{"ID": "run1", "ScheduleID": "0a1b2c3d4e5f", "Volume": "pg-data", "Status": "running"}]
+	}`), 0o600))
+
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	require.NoError(t, svc.StartScheduler(ctx, file))
+	runs, _ := svc.ListBackupRuns(ctx, model.BackupRunFilter{})
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.BackupRunFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Error, "interrupted")
+}
+
+func TestVolumeService_Schedule_LoadsScheduleArray(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	require.NoError(t, os.WriteFile(file, []byte(`[{"ID": "0a1b2c3d4e5f", "Cron": "@daily", "Volume": "pg-data", "Target": "local"}]`), 0o600))
+
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	require.NoError(t, svc.StartScheduler(ctx, file))
+	_, err := svc.GetSchedule(ctx, "0a1b2c3d4e5f")
+	assert.NoError(t, err)
+}
+
+func TestVolumeService_CreateSchedule_SaveFails(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc := domain.NewVolumeServiceImpl(&mockVolumeAdapter{})
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	require.NoError(t, svc.StartScheduler(ctx, filepath.Join(t.TempDir(), "missing", "schedules.json")))
+
+	_, err := svc.CreateSchedule(ctx, model.BackupSchedule{Cron: "@daily", Volume: "pg-data"})
+	assert.Error(t, err)
+	schedules, _ := svc.ListSchedules(ctx)
+	assert.Empty(t, schedules)
+}
+
+func TestVolumeService_RunSchedule_SelectorAndRetention(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	dir := t.TempDir()
+	require.NoError(t, svc.UseBackupDir(dir))
+	ctx := context.Background()
+
+	a.On("List", mock.Anything).Return([]model.Volume{
+		{Name: "pg-data", Labels: map[string]string{"backup": "nightly"}},
+		{Name: "cache", Labels: map[string]string{"backup": "never"}},
+		{Name: "redis-data", Labels: map[string]string{"backup": "nightly"}},
+	}, nil)
+	a.On("CopyFrom", mock.Anything, "pg-data", "/").
+		Return(volumeArchive(map[string]string{"PG_VERSION": "16"}), &model.VolumeFile{Type: model.FileTypeDir}, nil)
+	a.On("CopyFrom", mock.Anything, "redis-data", "/").
+		Return(nil, nil, assert.AnError)
+
+	schedule, err := svc.CreateSchedule(ctx, model.BackupSchedule{
+		Cron:      "@daily",
+		Selector:  map[string]string{"backup": "nightly"},
+		Retention: model.Retention{KeepLast: 1, KeepDaily: 2},
+	})
+	require.NoError(t, err)
+
+	// Two older backups of pg-data by the schedule on the same day: with
+	// keep_last 1 and keep_daily 2 the newest one survives for its day, the
+	// other goes. Backups on request and by other schedules are not the
+	// schedule's to prune.
+	target, _ := adapter.NewLocalTarget(dir)
+	for _, created := range []time.Time{
+		time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC),
+	} {
+		saveArchive(t, target, "pg-data", schedule.ID, created)
+	}
+	saveArchive(t, target, "pg-data", "", time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	saveArchive(t, target, "pg-data", "0a1b2c3d4e5f", time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, svc.RunSchedule(ctx, schedule.ID))
+
+	runs := waitForRuns(t, svc, schedule.ID, 2)
+	byVolume := map[string]model.BackupRun{}
+	for _, r := range runs {
+		byVolume[r.Volume] = r
+	}
+	pg := byVolume["pg-data"]
+	assert.Equal(t, model.BackupRunSucceeded, pg.Status)
+	assert.NotEmpty(t, pg.BackupID)
+	assert.NotEmpty(t, pg.SHA256)
+	assert.Equal(t, []string{"pg-data-20260101T010000.000Z-" + schedule.ID + ".tar.gz"}, pg.Pruned)
+	assert.Equal(t, model.BackupRunFailed, byVolume["redis-data"].Status)
+	assert.NotEmpty(t, byVolume["redis-data"].Error)
+
+	backups, err := svc.ListBackups(ctx, "pg-data", "")
+	require.NoError(t, err)
+	assert.Len(t, backups, 4)
+
+	failed, _ := svc.ListBackupRuns(ctx, model.BackupRunFilter{Status: model.BackupRunFailed})
+	assert.Len(t, failed, 1)
+}
+
+func TestVolumeService_RunSchedule_AlreadyRunning(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	require.NoError(t, svc.UseBackupDir(t.TempDir()))
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	a.On("CopyFrom", mock.Anything, "pg-data", "/").
+		Run(func(mock.Arguments) { <-release }).
+		Return(volumeArchive(nil), &model.VolumeFile{Type: model.FileTypeDir}, nil).Once()
+
+	schedule, err := svc.CreateSchedule(ctx, model.BackupSchedule{Cron: "@daily", Volume: "pg-data"})
+	require.NoError(t, err)
+	require.NoError(t, svc.RunSchedule(ctx, schedule.ID))
+	assert.ErrorIs(t, svc.RunSchedule(ctx, schedule.ID), domain.ErrScheduleRunning)
+
+	close(release)
+	runs := waitForRuns(t, svc, schedule.ID, 1)
+	assert.Equal(t, model.BackupRunSucceeded, runs[0].Status)
+}
+
+func saveArchive(t *testing.T, target adapter.BackupTarget, volume, schedule string, created time.Time) {
+	t.Helper()
+	_, err := target.Save(context.Background(), volume, schedule, created, func(w io.Writer) (*model.Backup, error) {
+		w.Write([]byte("archive"))
+		return &model.Backup{Volume: volume}, nil
+	})
+	require.NoError(t, err)
+}
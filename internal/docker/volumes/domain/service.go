@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
 )
@@ -11,5 +12,20 @@ type VolumeService interface {
 	Inspect(ctx context.Context, name string) (*model.Volume, error)
 	Create(ctx context.Context, opts model.CreateVolumeOptions) (*model.Volume, error)
 	Delete(ctx context.Context, name string) error
-	Prune(ctx context.Context) (model.PruneResult, error)
+	Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error)
+	ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error)
+	Download(ctx context.Context, name, path string) (*model.VolumeFile, io.ReadCloser, error)
+	Upload(ctx context.Context, name, dir string, files []model.UploadFile) ([]string, error)
+	Backup(ctx context.Context, name string, opts model.BackupOptions, w io.Writer) (*model.Backup, error)
+	StoreBackup(ctx context.Context, name string, opts model.BackupOptions) (*model.Backup, error)
+	ListBackups(ctx context.Context, name, target string) ([]model.Backup, error)
+	Restore(ctx context.Context, name string, archive io.Reader, opts model.RestoreOptions) error
+	RestoreBackup(ctx context.Context, name, id string, opts model.RestoreOptions) error
+	CreateSchedule(ctx context.Context, schedule model.BackupSchedule) (*model.BackupSchedule, error)
+	ListSchedules(ctx context.Context) ([]model.BackupSchedule, error)
+	GetSchedule(ctx context.Context, id string) (*model.BackupSchedule, error)
+	DeleteSchedule(ctx context.Context, id string) error
+	RunSchedule(ctx context.Context, id string) error
+	ListBackupRuns(ctx context.Context, filter model.BackupRunFilter) ([]model.BackupRun, error)
+	Clone(ctx context.Context, name string, opts model.CloneOptions, progress func(model.CloneProgress)) (*model.CloneResult, error)
 }
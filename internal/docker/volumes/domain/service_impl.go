@@ -2,35 +2,74 @@ package domain
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
 	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
 )
 
 type VolumeServiceImpl struct {
-	adapter adapter.VolumeAdapter
+	adapter   adapter.VolumeAdapter
+	usage     *UsageTracker
+	targets   map[string]adapter.BackupTarget
+	scheduler *BackupScheduler
 }
 
 func NewVolumeServiceImpl(adapter adapter.VolumeAdapter) *VolumeServiceImpl {
-	return &VolumeServiceImpl{adapter: adapter}
+	s := &VolumeServiceImpl{adapter: adapter, usage: NewUsageTracker(adapter)}
+	s.scheduler = NewBackupScheduler(s)
+	return s
 }
 
 func (s *VolumeServiceImpl) List(ctx context.Context) ([]model.Volume, error) {
-	return s.adapter.List(ctx)
+	vs, err := s.adapter.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.usage.Annotate(ctx, vs)
+	for i := range vs {
+		describeMount(&vs[i])
+	}
+	return vs, nil
 }
 
 func (s *VolumeServiceImpl) Inspect(ctx context.Context, name string) (*model.Volume, error) {
-	return s.adapter.Inspect(ctx, name)
+	v, err := s.adapter.Inspect(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	annotated := []model.Volume{*v}
+	s.usage.Annotate(ctx, annotated)
+	describeMount(&annotated[0])
+	return &annotated[0], nil
 }
 
 func (s *VolumeServiceImpl) Create(ctx context.Context, opts model.CreateVolumeOptions) (*model.Volume, error) {
-	return s.adapter.Create(ctx, opts)
+	if opts.Preset != nil {
+		if len(opts.DriverOpts) > 0 {
+			return nil, fmt.Errorf("%w: preset and driver options are exclusive", ErrInvalidVolume)
+		}
+		if opts.Driver != "" && opts.Driver != "local" {
+			return nil, fmt.Errorf("%w: presets need the local driver", ErrInvalidVolume)
+		}
+		driverOpts, err := presetDriverOpts(opts.Preset)
+		if err != nil {
+			return nil, err
+		}
+		opts.Driver, opts.DriverOpts, opts.Preset = "local", driverOpts, nil
+	}
+	v, err := s.adapter.Create(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	describeMount(v)
+	return v, nil
 }
 
 func (s *VolumeServiceImpl) Delete(ctx context.Context, name string) error {
-	return s.adapter.Delete(ctx, name)
-}
-
-func (s *VolumeServiceImpl) Prune(ctx context.Context) (model.PruneResult, error) {
-	return s.adapter.Prune(ctx)
+	if err := s.adapter.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.usage.Invalidate()
+	return nil
 }
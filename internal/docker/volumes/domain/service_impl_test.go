@@ -1,8 +1,12 @@
 package domain_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/rivernova/orcahub/internal/docker/volumes/domain"
@@ -35,11 +39,69 @@ func (m *mockVolumeAdapter) Delete(ctx context.Context, name string) error {
 	return m.Called(ctx, name).Error(0)
 }
 
-func (m *mockVolumeAdapter) Prune(ctx context.Context) (model.PruneResult, error) {
-	args := m.Called(ctx)
+func (m *mockVolumeAdapter) Prune(ctx context.Context, opts model.PruneOptions) (model.PruneResult, error) {
+	args := m.Called(ctx, opts)
 	return args.Get(0).(model.PruneResult), args.Error(1)
 }
 
+func (m *mockVolumeAdapter) Usage(ctx context.Context) (map[string]model.VolumeUsage, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]model.VolumeUsage), args.Error(1)
+}
+
+func (m *mockVolumeAdapter) Mounts(ctx context.Context) ([]model.VolumeMount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VolumeMount), args.Error(1)
+}
+
+func (m *mockVolumeAdapter) ListFiles(ctx context.Context, name, dir string) ([]model.VolumeFile, error) {
+	args := m.Called(ctx, name, dir)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.VolumeFile), args.Error(1)
+}
+
+func (m *mockVolumeAdapter) CopyFrom(ctx context.Context, name, path string) (io.ReadCloser, *model.VolumeFile, error) {
+	args := m.Called(ctx, name, path)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(*model.VolumeFile), args.Error(2)
+}
+
+// CopyTo leaves the archive out of the recorded call: it is usually a pipe
+// still being written to, which the mock must not inspect.
+func (m *mockVolumeAdapter) CopyTo(ctx context.Context, name, dir string, archive io.Reader) error {
+	args := m.Called(ctx, name, dir)
+	if fn, ok := args.Get(0).(func(io.Reader) error); ok {
+		return fn(archive)
+	}
+	return args.Error(0)
+}
+
+func (m *mockVolumeAdapter) Replace(ctx context.Context, name string, archive io.Reader) error {
+	args := m.Called(ctx, name)
+	if fn, ok := args.Get(0).(func(io.Reader) error); ok {
+		return fn(archive)
+	}
+	return args.Error(0)
+}
+
+func (m *mockVolumeAdapter) StopContainer(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockVolumeAdapter) StartContainer(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
 func TestVolumeService_List(t *testing.T) {
 	a := &mockVolumeAdapter{}
 	svc := domain.NewVolumeServiceImpl(a)
@@ -50,6 +112,8 @@ func TestVolumeService_List(t *testing.T) {
 		{Name: "redis-data", Driver: "local"},
 	}
 	a.On("List", ctx).Return(expected, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{}, nil)
+	a.On("Mounts", ctx).Return([]model.VolumeMount{}, nil)
 
 	result, err := svc.List(ctx)
 	assert.NoError(t, err)
@@ -57,6 +121,90 @@ func TestVolumeService_List(t *testing.T) {
 	assert.Equal(t, "postgres-data", result[0].Name)
 }
 
+func TestVolumeService_List_Usage(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{
+		{Name: "postgres-data", Driver: "local"},
+		{Name: "cache", Driver: "local"},
+		{Name: "nfs-share", Driver: "nfs"},
+	}, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{
+		"postgres-data": {Size: 4096, RefCount: 1},
+		"cache":         {Size: 0, RefCount: 0},
+	}, nil)
+	a.On("Mounts", ctx).Return([]model.VolumeMount{
+		{VolumeName: "postgres-data", ContainerID: "c2", ContainerName: "pg-replica", Destination: "/var/lib/postgresql/data"},
+		{VolumeName: "postgres-data", ContainerID: "c1", ContainerName: "pg", Destination: "/var/lib/postgresql/data", RW: true},
+	}, nil)
+
+	result, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), result[0].Size)
+	assert.Equal(t, int64(2), result[0].RefCount)
+	assert.True(t, result[0].InUse)
+	assert.Equal(t, "pg", result[0].Containers[0].ContainerName)
+	assert.True(t, result[0].Containers[0].RW)
+	assert.False(t, result[1].InUse)
+	assert.Equal(t, int64(-1), result[2].Size)
+}
+
+func TestVolumeService_List_CachesUsage(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{{Name: "cache"}}, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{"cache": {Size: 10}}, nil).Once()
+	a.On("Mounts", ctx).Return([]model.VolumeMount{}, nil)
+
+	_, err := svc.List(ctx)
+	assert.NoError(t, err)
+	result, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), result[0].Size)
+	a.AssertNumberOfCalls(t, "Usage", 1)
+	a.AssertNumberOfCalls(t, "Mounts", 2)
+}
+
+func TestVolumeService_List_UsageUnavailable(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{{Name: "cache"}}, nil)
+	a.On("Usage", ctx).Return(nil, errors.New("disk usage unavailable"))
+	a.On("Mounts", ctx).Return(nil, errors.New("daemon error"))
+
+	result, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), result[0].Size)
+	assert.Equal(t, int64(-1), result[0].RefCount)
+	assert.True(t, result[0].InUse)
+}
+
+func TestVolumeService_List_UnknownRefCountUsesMounts(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{{Name: "data"}, {Name: "cache"}}, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{
+		"data":  {Size: 10, RefCount: -1},
+		"cache": {Size: 5, RefCount: -1},
+	}, nil)
+	a.On("Mounts", ctx).Return([]model.VolumeMount{{VolumeName: "data", ContainerID: "c1", ContainerName: "app"}}, nil)
+
+	result, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result[0].RefCount)
+	assert.True(t, result[0].InUse)
+	assert.Equal(t, int64(0), result[1].RefCount)
+	assert.False(t, result[1].InUse)
+}
+
 func TestVolumeService_List_Error(t *testing.T) {
 	a := &mockVolumeAdapter{}
 	svc := domain.NewVolumeServiceImpl(a)
@@ -79,10 +227,13 @@ func TestVolumeService_Inspect(t *testing.T) {
 		Mountpoint: "/var/lib/docker/volumes/postgres-data/_data",
 	}
 	a.On("Inspect", ctx, "postgres-data").Return(expected, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{"postgres-data": {Size: 2048, RefCount: 0}}, nil)
+	a.On("Mounts", ctx).Return([]model.VolumeMount{}, nil)
 
 	result, err := svc.Inspect(ctx, "postgres-data")
 	assert.NoError(t, err)
 	assert.Equal(t, "/var/lib/docker/volumes/postgres-data/_data", result.Mountpoint)
+	assert.Equal(t, int64(2048), result.Size)
 }
 
 func TestVolumeService_Inspect_NotFound(t *testing.T) {
@@ -134,9 +285,164 @@ func TestVolumeService_Prune(t *testing.T) {
 	ctx := context.Background()
 
 	expected := model.PruneResult{Deleted: []string{"v1"}, SpaceReclaimed: 1234}
-	a.On("Prune", ctx).Return(expected, nil)
+	a.On("Prune", ctx, model.PruneOptions{}).Return(expected, nil)
 
-	result, err := svc.Prune(ctx)
+	result, err := svc.Prune(ctx, model.PruneOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestVolumeService_Prune_DryRun(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	anon := map[string]string{model.AnonymousLabel: ""}
+	a.On("List", ctx).Return([]model.Volume{
+		{Name: "f00d", Driver: "local", Labels: anon},
+		{Name: "beef", Driver: "local", Labels: map[string]string{model.AnonymousLabel: "", "env": "ci"}},
+		{Name: "in-use", Driver: "local", Labels: anon},
+		{Name: "pg-data", Driver: "local", Labels: map[string]string{"env": "dev"}},
+		{Name: "pg-keep", Driver: "local", Labels: map[string]string{"env": "dev", "keep": "true"}},
+	}, nil)
+	a.On("Mounts", ctx).Return([]model.VolumeMount{{VolumeName: "in-use", ContainerName: "app"}}, nil)
+	a.On("Usage", ctx).Return(map[string]model.VolumeUsage{
+		"f00d": {Size: 100}, "beef": {Size: 20}, "pg-data": {Size: 5000},
+	}, nil)
+
+	// Anonymous only by default; named and in-use volumes are kept.
+	result, err := svc.Prune(ctx, model.PruneOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []string{"beef", "f00d"}, result.Deleted)
+	assert.Equal(t, int64(120), result.SpaceReclaimed)
+
+	result, err = svc.Prune(ctx, model.PruneOptions{All: true, Labels: []string{"env=dev"}, ExcludeLabels: []string{"keep"}, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pg-data"}, result.Deleted)
+	assert.Equal(t, int64(5000), result.Volumes[0].Size)
+
+	a.AssertNotCalled(t, "Prune", mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Prune_DryRunNeedsMounts(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("List", ctx).Return([]model.Volume{{Name: "pg-data"}}, nil)
+	a.On("Mounts", ctx).Return(nil, errors.New("daemon unavailable"))
+
+	_, err := svc.Prune(ctx, model.PruneOptions{All: true, DryRun: true})
+	assert.Error(t, err)
+}
+
+func TestVolumeService_ListFiles(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	// The path is cleaned against the volume root so it cannot escape it.
+	a.On("ListFiles", ctx, "app-config", "/etc").Return([]model.VolumeFile{
+		{Name: "nginx.conf", Type: model.FileTypeFile},
+		{Name: "conf.d", Type: model.FileTypeDir},
+		{Name: "a.conf", Type: model.FileTypeFile},
+	}, nil)
+
+	files, err := svc.ListFiles(ctx, "app-config", "../../etc/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"conf.d", "a.conf", "nginx.conf"}, []string{files[0].Name, files[1].Name, files[2].Name})
+}
+
+func TestVolumeService_Download_File(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: "app.conf", Mode: 0o644, Size: 9})
+	tw.Write([]byte("debug=off"))
+	tw.Close()
+	a.On("CopyFrom", ctx, "app-config", "/app.conf").
+		Return(io.NopCloser(&archive), &model.VolumeFile{Name: "app.conf", Type: model.FileTypeFile, Size: 9}, nil)
+
+	file, reader, err := svc.Download(ctx, "app-config", "app.conf")
+	assert.NoError(t, err)
+	defer reader.Close()
+	content, _ := io.ReadAll(reader)
+	assert.Equal(t, "app.conf", file.Name)
+	assert.Equal(t, "debug=off", string(content))
+}
+
+func TestVolumeService_Download_Directory(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	a.On("CopyFrom", ctx, "app-config", "/").
+		Return(io.NopCloser(strings.NewReader("archive")), &model.VolumeFile{Name: "app-config", Type: model.FileTypeDir}, nil)
+
+	_, reader, err := svc.Download(ctx, "app-config", "")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(reader)
+	assert.Equal(t, "archive", string(content))
+}
+
+func TestVolumeService_Upload(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	var names, contents []string
+	a.On("CopyTo", ctx, "app-config", "/conf.d").Return(func(archive io.Reader) error {
+		tr := tar.NewReader(archive)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			data, _ := io.ReadAll(tr)
+			names = append(names, hdr.Name)
+			contents = append(contents, string(data))
+		}
+	})
+
+	paths, err := svc.Upload(ctx, "app-config", "/conf.d", []model.UploadFile{
+		{Name: "site.conf", Size: 6, Content: strings.NewReader("listen")},
+		{Name: "ssl.conf", Size: 3, Content: strings.NewReader("ssl")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/conf.d/site.conf", "/conf.d/ssl.conf"}, paths)
+	assert.Equal(t, []string{"site.conf", "ssl.conf"}, names)
+	assert.Equal(t, []string{"listen", "ssl"}, contents)
+}
+
+func TestVolumeService_Upload_InvalidName(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	for _, name := range []string{"", "..", "../etc/passwd"} {
+		_, err := svc.Upload(ctx, "app-config", "/", []model.UploadFile{{Name: name, Content: strings.NewReader("")}})
+		assert.ErrorIs(t, err, domain.ErrInvalidPath)
+	}
+	a.AssertNotCalled(t, "CopyTo", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVolumeService_Upload_CopyError(t *testing.T) {
+	a := &mockVolumeAdapter{}
+	svc := domain.NewVolumeServiceImpl(a)
+	ctx := context.Background()
+
+	// The adapter fails without reading the archive; Upload must not hang.
+	a.On("CopyTo", ctx, "app-config", "/missing").Return(errors.New("no such directory"))
+
+	_, err := svc.Upload(ctx, "app-config", "/missing", []model.UploadFile{
+		{Name: "big.bin", Size: 1 << 20, Content: bytes.NewReader(make([]byte, 1<<20))},
+	})
+	assert.Error(t, err)
+}
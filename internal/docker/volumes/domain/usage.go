@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rivernova/orcahub/internal/docker/volumes/adapter"
+	model "github.com/rivernova/orcahub/internal/docker/volumes/model"
+)
+
+// usageTTL bounds how stale reported volume sizes may be. Computing them
+// makes the daemon walk every volume on disk, so they are not refreshed on
+// every request.
+const usageTTL = 30 * time.Second
+
+// UsageTracker caches the daemon's volume disk usage. Mounts are cheap to
+// list and change far more often, so they are always read fresh.
+type UsageTracker struct {
+	adapter adapter.VolumeAdapter
+	now     func() time.Time
+
+	// refresh serializes disk usage calls so concurrent requests on a cold
+	// cache wait for a single walk instead of each starting their own.
+	refresh   sync.Mutex
+	mu        sync.Mutex
+	usage     map[string]model.VolumeUsage
+	fetchedAt time.Time
+}
+
+func NewUsageTracker(adapter adapter.VolumeAdapter) *UsageTracker {
+	return &UsageTracker{adapter: adapter, now: time.Now}
+}
+
+func (t *UsageTracker) cached() (map[string]model.VolumeUsage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.usage == nil || t.now().Sub(t.fetchedAt) >= usageTTL {
+		return nil, false
+	}
+	return t.usage, true
+}
+
+// Usage returns the disk usage of every volume, keyed by name.
+func (t *UsageTracker) Usage(ctx context.Context) (map[string]model.VolumeUsage, error) {
+	if usage, ok := t.cached(); ok {
+		return usage, nil
+	}
+	t.refresh.Lock()
+	defer t.refresh.Unlock()
+	if usage, ok := t.cached(); ok {
+		return usage, nil
+	}
+
+	usage, err := t.adapter.Usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.usage, t.fetchedAt = usage, t.now()
+	t.mu.Unlock()
+	return usage, nil
+}
+
+// Invalidate drops the cached usage, for use after volumes are removed or
+// their contents change.
+func (t *UsageTracker) Invalidate() {
+	t.mu.Lock()
+	t.usage = nil
+	t.mu.Unlock()
+}
+
+// Annotate fills in size, reference count and mounting containers on vs.
+// Usage data is best effort: when the daemon cannot provide it the volumes
+// are returned with unknown sizes rather than failing the request. The
+// reference count comes from the container mounts, which are always fresh,
+// and only from the daemon's usage data when they cannot be listed. A volume
+// whose references are unknown either way is reported as in use, so nothing
+// mistakes it for an unused one.
+func (t *UsageTracker) Annotate(ctx context.Context, vs []model.Volume) {
+	usage, _ := t.Usage(ctx)
+	mounts, mountsErr := t.adapter.Mounts(ctx)
+
+	byVolume := make(map[string][]model.VolumeMount)
+	for _, m := range mounts {
+		byVolume[m.VolumeName] = append(byVolume[m.VolumeName], m)
+	}
+	for i := range vs {
+		v := &vs[i]
+		v.Size, v.RefCount = -1, -1
+		if u, ok := usage[v.Name]; ok {
+			v.Size, v.RefCount = u.Size, u.RefCount
+		}
+		v.Containers = byVolume[v.Name]
+		sort.Slice(v.Containers, func(a, b int) bool {
+			return v.Containers[a].ContainerName < v.Containers[b].ContainerName
+		})
+		if mountsErr == nil {
+			v.RefCount = int64(len(v.Containers))
+		}
+		v.InUse = v.RefCount != 0
+	}
+}
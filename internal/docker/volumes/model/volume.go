@@ -1,5 +1,13 @@
 package model
 
+import (
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"time"
+)
+
 type Volume struct {
 	Name       string
 	Driver     string
@@ -9,6 +17,46 @@ type Volume struct {
 	Scope      string
 	CreatedAt  string
 	Status     map[string]interface{}
+	Size       int64 // bytes on disk, -1 when the driver does not report it
+	RefCount   int64 // containers referencing the volume, -1 when unknown
+	InUse      bool  // true unless RefCount is known to be 0
+	Containers []VolumeMount
+	// Mount is the decoded local driver mount, nil for plain volumes.
+	Mount *MountInfo
+}
+
+// RedactedOptions returns the driver options with mount passwords masked.
+// Options of local driver volumes hold them in plain text.
+func (v Volume) RedactedOptions() map[string]string {
+	o, ok := v.Options["o"]
+	if !ok {
+		return v.Options
+	}
+	opts := strings.Split(o, ",")
+	for i, opt := range opts {
+		if key, _, _ := strings.Cut(opt, "="); key == "password" || key == "pass" {
+			opts[i] = key + "=********"
+		}
+	}
+	redacted := maps.Clone(v.Options)
+	redacted["o"] = strings.Join(opts, ",")
+	return redacted
+}
+
+// VolumeUsage is the disk usage the daemon reports for a volume.
+type VolumeUsage struct {
+	Size     int64
+	RefCount int64
+}
+
+// VolumeMount is a container mounting a volume.
+type VolumeMount struct {
+	VolumeName    string
+	ContainerID   string
+	ContainerName string
+	State         string
+	Destination   string
+	RW            bool
 }
 
 type CreateVolumeOptions struct {
@@ -16,9 +64,238 @@ type CreateVolumeOptions struct {
 	Driver     string
 	DriverOpts map[string]string
 	Labels     map[string]string
+	// Preset builds DriverOpts for the local driver; it cannot be combined
+	// with raw DriverOpts.
+	Preset *MountPreset
+}
+
+const (
+	MountTypeNFS   = "nfs"
+	MountTypeCIFS  = "cifs"
+	MountTypeBind  = "bind"
+	MountTypeTmpfs = "tmpfs"
+)
+
+// MountPreset describes a local driver volume backed by a mount. Server and
+// Path are the NFS server and export, the CIFS server and share, or the host
+// directory of a bind mount. Options are extra mount options.
+//
+// The local driver takes mount options as one comma separated string, so no
+// value may contain a comma, and it stores them as given: a CIFS Password
+// can be read back by anyone with access to the Docker daemon. Leave it out
+// and pass "credentials=<file>" in Options to use a credentials file on the
+// host instead.
+type MountPreset struct {
+	Type     string
+	Server   string
+	Path     string
+	Version  string // NFS or SMB protocol version
+	Username string // CIFS
+	Password string // CIFS
+	Domain   string // CIFS
+	Size     string // tmpfs, e.g. "64m"
+	Mode     string // tmpfs, octal
+	UID      *int   // tmpfs
+	GID      *int   // tmpfs
+	ReadOnly bool
+	Options  []string
+}
+
+// MountInfo is a decoded local driver mount. Credentials are never
+// included; Options are the remaining mount options.
+type MountInfo struct {
+	Type     string
+	Server   string
+	Path     string
+	Username string
+	ReadOnly bool
+	Options  []string
+}
+
+// AnonymousLabel marks volumes the daemon created without a name, for
+// example for a VOLUME in an image. Daemons older than 23.0 do not set it.
+const AnonymousLabel = "com.docker.volume.anonymous"
+
+// PruneOptions narrows a prune. Labels and ExcludeLabels take "key" or
+// "key=value" entries; a volume must match every label and none of the
+// excluded ones. Without All only anonymous volumes are removed.
+type PruneOptions struct {
+	Labels        []string
+	ExcludeLabels []string
+	All           bool
+	DryRun        bool
 }
 
 type PruneResult struct {
 	Deleted        []string `json:"deleted"`
 	SpaceReclaimed int64    `json:"space_reclaimed"`
+	DryRun         bool     `json:"dry_run,omitempty"`
+	// Volumes details what a dry run would delete.
+	Volumes []PrunedVolume `json:"volumes,omitempty"`
+}
+
+// PrunedVolume is a prune candidate. Size is -1 when unknown.
+type PrunedVolume struct {
+	Name   string            `json:"name"`
+	Driver string            `json:"driver"`
+	Size   int64             `json:"size"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+const (
+	FileTypeFile    = "file"
+	FileTypeDir     = "dir"
+	FileTypeSymlink = "symlink"
+	FileTypeOther   = "other"
+)
+
+// VolumeFile is an entry in a volume's filesystem. Path is absolute from the
+// volume root.
+type VolumeFile struct {
+	Name       string
+	Path       string
+	Type       string
+	Size       int64
+	Mode       os.FileMode
+	ModTime    time.Time
+	UID        int
+	GID        int
+	LinkTarget string
+}
+
+// UploadFile is a file to write into a volume.
+type UploadFile struct {
+	Name    string
+	Size    int64
+	Mode    int64
+	Content io.Reader
+}
+
+// Backup is a gzip-compressed tar archive of a volume's contents. ID is the
+// archive's name in its target; streamed backups have none.
+type Backup struct {
+	ID     string
+	Volume string
+	Target string
+	Size   int64
+	SHA256 string
+	// ScheduleID is the ID of the schedule that took the backup, empty for
+	// backups taken on request.
+	ScheduleID string
+	CreatedAt  time.Time
+}
+
+type BackupOptions struct {
+	// Target names where a stored backup goes; empty means the default one.
+	Target string
+	// StopContainers stops the running containers using the volume for the
+	// duration of the backup and starts them again afterwards.
+	StopContainers bool
+}
+
+type RestoreOptions struct {
+	// Target names where a stored backup is restored from.
+	Target         string
+	StopContainers bool
+	// Clean replaces the volume's contents with the archive instead of
+	// extracting it over them. The old contents are kept if extraction fails.
+	Clean bool
+	// SHA256, when set, is the expected checksum of the uploaded archive.
+	SHA256 string
+	// SkipVerify restores a stored backup that has no recorded checksum.
+	SkipVerify bool
+}
+
+// Retention selects which backups a schedule took of a volume survive
+// pruning. A backup is kept when any rule keeps it; with every rule at zero
+// nothing is pruned.
+type Retention struct {
+	KeepLast   int // the newest n backups
+	KeepDaily  int // the newest backup of each of the last n days with one
+	KeepWeekly int // the newest backup of each of the last n ISO weeks with one
+}
+
+// BackupSchedule backs up a single volume, or every volume matching
+// Selector, each time Cron fires.
+type BackupSchedule struct {
+	ID             string
+	Cron           string
+	Volume         string
+	Selector       map[string]string
+	Target         string
+	Retention      Retention
+	StopContainers bool
+	CreatedAt      time.Time
+	NextRun        time.Time
+}
+
+const (
+	BackupRunRunning   = "running"
+	BackupRunSucceeded = "succeeded"
+	BackupRunFailed    = "failed"
+)
+
+// BackupRun is one backup of one volume taken by a schedule.
+type BackupRun struct {
+	ID         string
+	ScheduleID string
+	Volume     string
+	Target     string
+	Status     string
+	BackupID   string
+	Size       int64
+	SHA256     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Error      string
+	// Pruned lists the backups removed by the schedule's retention policy.
+	Pruned []string
+}
+
+func (r BackupRun) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+type BackupRunFilter struct {
+	ScheduleID string
+	Volume     string
+	Status     string
+}
+
+// CloneOptions describe the volume a clone is copied into. An empty Driver
+// keeps the source's driver; driver options are never copied, since they
+// usually point at the source's storage, so a source created with options
+// can only be cloned to the same driver with DriverOpts set.
+type CloneOptions struct {
+	Name           string
+	Driver         string
+	DriverOpts     map[string]string
+	Labels         map[string]string
+	StopContainers bool
+}
+
+const (
+	ClonePhaseCreating = "creating"
+	ClonePhaseCopying  = "copying"
+	ClonePhaseDone     = "done"
+)
+
+type CloneProgress struct {
+	Phase       string
+	FilesCopied int
+	BytesCopied int64
+	// BytesTotal is the source's size on disk, -1 when unknown. Sizes on
+	// disk include filesystem overhead, so BytesCopied may end below it.
+	BytesTotal int64
+}
+
+type CloneResult struct {
+	Source      string
+	Volume      *Volume
+	FilesCopied int
+	BytesCopied int64
+	Duration    time.Duration
 }
@@ -0,0 +1,35 @@
+package httputil
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadReader returns the uploaded file of a request: the "file" field of a
+// multipart form, or the raw body for any other content type. Multipart
+// parts are streamed rather than buffered, so large archives can be sent
+// either way.
+func UploadReader(c *gin.Context) (io.Reader, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("multipart field \"file\" is required")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	containerrouter "github.com/rivernova/orcahub/internal/docker/containers/api/router"
+	eventrouter "github.com/rivernova/orcahub/internal/docker/events/api/router"
 	imagerouter "github.com/rivernova/orcahub/internal/docker/images/api/router"
 	networkrouter "github.com/rivernova/orcahub/internal/docker/networks/api/router"
 	volumerouter "github.com/rivernova/orcahub/internal/docker/volumes/api/router"
@@ -11,6 +12,7 @@ import (
 	systemrouter "github.com/rivernova/orcahub/internal/system"
 
 	containerapi "github.com/rivernova/orcahub/internal/docker/containers/api"
+	eventapi "github.com/rivernova/orcahub/internal/docker/events/api"
 	imageapi "github.com/rivernova/orcahub/internal/docker/images/api"
 	networkapi "github.com/rivernova/orcahub/internal/docker/networks/api"
 	volumeapi "github.com/rivernova/orcahub/internal/docker/volumes/api"
@@ -23,6 +25,7 @@ type Handlers struct {
 	Images     *imageapi.Handler
 	Volumes    *volumeapi.Handler
 	Networks   *networkapi.Handler
+	Events     *eventapi.Handler
 
 	//K8s
 
@@ -45,6 +48,7 @@ func SetupRouter(handlers *Handlers) *gin.Engine {
 			imagerouter.Register(docker, handlers.Images)
 			volumerouter.Register(docker, handlers.Volumes)
 			networkrouter.Register(docker, handlers.Networks)
+			eventrouter.Register(docker, handlers.Events)
 		}
 	}
 
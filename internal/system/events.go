@@ -0,0 +1,171 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/gin-gonic/gin"
+
+	response "github.com/rivernova/orcahub/internal/system/response"
+)
+
+// EventSource streams Docker events; *client.Client is one.
+type EventSource interface {
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+}
+
+// UseEventSource replaces the Docker client as the source of Events.
+func (h *Handler) UseEventSource(src EventSource) {
+	h.events = src
+}
+
+var eventTypes = []events.Type{
+	events.BuilderEventType, events.ConfigEventType, events.ContainerEventType,
+	events.DaemonEventType, events.ImageEventType, events.NetworkEventType,
+	events.NodeEventType, events.PluginEventType, events.SecretEventType,
+	events.ServiceEventType, events.VolumeEventType,
+}
+
+// eventFilterParams maps query parameters to Docker event filters. Each may
+// be repeated; values of one filter are ORed and different filters ANDed.
+var eventFilterParams = map[string]string{
+	"type":      "type",
+	"action":    "event",
+	"container": "container",
+	"image":     "image",
+	"volume":    "volume",
+	"network":   "network",
+	"label":     "label",
+}
+
+// Events — GET /api/system/events (SSE stream)
+//
+// Filters: type, action, container, image, volume, network and label, e.g.
+// ?type=container&action=die&label=com.docker.compose.project=shop. since and
+// until take a Unix timestamp, an RFC 3339 date or a duration ago such as
+// "10m"; with since, past events are replayed first, and with until the
+// stream ends there. Each event's SSE id is its time in nanoseconds, so a
+// reconnecting EventSource resumes after the last event it saw through the
+// Last-Event-ID header.
+func (h *Handler) Events(c *gin.Context) {
+	if h.events == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "docker client not available"})
+		return
+	}
+	opts, lastID, err := eventOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	eventsChan, errChan := h.events.Events(ctx, opts)
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-eventsChan:
+			if !ok {
+				return false
+			}
+			// since has second precision on older daemons; drop what the
+			// client already has.
+			if event.TimeNano <= lastID {
+				return true
+			}
+			data, err := json.Marshal(toEvent(event))
+			if err != nil {
+				return true
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.TimeNano, data)
+			return true
+		case err, ok := <-errChan:
+			// The daemon closes the stream once until has passed.
+			if ok && err != nil && !errors.Is(err, io.EOF) {
+				data, _ := json.Marshal(gin.H{"error": err.Error()})
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			}
+			return false
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+// eventOptions reads the filters and time bounds of an events request. A
+// Last-Event-ID header, or last_event_id parameter for clients that cannot
+// set headers, overrides since.
+func eventOptions(c *gin.Context) (events.ListOptions, int64, error) {
+	args := filters.NewArgs()
+	for param, filter := range eventFilterParams {
+		for _, value := range c.QueryArray(param) {
+			if value == "" {
+				continue
+			}
+			if param == "type" && !slices.Contains(eventTypes, events.Type(value)) {
+				return events.ListOptions{}, 0, fmt.Errorf("unknown event type %q", value)
+			}
+			args.Add(filter, value)
+		}
+	}
+	opts := events.ListOptions{Since: c.Query("since"), Until: c.Query("until"), Filters: args}
+
+	now := time.Now()
+	for name, value := range map[string]string{"since": opts.Since, "until": opts.Until} {
+		if value == "" {
+			continue
+		}
+		if _, err := timetypes.GetTimestamp(value, now); err != nil {
+			return events.ListOptions{}, 0, fmt.Errorf("invalid %s %q", name, value)
+		}
+	}
+
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return opts, 0, nil
+	}
+	lastID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || lastID <= 0 {
+		return events.ListOptions{}, 0, fmt.Errorf("invalid Last-Event-ID %q", raw)
+	}
+	opts.Since = fmt.Sprintf("%d.%09d", lastID/int64(time.Second), lastID%int64(time.Second))
+	return opts, lastID, nil
+}
+
+func toEvent(m events.Message) response.Event {
+	attributes := m.Actor.Attributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return response.Event{
+		Type:   string(m.Type),
+		Action: string(m.Action),
+		Actor: response.EventActor{
+			ID:         m.Actor.ID,
+			Name:       attributes["name"],
+			Attributes: attributes,
+		},
+		Scope:    m.Scope,
+		Time:     m.Time,
+		TimeNano: m.TimeNano,
+	}
+}
@@ -0,0 +1,143 @@
+package system_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/gin-gonic/gin"
+	systemapi "github.com/rivernova/orcahub/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEvents replays messages then ends the stream with end.
+type fakeEvents struct {
+	messages []events.Message
+	end      error
+	opts     events.ListOptions
+}
+
+func (f *fakeEvents) Events(ctx context.Context, opts events.ListOptions) (<-chan events.Message, <-chan error) {
+	f.opts = opts
+	msgs := make(chan events.Message)
+	errs := make(chan error, 1)
+	go func() {
+		// Unbuffered, so each message is taken before the stream ends.
+		for _, m := range f.messages {
+			select {
+			case msgs <- m:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errs <- f.end
+	}()
+	return msgs, errs
+}
+
+// streamRecorder adds the CloseNotifier gin's Stream needs.
+type streamRecorder struct{ *httptest.ResponseRecorder }
+
+func (streamRecorder) CloseNotify() <-chan bool { return make(chan bool) }
+
+func serveEvents(src *fakeEvents, req *http.Request) *httptest.ResponseRecorder {
+	r := gin.New()
+	h := systemapi.NewHandler()
+	h.UseEventSource(src)
+	r.GET("/events", h.Events)
+	w := streamRecorder{httptest.NewRecorder()}
+	r.ServeHTTP(w, req)
+	return w.ResponseRecorder
+}
+
+func TestSystemHandler_Events_EncodesJSON(t *testing.T) {
+	src := &fakeEvents{end: io.EOF, messages: []events.Message{{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor: events.Actor{ID: "c1", Attributes: map[string]string{
+			"name": `we"ird\name`, "exitCode": "137", "com.docker.compose.project": "shop",
+		}},
+		Scope:    "local",
+		Time:     1700000000,
+		TimeNano: 1700000000123456789,
+	}}}
+
+	w := serveEvents(src, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "id: 1700000000123456789\n")
+	assert.NotContains(t, body, "event: error")
+
+	var data string
+	for _, line := range strings.Split(body, "\n") {
+		if rest, ok := strings.CutPrefix(line, "data: "); ok {
+			data = rest
+		}
+	}
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(data), &event))
+	actor := event["actor"].(map[string]interface{})
+	assert.Equal(t, `we"ird\name`, actor["name"])
+	assert.Equal(t, "137", actor["attributes"].(map[string]interface{})["exitCode"])
+	assert.Equal(t, "die", event["action"])
+	assert.Equal(t, float64(1700000000), event["time"])
+}
+
+func TestSystemHandler_Events_Filters(t *testing.T) {
+	src := &fakeEvents{end: io.EOF}
+
+	w := serveEvents(src, httptest.NewRequest(http.MethodGet,
+		"/events?type=container&type=network&action=die&container=web&label=env%3Dprod&since=1700000000&until=1700000600", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.ElementsMatch(t, []string{"container", "network"}, src.opts.Filters.Get("type"))
+	assert.Equal(t, []string{"die"}, src.opts.Filters.Get("event"))
+	assert.Equal(t, []string{"web"}, src.opts.Filters.Get("container"))
+	assert.Equal(t, []string{"env=prod"}, src.opts.Filters.Get("label"))
+	assert.Equal(t, "1700000000", src.opts.Since)
+	assert.Equal(t, "1700000600", src.opts.Until)
+}
+
+func TestSystemHandler_Events_BadRequest(t *testing.T) {
+	for _, query := range []string{"type=bogus", "since=yesterday", "until=soon"} {
+		src := &fakeEvents{end: io.EOF}
+		w := serveEvents(src, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w := serveEvents(&fakeEvents{end: io.EOF}, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSystemHandler_Events_ResumesFromLastEventID(t *testing.T) {
+	src := &fakeEvents{end: io.EOF, messages: []events.Message{
+		{Type: events.ContainerEventType, Action: events.ActionStart, TimeNano: 1700000000000000100},
+		{Type: events.ContainerEventType, Action: events.ActionStop, TimeNano: 1700000000000000200},
+	}}
+	req := httptest.NewRequest(http.MethodGet, "/events?since=1h", nil)
+	req.Header.Set("Last-Event-ID", "1700000000000000100")
+
+	w := serveEvents(src, req)
+
+	assert.Equal(t, "1700000000.000000100", src.opts.Since)
+	body := w.Body.String()
+	assert.NotContains(t, body, "id: 1700000000000000100\n")
+	assert.Contains(t, body, "id: 1700000000000000200\n")
+}
+
+func TestSystemHandler_Events_StreamError(t *testing.T) {
+	src := &fakeEvents{end: assert.AnError}
+
+	w := serveEvents(src, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Contains(t, w.Body.String(), "event: error\ndata: {\"error\":")
+}
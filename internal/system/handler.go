@@ -2,8 +2,6 @@ package system
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"os/exec"
@@ -11,16 +9,23 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 	"github.com/gin-gonic/gin"
 
+	volumemodel "github.com/rivernova/orcahub/internal/docker/volumes/model"
 	response "github.com/rivernova/orcahub/internal/system/response"
 )
 
 type Handler struct {
-	client *client.Client
+	client  *client.Client
+	volumes VolumePruner
+	events  EventSource
+}
+
+// VolumePruner removes unused volumes; see the volumes service.
+type VolumePruner interface {
+	Prune(ctx context.Context, opts volumemodel.PruneOptions) (volumemodel.PruneResult, error)
 }
 
 func NewHandler() *Handler {
@@ -28,7 +33,12 @@ func NewHandler() *Handler {
 	if err != nil {
 		return &Handler{}
 	}
-	return &Handler{client: cli}
+	return &Handler{client: cli, events: cli}
+}
+
+// UseVolumePruner lets Prune remove volumes. Without one it refuses to.
+func (h *Handler) UseVolumePruner(p VolumePruner) {
+	h.volumes = p
 }
 
 // Status — GET /api/system/status
@@ -145,31 +155,67 @@ func (h *Handler) DiskUsage(c *gin.Context) {
 	})
 }
 
+type pruneRequest struct {
+	// Volumes are only pruned on request, and then only anonymous ones
+	// unless AllVolumes is set.
+	Volumes    bool `form:"volumes"`
+	AllVolumes bool `form:"all_volumes"`
+	// Label filters take "key" or "key=value" and apply to every type.
+	Label        []string `form:"label"`
+	ExcludeLabel []string `form:"exclude_label"`
+}
+
 // Prune — POST /api/system/prune
 func (h *Handler) Prune(c *gin.Context) {
 	if h.client == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "docker client not available"})
 		return
 	}
+	var req pruneRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Volumes && h.volumes == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "volume pruning not available"})
+		return
+	}
 
 	ctx := c.Request.Context()
 	var totalReclaimed uint64
 
-	containerReport, _ := h.client.ContainersPrune(ctx, filters.Args{})
+	labels := filters.NewArgs()
+	for _, l := range req.Label {
+		labels.Add("label", l)
+	}
+	for _, l := range req.ExcludeLabel {
+		labels.Add("label!", l)
+	}
+
+	containerReport, _ := h.client.ContainersPrune(ctx, labels)
 	containersDeleted := containerReport.ContainersDeleted
 	totalReclaimed += containerReport.SpaceReclaimed
 
-	imageReport, _ := h.client.ImagesPrune(ctx, filters.NewArgs(
-		filters.Arg("dangling", "false"),
-	))
+	imageFilters := labels.Clone()
+	imageFilters.Add("dangling", "false")
+	imageReport, _ := h.client.ImagesPrune(ctx, imageFilters)
 	imagesDeleted := len(imageReport.ImagesDeleted)
 	totalReclaimed += imageReport.SpaceReclaimed
 
-	volumeReport, _ := h.client.VolumesPrune(ctx, filters.Args{})
-	volumesDeleted := volumeReport.VolumesDeleted
-	totalReclaimed += volumeReport.SpaceReclaimed
+	volumesDeleted := []string{}
+	if req.Volumes {
+		volumeReport, _ := h.volumes.Prune(ctx, volumemodel.PruneOptions{
+			Labels:        req.Label,
+			ExcludeLabels: req.ExcludeLabel,
+			All:           req.AllVolumes,
+		})
+		if volumeReport.Deleted != nil {
+			volumesDeleted = volumeReport.Deleted
+		}
+		totalReclaimed += uint64(volumeReport.SpaceReclaimed)
+	}
 
-	networkReport, _ := h.client.NetworksPrune(ctx, filters.Args{})
+	networkReport, _ := h.client.NetworksPrune(ctx, labels)
 	networksDeleted := networkReport.NetworksDeleted
 
 	c.JSON(http.StatusOK, gin.H{
@@ -181,49 +227,6 @@ func (h *Handler) Prune(c *gin.Context) {
 	})
 }
 
-// Events — GET /api/system/events (SSE stream)
-func (h *Handler) Events(c *gin.Context) {
-	if h.client == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "docker client not available"})
-		return
-	}
-
-	c.Header("Content-Type", "text/event-stream")
-	c.Header("Cache-Control", "no-cache")
-	c.Header("Connection", "keep-alive")
-	c.Header("X-Accel-Buffering", "no")
-
-	ctx, cancel := context.WithCancel(c.Request.Context())
-	defer cancel()
-
-	eventsChan, errChan := h.client.Events(ctx, events.ListOptions{})
-
-	c.Stream(func(w io.Writer) bool {
-		select {
-		case event, ok := <-eventsChan:
-			if !ok {
-				return false
-			}
-			data := fmt.Sprintf(
-				`{"type":"%s","action":"%s","actor":{"id":"%s","name":"%s"},"time":%d}`,
-				event.Type, event.Action,
-				event.Actor.ID,
-				event.Actor.Attributes["name"],
-				event.Time,
-			)
-			fmt.Fprintf(w, "data: %s\n\n", data)
-			return true
-		case err, ok := <-errChan:
-			if ok && err != nil {
-				fmt.Fprintf(w, "data: {\"error\":\"%s\"}\n\n", err.Error())
-			}
-			return false
-		case <-ctx.Done():
-			return false
-		}
-	})
-}
-
 // helpers (mismos que los originales)
 func detectDocker(ctx context.Context) response.DockerStatus {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
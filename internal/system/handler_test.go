@@ -32,3 +32,15 @@ func TestSystemHandler_Status_ReturnsJSON(t *testing.T) {
     assert.Contains(t, w.Body.String(), "\"docker\"")
     assert.Contains(t, w.Body.String(), "\"kubernetes\"")
 }
+
+func TestSystemHandler_Prune_VolumesNeedPruner(t *testing.T) {
+    r := gin.New()
+    h := systemapi.NewHandler()
+    r.POST("/prune", h.Prune)
+
+    w := httptest.NewRecorder()
+    r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/prune?volumes=true", nil))
+
+    // Refused before anything is pruned.
+    assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
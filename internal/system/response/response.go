@@ -17,3 +17,21 @@ type KubernetesStatus struct {
 	ServerInfo string `json:"server_info,omitempty"`
 	Error      string `json:"error,omitempty"`
 }
+
+// Event is one Docker event as streamed by /api/system/events.
+type Event struct {
+	Type     string     `json:"type"`
+	Action   string     `json:"action"`
+	Actor    EventActor `json:"actor"`
+	Scope    string     `json:"scope,omitempty"`
+	Time     int64      `json:"time"`
+	TimeNano int64      `json:"time_nano"`
+}
+
+// EventActor is the object the event is about. Name repeats the "name"
+// attribute, which most actors carry.
+type EventActor struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes"`
+}
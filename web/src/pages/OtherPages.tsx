@@ -47,7 +47,7 @@ export function SettingsPage() {
   const [pruneResult, setPruneResult] = useState<PruneResult | null>(null)
 
   const systemPrune = async () => {
-    if (!window.confirm('This will remove all stopped containers, unused images, volumes and networks. Continue?')) return
+    if (!window.confirm('This will remove all stopped containers, unused images and networks. Volumes are kept. Continue?')) return
     setPruning(true)
     setPruneResult(null)
     try {
@@ -108,7 +108,7 @@ export function SettingsPage() {
         <SettingsSection title="Danger Zone" description="Irreversible system actions">
           <SettingRow
             title="System prune"
-            description="Remove all stopped containers, unused images, volumes, and networks"
+            description="Remove all stopped containers, unused images, and networks (volumes are kept)"
             control={
               <Button variant="danger" size="sm" onClick={systemPrune} disabled={pruning}>
                 {pruning ? <><Loader2 className="w-3 h-3 animate-spin" /> Pruning…</> : 'Prune all'}